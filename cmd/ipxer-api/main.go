@@ -6,19 +6,16 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sync/atomic"
 	"time"
 
 	"github.com/alexandremahdhaoui/ipxer/internal/util/httputil"
-	ipxerv1alpha1 "github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/runtime"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/kubeutil"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
-
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/alexandremahdhaoui/ipxer/pkg/generated/ipxerserver"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
@@ -32,7 +29,14 @@ const (
 	Name             = "ipxer-api"
 	ConfigPathEnvKey = "IPXER_CONFIG_PATH"
 
-	KubeconfigFromServiceAccount = ">>> Kubeconfig From Service Account"
+	defaultContentMaxConcurrency = 8
+	defaultContentTimeoutSeconds = 30
+	defaultOCIBlobCacheDirName   = "ipxer-oci-blobs"
+
+	defaultHTTPResponseCacheSizeBytes = 256 << 20
+
+	defaultGitCacheDirName         = "ipxer-git"
+	defaultGitFetchIntervalSeconds = 60
 )
 
 var (
@@ -51,6 +55,49 @@ type Config struct {
 
 	KubeconfigPath string `json:"kubeconfigPath"`
 
+	// BootstrapParams are the iPXE settings forwarded by the bootstrap script to `/ipxe`, formatted as
+	// `<setting>[:<type>]`, e.g. `mac:hexhyp`, `serial:uristring` or `memsize`. `uuid` and `buildarch` are always
+	// forwarded. Defaults to uuid, buildarch, platform, mac, serial, asset, hostname and ip.
+	BootstrapParams []string `json:"bootstrapParams"`
+
+	// ContentResolution bounds the resolution and transformation of the contents of a profile.
+	ContentResolution struct {
+		// MaxConcurrency is the maximum number of contents of a profile resolved or transformed concurrently.
+		// Defaults to 8.
+		MaxConcurrency int `json:"maxConcurrency"`
+		// TimeoutSeconds is the timeout of the resolution, or the transformation, of a content. Defaults to 30.
+		TimeoutSeconds int `json:"timeoutSeconds"`
+	} `json:"contentResolution"`
+
+	// HTTP configures the resolution of contents from plain remote files.
+	HTTP struct {
+		// ResponseCacheSizeBytes is the maximum size of the responses kept to be revalidated, the least recently used
+		// ones being evicted first. Defaults to 256MiB.
+		ResponseCacheSizeBytes int64 `json:"responseCacheSizeBytes"`
+	} `json:"http"`
+
+	// OCI configures the resolution of contents from OCI artifacts.
+	OCI struct {
+		// BlobCacheDirectory is the directory caching the pulled blobs. Defaults to `<temp dir>/ipxer-oci-blobs`.
+		BlobCacheDirectory string `json:"blobCacheDirectory"`
+	} `json:"oci"`
+
+	// Git configures the resolution of contents from Git repositories.
+	Git struct {
+		// CacheDirectory is the directory holding the bare clones of the repositories. Defaults to
+		// `<temp dir>/ipxer-git`.
+		CacheDirectory string `json:"cacheDirectory"`
+		// FetchIntervalSeconds is the minimum interval between two fetches of a repository. Defaults to 60.
+		FetchIntervalSeconds int `json:"fetchIntervalSeconds"`
+	} `json:"git"`
+
+	// Webhook configures the calls to the webhooks of resolvers and transformers.
+	Webhook struct {
+		// DisableTLSInsecureSkipVerify enforces TLS verification for every webhook, regardless of the
+		// tlsInsecureSkipVerify of their mTLSRef.
+		DisableTLSInsecureSkipVerify bool `json:"disableTLSInsecureSkipVerify"`
+	} `json:"webhook"`
+
 	// ProbesServer
 	ProbesServer struct {
 		LivenessPath  string `json:"livenessPath"`
@@ -105,15 +152,59 @@ func main() {
 		gs.Shutdown(1)
 	}
 
+	if config.ContentResolution.MaxConcurrency <= 0 {
+		config.ContentResolution.MaxConcurrency = defaultContentMaxConcurrency
+	}
+
+	if config.ContentResolution.TimeoutSeconds <= 0 {
+		config.ContentResolution.TimeoutSeconds = defaultContentTimeoutSeconds
+	}
+
+	if config.HTTP.ResponseCacheSizeBytes <= 0 {
+		config.HTTP.ResponseCacheSizeBytes = defaultHTTPResponseCacheSizeBytes
+	}
+
+	if config.OCI.BlobCacheDirectory == "" {
+		config.OCI.BlobCacheDirectory = filepath.Join(os.TempDir(), defaultOCIBlobCacheDirName)
+	}
+
+	if config.Git.CacheDirectory == "" {
+		config.Git.CacheDirectory = filepath.Join(os.TempDir(), defaultGitCacheDirName)
+	}
+
+	if config.Git.FetchIntervalSeconds <= 0 {
+		config.Git.FetchIntervalSeconds = defaultGitFetchIntervalSeconds
+	}
+
+	var bootstrapParams []types.IPXEParam
+	if len(config.BootstrapParams) > 0 {
+		if bootstrapParams, err = types.ParseIPXEParams(config.BootstrapParams); err != nil {
+			slog.ErrorContext(ctx, "parsing bootstrap params", "error", err.Error())
+			gs.Shutdown(1)
+		}
+	}
+
 	// --------------------------------------------- Client --------------------------------------------------------- //
 
-	restConfig, err := newKubeRestConfig(config.KubeconfigPath)
+	restConfig, err := kubeutil.NewRestConfig(config.KubeconfigPath)
 	if err != nil {
 		slog.ErrorContext(ctx, "creating kube rest config", "error", err.Error())
 		gs.Shutdown(1)
 	}
 
-	cl, err := newKubeClient(restConfig)
+	// Assignments and Profiles are served from an informer-backed cache, indexed by the fields the adapters query.
+	informerCache, err := kubeutil.NewCache(restConfig, config.AssignmentNamespace, config.ProfileNamespace)
+	if err != nil {
+		slog.ErrorContext(ctx, "creating informer cache", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	if err := adapter.IndexFields(ctx, informerCache); err != nil {
+		slog.ErrorContext(ctx, "indexing fields", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	cl, err := kubeutil.NewCachedClient(restConfig, informerCache)
 	if err != nil {
 		slog.ErrorContext(ctx, "creating kube client", "error", err.Error())
 		gs.Shutdown(1)
@@ -132,35 +223,63 @@ func main() {
 
 	inlineResolver := adapter.NewInlineResolver()
 	objectRefResolver := adapter.NewObjectRefResolver(dynCl)
-	webhookResolver := adapter.NewWebhookResolver(objectRefResolver)
+	// the credentials of webhooks are watched until ctx is done, and their transports are shared across requests.
+	credentialCache := adapter.NewCredentialCache(ctx, dynCl, config.Webhook.DisableTLSInsecureSkipVerify)
+	webhookClient := adapter.NewWebhookClient(credentialCache)
+	webhookResolver := adapter.NewWebhookResolver(webhookClient)
+	httpResolver := adapter.NewHTTPResolver(credentialCache, config.HTTP.ResponseCacheSizeBytes)
+	s3Resolver := adapter.NewS3Resolver(objectRefResolver)
+	ociResolver := adapter.NewOCIResolver(objectRefResolver, config.OCI.BlobCacheDirectory)
+	gitResolver := adapter.NewGitResolver(
+		objectRefResolver,
+		config.Git.CacheDirectory,
+		time.Duration(config.Git.FetchIntervalSeconds)*time.Second,
+	)
 
 	butaneTransformer := adapter.NewButaneTransformer()
-	webhookTransformer := adapter.NewWebhookTransformer(objectRefResolver)
+	webhookTransformer := adapter.NewWebhookTransformer(webhookClient)
 
 	// --------------------------------------------- Controller ----------------------------------------------------- //
 	var baseURL string
 
+	// the cache metrics are served by the metrics server.
+	contentCache, err := controller.NewContentCache(prometheus.DefaultRegisterer)
+	if err != nil {
+		slog.ErrorContext(ctx, "creating content cache", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
 	mux := controller.NewResolveTransformerMux(
 		baseURL,
 		map[types.ResolverKind]adapter.Resolver{
 			types.InlineResolverKind:    inlineResolver,
 			types.ObjectRefResolverKind: objectRefResolver,
 			types.WebhookResolverKind:   webhookResolver,
+			types.HTTPResolverKind:      httpResolver,
+			types.S3ResolverKind:        s3Resolver,
+			types.OCIResolverKind:       ociResolver,
+			types.GitResolverKind:       gitResolver,
 		},
 		map[types.TransformerKind]adapter.Transformer{
 			types.ButaneTransformerKind:  butaneTransformer,
 			types.WebhookTransformerKind: webhookTransformer,
 		},
+		contentCache,
+		config.ContentResolution.MaxConcurrency,
+		time.Duration(config.ContentResolution.TimeoutSeconds)*time.Second,
 	)
 
-	ipxe := controller.NewIPXE(assignment, profile, mux)
+	ipxe := controller.NewIPXE(assignment, profile, mux, bootstrapParams)
 	content := controller.NewContent(profile, mux)
 
 	// --------------------------------------------- App ------------------------------------------------------------ //
 
 	ipxerHandler := ipxerserver.Handler(ipxerserver.NewStrictHandler(
 		server.New(ipxe, content),
-		nil, // TODO: prometheus middleware
+		[]ipxerserver.StrictMiddlewareFunc{
+			server.QueryMiddleware,
+			// TODO: prometheus middleware
+		},
 	))
 
 	ipxerServer := &http.Server{ //nolint:exhaustruct
@@ -184,14 +303,21 @@ func main() {
 	// --------------------------------------------- Probes --------------------------------------------------------- //
 
 	probesHandler := http.NewServeMux()
+	cacheSynced := new(atomic.Bool)
 
 	probesHandler.Handle(config.ProbesServer.LivenessPath, http.HandlerFunc(
 		func(w http.ResponseWriter, _ *http.Request) {
 			w.WriteHeader(http.StatusOK)
 		}))
 
+	// ready once the informer cache is synced.
 	probesHandler.Handle(config.ProbesServer.ReadinessPath, http.HandlerFunc(
 		func(w http.ResponseWriter, _ *http.Request) {
+			if !cacheSynced.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
 			w.WriteHeader(http.StatusOK)
 		}))
 
@@ -201,6 +327,29 @@ func main() {
 		ReadHeaderTimeout: time.Second,
 	}
 
+	// --------------------------------------------- Run Cache ------------------------------------------------------ //
+
+	gs.WaitGroup().Add(1)
+
+	go func() {
+		defer gs.WaitGroup().Done()
+
+		if err := informerCache.Start(ctx); err != nil {
+			slog.ErrorContext(ctx, "running informer cache", "error", err.Error())
+			go gs.Shutdown(1)
+		}
+	}()
+
+	go func() {
+		if !informerCache.WaitForCacheSync(ctx) {
+			slog.ErrorContext(ctx, "waiting for informer cache to sync")
+			return
+		}
+
+		cacheSynced.Store(true)
+		slog.InfoContext(ctx, "informer cache synced")
+	}()
+
 	// --------------------------------------------- Run Server ----------------------------------------------------- //
 
 	httputil.Serve(map[string]*http.Server{
@@ -211,42 +360,3 @@ func main() {
 
 	slog.Info("✅ gracefully stopped", "binary", Name)
 }
-
-// ------------------------------------------------- Helpers -------------------------------------------------------- //
-
-func newKubeRestConfig(kubeconfigPath string) (*rest.Config, error) {
-	if kubeconfigPath == KubeconfigFromServiceAccount {
-		return rest.InClusterConfig() // TODO: wrap err
-	}
-
-	b, err := os.ReadFile(kubeconfigPath)
-	if err != nil {
-		return nil, err // TODO: wrap err
-	}
-
-	restConfig, err := clientcmd.RESTConfigFromKubeConfig(b)
-	if err != nil {
-		return nil, err // TODO: wrap err
-	}
-
-	return restConfig, nil
-}
-
-func newKubeClient(restConfig *rest.Config) (client.Client, error) { //nolint:ireturn
-	sch := runtime.NewScheme()
-
-	if err := corev1.AddToScheme(sch); err != nil {
-		return nil, err // TODO: wrap err
-	}
-
-	if err := ipxerv1alpha1.AddToScheme(sch); err != nil {
-		return nil, err // TODO: wrap err
-	}
-
-	cl, err := client.New(restConfig, client.Options{Scheme: sch}) //nolint:exhaustruct
-	if err != nil {
-		return nil, err // TODO: wrap err
-	}
-
-	return cl, nil
-}
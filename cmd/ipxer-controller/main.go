@@ -1 +1,232 @@
 package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/dynamic"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
+	"github.com/alexandremahdhaoui/ipxer/internal/driver/reconciler"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/gracefulshutdown"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/kubeutil"
+)
+
+const (
+	Name             = "ipxer-controller"
+	ConfigPathEnvKey = "IPXER_CONTROLLER_CONFIG_PATH"
+
+	defaultRequeueAfterSeconds = 60
+
+	defaultGitCacheDirName         = "ipxer-git"
+	defaultGitFetchIntervalSeconds = 60
+)
+
+var (
+	Version        = "dev" //nolint:gochecknoglobals // set by ldflags
+	CommitSHA      = "n/a" //nolint:gochecknoglobals // set by ldflags
+	BuildTimestamp = "n/a" //nolint:gochecknoglobals // set by ldflags
+)
+
+type Config struct {
+	// Adapters
+
+	AssignmentNamespace string `json:"assignmentNamespace"`
+	ProfileNamespace    string `json:"profileNamespace"`
+
+	// Kubeconfig
+
+	KubeconfigPath string `json:"kubeconfigPath"`
+
+	// Reconcilers
+
+	// RequeueAfterSeconds is the delay before reconciling a degraded Profile again, as the sources of its additional
+	// content are not watched. Defaults to 60.
+	RequeueAfterSeconds int `json:"requeueAfterSeconds"`
+
+	// Git configures the resolution of contents from Git repositories.
+	Git struct {
+		// CacheDirectory is the directory holding the bare clones of the repositories. Defaults to
+		// `<temp dir>/ipxer-git`.
+		CacheDirectory string `json:"cacheDirectory"`
+		// FetchIntervalSeconds is the minimum interval between two fetches of a repository. Defaults to 60.
+		FetchIntervalSeconds int `json:"fetchIntervalSeconds"`
+	} `json:"git"`
+
+	// LeaderElection
+	LeaderElection struct {
+		Enabled   bool   `json:"enabled"`
+		ID        string `json:"id"`
+		Namespace string `json:"namespace"`
+	} `json:"leaderElection"`
+
+	// ProbesServer
+	ProbesServer struct {
+		LivenessPath  string `json:"livenessPath"`
+		ReadinessPath string `json:"readinessPath"`
+		Port          int    `json:"port"`
+	} `json:"probesServer"`
+
+	// MetricsServer serves metrics at `/metrics`.
+	MetricsServer struct {
+		Port int `json:"port"`
+	} `json:"metricsServer"`
+}
+
+// ------------------------------------------------- Main ----------------------------------------------------------- //
+
+func main() {
+	_, _ = fmt.Fprintf(
+		os.Stdout,
+		"Starting %s version %s (%s) %s\n",
+		Name,
+		Version,
+		CommitSHA,
+		BuildTimestamp,
+	)
+
+	gs := gracefulshutdown.New(Name)
+	ctx := gs.Context()
+
+	ctrl.SetLogger(logr.FromSlogHandler(slog.Default().Handler()))
+
+	// --------------------------------------------- Config --------------------------------------------------------- //
+
+	ipxerConfigPath := os.Getenv(ConfigPathEnvKey)
+	if ipxerConfigPath == "" {
+		slog.ErrorContext(ctx, fmt.Sprintf("environment variable %q must be set", ConfigPathEnvKey))
+		gs.Shutdown(1)
+	}
+
+	b, err := os.ReadFile(ipxerConfigPath)
+	if err != nil {
+		slog.ErrorContext(ctx, "reading ipxer-controller configuration file", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	config := new(Config)
+	if err = json.Unmarshal(b, config); err != nil {
+		slog.ErrorContext(ctx, "parsing ipxer-controller configuration", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	if config.RequeueAfterSeconds <= 0 {
+		config.RequeueAfterSeconds = defaultRequeueAfterSeconds
+	}
+
+	if config.Git.CacheDirectory == "" {
+		config.Git.CacheDirectory = filepath.Join(os.TempDir(), defaultGitCacheDirName)
+	}
+
+	if config.Git.FetchIntervalSeconds <= 0 {
+		config.Git.FetchIntervalSeconds = defaultGitFetchIntervalSeconds
+	}
+
+	// --------------------------------------------- Manager -------------------------------------------------------- //
+
+	restConfig, err := kubeutil.NewRestConfig(config.KubeconfigPath)
+	if err != nil {
+		slog.ErrorContext(ctx, "creating kube rest config", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	scheme, err := kubeutil.NewScheme()
+	if err != nil {
+		slog.ErrorContext(ctx, "creating kube scheme", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{ //nolint:exhaustruct
+		Scheme: scheme,
+		Cache: cache.Options{ //nolint:exhaustruct
+			DefaultNamespaces: map[string]cache.Config{
+				config.AssignmentNamespace: {},
+				config.ProfileNamespace:    {},
+			},
+		},
+		Metrics: metricsserver.Options{ //nolint:exhaustruct
+			BindAddress: fmt.Sprintf(":%d", config.MetricsServer.Port),
+		},
+		HealthProbeBindAddress:  fmt.Sprintf(":%d", config.ProbesServer.Port),
+		LivenessEndpointName:    config.ProbesServer.LivenessPath,
+		ReadinessEndpointName:   config.ProbesServer.ReadinessPath,
+		LeaderElection:          config.LeaderElection.Enabled,
+		LeaderElectionID:        config.LeaderElection.ID,
+		LeaderElectionNamespace: config.LeaderElection.Namespace,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "creating manager", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	dynCl, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		slog.ErrorContext(ctx, "creating dynamic client", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	// --------------------------------------------- Adapter -------------------------------------------------------- //
+
+	// adapters read from the cache of the manager, which must index the fields they query.
+	if err := adapter.IndexFields(ctx, mgr.GetFieldIndexer()); err != nil {
+		slog.ErrorContext(ctx, "indexing fields", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	assignment := adapter.NewAssignment(mgr.GetClient(), config.AssignmentNamespace)
+	profile := adapter.NewProfile(mgr.GetClient(), config.ProfileNamespace)
+	objectRefResolver := adapter.NewObjectRefResolver(dynCl)
+	gitResolver := adapter.NewGitResolver(
+		objectRefResolver,
+		config.Git.CacheDirectory,
+		time.Duration(config.Git.FetchIntervalSeconds)*time.Second,
+	)
+
+	// --------------------------------------------- Reconcilers ---------------------------------------------------- //
+
+	if err := reconciler.NewAssignment(mgr.GetClient(), assignment, profile).SetupWithManager(mgr); err != nil {
+		slog.ErrorContext(ctx, "setting up assignment reconciler", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	if err := reconciler.NewProfile(
+		mgr.GetClient(),
+		profile,
+		objectRefResolver,
+		gitResolver,
+		time.Duration(config.RequeueAfterSeconds)*time.Second,
+	).SetupWithManager(mgr); err != nil {
+		slog.ErrorContext(ctx, "setting up profile reconciler", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	// --------------------------------------------- Probes --------------------------------------------------------- //
+
+	if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		slog.ErrorContext(ctx, "adding liveness check", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	if err := mgr.AddReadyzCheck("ping", healthz.Ping); err != nil {
+		slog.ErrorContext(ctx, "adding readiness check", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	// --------------------------------------------- Run Manager ---------------------------------------------------- //
+
+	if err := mgr.Start(ctx); err != nil {
+		slog.ErrorContext(ctx, "running manager", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	slog.Info("✅ gracefully stopped", "binary", Name)
+}
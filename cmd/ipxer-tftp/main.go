@@ -1 +1,389 @@
 package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
+	"github.com/alexandremahdhaoui/ipxer/internal/controller"
+	"github.com/alexandremahdhaoui/ipxer/internal/driver/proxydhcp"
+	"github.com/alexandremahdhaoui/ipxer/internal/driver/tftp"
+	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/gracefulshutdown"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/httputil"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/kubeutil"
+	"github.com/alexandremahdhaoui/ipxer/pkg/constants"
+	"github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
+)
+
+const (
+	Name             = "ipxer-tftp"
+	ConfigPathEnvKey = "IPXER_TFTP_CONFIG_PATH"
+)
+
+var (
+	Version        = "dev" //nolint:gochecknoglobals // set by ldflags
+	CommitSHA      = "n/a" //nolint:gochecknoglobals // set by ldflags
+	BuildTimestamp = "n/a" //nolint:gochecknoglobals // set by ldflags
+)
+
+type Config struct {
+	// Bootloaders
+
+	// BootloaderDirectory is the directory holding the iPXE binaries, organized as `<buildarch>/<filename>`.
+	// It takes precedence over BootloaderConfigMap.
+	BootloaderDirectory string `json:"bootloaderDirectory"`
+
+	// BootloaderConfigMap holds the iPXE binaries under the key `<buildarch>.<filename>`.
+	BootloaderConfigMap struct {
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+	} `json:"bootloaderConfigMap"`
+
+	// IPXERBaseURL is the base URL of the ipxer-api, e.g. `http://ipxer.example.com`. When set, the served bootloaders
+	// embed a script chainloading to `<ipxerBaseURL>/ipxe`. Bootloaders must then be built with the placeholder script
+	// defined by controller.EmbeddedScriptPlaceholder.
+	IPXERBaseURL string `json:"ipxerBaseURL"`
+
+	// BootstrapParams are the iPXE settings forwarded by the embedded script to `/ipxe`. See the ipxer-api configuration.
+	BootstrapParams []string `json:"bootstrapParams"`
+
+	// Kubeconfig
+
+	KubeconfigPath string `json:"kubeconfigPath"`
+
+	// ProbesServer
+	ProbesServer struct {
+		LivenessPath  string `json:"livenessPath"`
+		ReadinessPath string `json:"readinessPath"`
+		Port          int    `json:"port"`
+	} `json:"probesServer"`
+
+	// MetricsServer
+	MetricsServer struct {
+		Path string `json:"path"`
+		Port int    `json:"port"`
+	} `json:"metricsServer"`
+
+	// TFTPServer
+	TFTPServer struct {
+		Port int `json:"port"`
+		// TimeoutSeconds is the default retransmission timeout.
+		TimeoutSeconds int `json:"timeoutSeconds"`
+		// Retries is the number of retransmissions before aborting a transfer.
+		Retries int `json:"retries"`
+		// MaxWindowSize caps the RFC 7440 windowsize negotiated by clients.
+		MaxWindowSize int `json:"maxWindowSize"`
+	} `json:"tftpServer"`
+
+	// ProxyDHCPServer answers PXE clients alongside the existing DHCP server, i.e. without leasing addresses. PXE
+	// clients are handed the bootloader matching their architecture, and iPXE clients `<ipxerBaseURL>/boot.ipxe`.
+	ProxyDHCPServer struct {
+		Enabled bool `json:"enabled"`
+		// ServerIP is the IPv4 address of this server on the PXE network. It is also used as the TFTP server address
+		// unless NextServer is set.
+		ServerIP   string `json:"serverIP"`
+		NextServer string `json:"nextServer"`
+		// Port receives broadcast DHCP requests. Defaults to 67.
+		Port int `json:"port"`
+		// PXEPort receives PXE boot server requests. Defaults to 4011.
+		PXEPort int `json:"pxePort"`
+		// Bootfiles overrides the bootloader filename served per firmware platform, e.g. `{"efi": "snponly.efi"}`.
+		Bootfiles map[v1alpha1.Platform]string `json:"bootfiles"`
+	} `json:"proxyDHCPServer"`
+}
+
+// ------------------------------------------------- Main ----------------------------------------------------------- //
+
+func main() {
+	_, _ = fmt.Fprintf(
+		os.Stdout,
+		"Starting %s version %s (%s) %s\n",
+		Name,
+		Version,
+		CommitSHA,
+		BuildTimestamp,
+	)
+
+	gs := gracefulshutdown.New(Name)
+	ctx := gs.Context()
+
+	// --------------------------------------------- Config --------------------------------------------------------- //
+
+	configPath := os.Getenv(ConfigPathEnvKey)
+	if configPath == "" {
+		slog.ErrorContext(ctx, fmt.Sprintf("environment variable %q must be set", ConfigPathEnvKey))
+		gs.Shutdown(1)
+	}
+
+	b, err := os.ReadFile(configPath)
+	if err != nil {
+		slog.ErrorContext(ctx, "reading ipxer-tftp configuration file", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	config := new(Config)
+	if err = json.Unmarshal(b, config); err != nil {
+		slog.ErrorContext(ctx, "parsing ipxer-tftp configuration", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	// --------------------------------------------- Adapter -------------------------------------------------------- //
+
+	var (
+		bootloaderAdapter adapter.Bootloader
+		informerCache     cache.Cache
+	)
+
+	if config.BootloaderDirectory != "" {
+		bootloaderAdapter = adapter.NewDirectoryBootloader(config.BootloaderDirectory)
+	} else {
+		restConfig, err := kubeutil.NewRestConfig(config.KubeconfigPath)
+		if err != nil {
+			slog.ErrorContext(ctx, "creating kube rest config", "error", err.Error())
+			gs.Shutdown(1)
+		}
+
+		// the bootloaders are read from an informer watching the ConfigMap, hence TFTP requests do not reach the API
+		// server.
+		informerCache, err = kubeutil.NewConfigMapCache(
+			restConfig,
+			config.BootloaderConfigMap.Namespace,
+			config.BootloaderConfigMap.Name,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "creating informer cache", "error", err.Error())
+			gs.Shutdown(1)
+		}
+
+		// the informer is created before the cache starts, hence the cache is synced once the ConfigMap is listed.
+		if _, err := informerCache.GetInformer(ctx, &corev1.ConfigMap{}); err != nil {
+			slog.ErrorContext(ctx, "creating configmap informer", "error", err.Error())
+			gs.Shutdown(1)
+		}
+
+		cl, err := kubeutil.NewCachedClient(restConfig, informerCache)
+		if err != nil {
+			slog.ErrorContext(ctx, "creating kube client", "error", err.Error())
+			gs.Shutdown(1)
+		}
+
+		bootloaderAdapter = adapter.NewConfigMapBootloader(
+			cl,
+			config.BootloaderConfigMap.Namespace,
+			config.BootloaderConfigMap.Name,
+		)
+	}
+
+	// --------------------------------------------- Controller ----------------------------------------------------- //
+
+	var bootstrapParams []types.IPXEParam
+	if len(config.BootstrapParams) > 0 {
+		if bootstrapParams, err = types.ParseIPXEParams(config.BootstrapParams); err != nil {
+			slog.ErrorContext(ctx, "parsing bootstrap params", "error", err.Error())
+			gs.Shutdown(1)
+		}
+	}
+
+	bootloader := controller.NewBootloader(bootloaderAdapter, config.IPXERBaseURL, bootstrapParams)
+
+	// --------------------------------------------- App ------------------------------------------------------------ //
+
+	tftpServer := tftp.New(fmt.Sprintf(":%d", config.TFTPServer.Port), bootloader)
+
+	if config.TFTPServer.TimeoutSeconds > 0 {
+		tftpServer.Timeout = time.Duration(config.TFTPServer.TimeoutSeconds) * time.Second
+	}
+
+	if config.TFTPServer.Retries > 0 {
+		tftpServer.Retries = config.TFTPServer.Retries
+	}
+
+	if config.TFTPServer.MaxWindowSize > 0 {
+		tftpServer.MaxWindowSize = config.TFTPServer.MaxWindowSize
+	}
+
+	var proxyDHCPServer *proxydhcp.Server
+
+	if config.ProxyDHCPServer.Enabled {
+		proxyDHCPServer, err = newProxyDHCPServer(config)
+		if err != nil {
+			slog.ErrorContext(ctx, "creating proxydhcp server", "error", err.Error())
+			gs.Shutdown(1)
+		}
+	}
+
+	// --------------------------------------------- Metrics -------------------------------------------------------- //
+
+	metricsHandler := http.NewServeMux()
+	metricsHandler.Handle(config.MetricsServer.Path, promhttp.Handler())
+
+	metrics := &http.Server{ //nolint:exhaustruct
+		Addr:              fmt.Sprintf(":%d", config.MetricsServer.Port),
+		Handler:           metricsHandler,
+		ReadHeaderTimeout: time.Second,
+	}
+
+	// --------------------------------------------- Probes --------------------------------------------------------- //
+
+	probesHandler := http.NewServeMux()
+	cacheSynced := new(atomic.Bool)
+
+	probesHandler.Handle(config.ProbesServer.LivenessPath, http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	// ready once the tftp server is bound and the bootloader informer cache, if any, is synced.
+	probesHandler.Handle(config.ProbesServer.ReadinessPath, http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			if !tftpServer.Ready() || (informerCache != nil && !cacheSynced.Load()) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	probes := &http.Server{ //nolint:exhaustruct
+		Addr:              fmt.Sprintf(":%d", config.ProbesServer.Port),
+		Handler:           probesHandler,
+		ReadHeaderTimeout: time.Second,
+	}
+
+	// --------------------------------------------- Run Cache ------------------------------------------------------ //
+
+	if informerCache != nil {
+		gs.WaitGroup().Add(1)
+
+		go func() {
+			defer gs.WaitGroup().Done()
+
+			if err := informerCache.Start(ctx); err != nil {
+				slog.ErrorContext(ctx, "running informer cache", "error", err.Error())
+				go gs.Shutdown(1)
+			}
+		}()
+
+		go func() {
+			if !informerCache.WaitForCacheSync(ctx) {
+				slog.ErrorContext(ctx, "waiting for informer cache to sync")
+				return
+			}
+
+			cacheSynced.Store(true)
+			slog.InfoContext(ctx, "informer cache synced")
+		}()
+	}
+
+	// --------------------------------------------- Run Server ----------------------------------------------------- //
+
+	serveUDP("tftp", tftpServer, tftp.ErrServerClosed, gs)
+
+	if proxyDHCPServer != nil {
+		serveUDP("proxydhcp", proxyDHCPServer, proxydhcp.ErrServerClosed, gs)
+	}
+
+	httputil.Serve(map[string]*http.Server{
+		"metrics": metrics,
+		"probes":  probes,
+	}, gs)
+
+	slog.Info("✅ gracefully stopped", "binary", Name)
+}
+
+// ------------------------------------------------- Helpers -------------------------------------------------------- //
+
+var errInvalidIPv4 = errors.New("invalid ipv4 address")
+
+func newProxyDHCPServer(config *Config) (*proxydhcp.Server, error) {
+	serverIP := net.ParseIP(config.ProxyDHCPServer.ServerIP).To4()
+	if serverIP == nil {
+		return nil, errors.Join(fmt.Errorf("serverIP %q", config.ProxyDHCPServer.ServerIP), errInvalidIPv4)
+	}
+
+	var ipxeBootURL string
+	if config.IPXERBaseURL != "" {
+		ipxeBootURL = strings.TrimSuffix(config.IPXERBaseURL, "/") + "/boot.ipxe"
+	}
+
+	server := proxydhcp.New(serverIP, ipxeBootURL)
+
+	if config.ProxyDHCPServer.NextServer != "" {
+		server.NextServer = net.ParseIP(config.ProxyDHCPServer.NextServer).To4()
+		if server.NextServer == nil {
+			return nil, errors.Join(fmt.Errorf("nextServer %q", config.ProxyDHCPServer.NextServer), errInvalidIPv4)
+		}
+	}
+
+	if config.ProxyDHCPServer.Port > 0 {
+		server.Addr = fmt.Sprintf(":%d", config.ProxyDHCPServer.Port)
+	}
+
+	if config.ProxyDHCPServer.PXEPort > 0 {
+		server.PXEAddr = fmt.Sprintf(":%d", config.ProxyDHCPServer.PXEPort)
+	}
+
+	if len(config.ProxyDHCPServer.Bootfiles) > 0 {
+		server.Bootfiles = maps.Clone(proxydhcp.DefaultBootfiles)
+		maps.Copy(server.Bootfiles, config.ProxyDHCPServer.Bootfiles)
+	}
+
+	return server, nil
+}
+
+type udpServer interface {
+	ListenAndServe() error
+	Shutdown(ctx context.Context) error
+}
+
+// serveUDP runs a udp server in the background following the same lifecycle as httputil.Serve.
+func serveUDP(name string, server udpServer, errServerClosed error, gs *gracefulshutdown.GracefulShutdown) {
+	ctx := context.WithValue(gs.Context(), constants.ServerNameContextKey, name)
+
+	gs.WaitGroup().Add(1)
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, errServerClosed) {
+			slog.ErrorContext(ctx, "❌ received error", "error", err)
+
+			// we need to call Done() before requesting the shutdown. Otherwise, the WaitGroup will never decrement.
+			gs.WaitGroup().Done()
+			gs.Shutdown(1)
+
+			return
+		}
+
+		gs.WaitGroup().Done()
+		gs.Shutdown(0)
+	}()
+
+	go func() {
+		<-gs.Context().Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.ErrorContext(ctx, "❌ received error while shutting down server", "error", err)
+
+			return
+		}
+
+		slog.Info("✅ gracefully shut down server", "server", name)
+	}()
+}
@@ -1,12 +1,28 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
+	"github.com/alexandremahdhaoui/ipxer/internal/driver/webhook"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/gracefulshutdown"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/kubeutil"
+	"github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
 )
 
 const (
-	Name = "ipxer-webhook"
+	Name             = "ipxer-webhook"
+	ConfigPathEnvKey = "IPXER_WEBHOOK_CONFIG_PATH"
 )
 
 var (
@@ -15,11 +31,166 @@ var (
 	BuildTimestamp = "n/a" //nolint:gochecknoglobals // set by ldflags
 )
 
+type Config struct {
+	// Adapters
+
+	AssignmentNamespace string `json:"assignmentNamespace"`
+	ProfileNamespace    string `json:"profileNamespace"`
+
+	// Kubeconfig
+
+	KubeconfigPath string `json:"kubeconfigPath"`
+
+	// WebhookServer
+	WebhookServer struct {
+		Port int `json:"port"`
+		// CertDir is the directory holding the serving certificate and key, e.g. mounted from a cert-manager Secret.
+		// Changes to these files are loaded without restarting the server.
+		CertDir string `json:"certDir"`
+		// CertName defaults to `tls.crt`.
+		CertName string `json:"certName"`
+		// KeyName defaults to `tls.key`.
+		KeyName string `json:"keyName"`
+	} `json:"webhookServer"`
+
+	// ProbesServer
+	ProbesServer struct {
+		LivenessPath  string `json:"livenessPath"`
+		ReadinessPath string `json:"readinessPath"`
+		Port          int    `json:"port"`
+	} `json:"probesServer"`
+
+	// MetricsServer serves metrics at `/metrics`.
+	MetricsServer struct {
+		Port int `json:"port"`
+	} `json:"metricsServer"`
+}
+
 // ------------------------------------------------- Main ----------------------------------------------------------- //
 
 func main() {
 	_, _ = fmt.Fprintf(os.Stdout, "Starting %s version %s (%s) %s\n", Name, Version, CommitSHA, BuildTimestamp)
 
-	// TODO: implement me
-	panic("implement me")
+	gs := gracefulshutdown.New(Name)
+	ctx := gs.Context()
+
+	ctrl.SetLogger(logr.FromSlogHandler(slog.Default().Handler()))
+
+	// --------------------------------------------- Config --------------------------------------------------------- //
+
+	ipxerConfigPath := os.Getenv(ConfigPathEnvKey)
+	if ipxerConfigPath == "" {
+		slog.ErrorContext(ctx, fmt.Sprintf("environment variable %q must be set", ConfigPathEnvKey))
+		gs.Shutdown(1)
+	}
+
+	b, err := os.ReadFile(ipxerConfigPath)
+	if err != nil {
+		slog.ErrorContext(ctx, "reading ipxer-webhook configuration file", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	config := new(Config)
+	if err = json.Unmarshal(b, config); err != nil {
+		slog.ErrorContext(ctx, "parsing ipxer-webhook configuration", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	// --------------------------------------------- Manager -------------------------------------------------------- //
+
+	restConfig, err := kubeutil.NewRestConfig(config.KubeconfigPath)
+	if err != nil {
+		slog.ErrorContext(ctx, "creating kube rest config", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	scheme, err := kubeutil.NewScheme()
+	if err != nil {
+		slog.ErrorContext(ctx, "creating kube scheme", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	// The webhook server watches the certificate and key files in CertDir and reloads them on change.
+	webhookServer := ctrlwebhook.NewServer(ctrlwebhook.Options{ //nolint:exhaustruct
+		Port:     config.WebhookServer.Port,
+		CertDir:  config.WebhookServer.CertDir,
+		CertName: config.WebhookServer.CertName,
+		KeyName:  config.WebhookServer.KeyName,
+	})
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{ //nolint:exhaustruct
+		Scheme: scheme,
+		Cache: cache.Options{ //nolint:exhaustruct
+			DefaultNamespaces: map[string]cache.Config{
+				config.AssignmentNamespace: {},
+				config.ProfileNamespace:    {},
+			},
+		},
+		WebhookServer: webhookServer,
+		Metrics: metricsserver.Options{ //nolint:exhaustruct
+			BindAddress: fmt.Sprintf(":%d", config.MetricsServer.Port),
+		},
+		HealthProbeBindAddress: fmt.Sprintf(":%d", config.ProbesServer.Port),
+		LivenessEndpointName:   config.ProbesServer.LivenessPath,
+		ReadinessEndpointName:  config.ProbesServer.ReadinessPath,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "creating manager", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	// --------------------------------------------- Adapter -------------------------------------------------------- //
+
+	// adapters read from the cache of the manager, which must index the fields they query.
+	if err := adapter.IndexFields(ctx, mgr.GetFieldIndexer()); err != nil {
+		slog.ErrorContext(ctx, "indexing fields", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	assignment := adapter.NewAssignment(mgr.GetClient(), config.AssignmentNamespace)
+	profile := adapter.NewProfile(mgr.GetClient(), config.ProfileNamespace)
+
+	// --------------------------------------------- Webhooks ------------------------------------------------------- //
+
+	assignmentWebhook := webhook.NewAssignment(assignment, profile)
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.Assignment{}).
+		WithDefaulter(assignmentWebhook).
+		WithValidator(assignmentWebhook).
+		Complete(); err != nil {
+		slog.ErrorContext(ctx, "registering assignment webhook", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	profileWebhook := webhook.NewProfile()
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha1.Profile{}).
+		WithDefaulter(profileWebhook).
+		WithValidator(profileWebhook).
+		Complete(); err != nil {
+		slog.ErrorContext(ctx, "registering profile webhook", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	// --------------------------------------------- Probes --------------------------------------------------------- //
+
+	if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		slog.ErrorContext(ctx, "adding liveness check", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	// ready once the webhook server serves requests.
+	if err := mgr.AddReadyzCheck("webhook", webhookServer.StartedChecker()); err != nil {
+		slog.ErrorContext(ctx, "adding readiness check", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	// --------------------------------------------- Run Manager ---------------------------------------------------- //
+
+	if err := mgr.Start(ctx); err != nil {
+		slog.ErrorContext(ctx, "running manager", "error", err.Error())
+		gs.Shutdown(1)
+	}
+
+	slog.Info("✅ gracefully stopped", "binary", Name)
 }
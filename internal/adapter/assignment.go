@@ -3,11 +3,13 @@ package adapter
 import (
 	"context"
 	"errors"
-
-	"github.com/google/uuid"
+	"fmt"
+	"net"
+	"strings"
 
 	"github.com/alexandremahdhaoui/ipxer/internal/types"
 	"github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
+	"github.com/google/uuid"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -17,13 +19,25 @@ var (
 	errAssignmentFindDefault     = errors.New("finding default assignment")
 	errAssignmentFindBySelectors = errors.New("error finding assignment by selectors")
 	errAssignmentList            = errors.New("listing assignment")
+
+	// ErrInvalidSubjectSelectors is returned when the subject selectors of an Assignment cannot be parsed.
+	ErrInvalidSubjectSelectors = errors.New("invalid subject selectors")
 )
 
 // --------------------------------------------------- INTERFACES --------------------------------------------------- //
 
 type Assignment interface {
-	FindDefaultByBuildarch(ctx context.Context, buildarch string) (types.Assignment, error)
+	// FindDefault returns the default assignment matching the buildarch and platform of the selectors.
+	FindDefault(ctx context.Context, selectors types.IPXESelectors) (types.Assignment, error)
 	FindBySelectors(ctx context.Context, selectors types.IPXESelectors) (types.Assignment, error)
+
+	// ListDefault returns all default assignments matching the buildarch and platform of the selectors. More than one
+	// item implies conflicting default assignments.
+	ListDefault(ctx context.Context, selectors types.IPXESelectors) ([]types.Assignment, error)
+	// ListBySelectors returns all assignments selecting any subject identifier of the selectors, i.e. its UUID, MAC,
+	// serial number, asset tag or hostname, and matching its buildarch and platform. Items are ordered by subject
+	// identifier, in this order of precedence. More than one item implies conflicting assignments.
+	ListBySelectors(ctx context.Context, selectors types.IPXESelectors) ([]types.Assignment, error)
 }
 
 // --------------------------------------------------- CONSTRUCTORS ------------------------------------------------- //
@@ -42,74 +56,170 @@ type assignment struct {
 	namespace string
 }
 
-// --------------------------------------------- FindDefaultByBuildarch ------------------------------------------------- //
+// --------------------------------------------- FindDefault -------------------------------------------------------- //
 
-func (a *assignment) FindDefaultByBuildarch(ctx context.Context, buildarch string) (types.Assignment, error) {
-	// list assignment
-	list := new(v1alpha1.AssignmentList)
-
-	// Get the list of default matching the buildarch
-	if err := a.client.List(ctx, list,
-		buildarchLabelSelector(buildarch),
-		defaultAssignmentLabelSelector(),
-	); err != nil {
-		return types.Assignment{}, errors.Join(err, errAssignmentList, errAssignmentFindDefault)
+func (a *assignment) FindDefault(ctx context.Context, selectors types.IPXESelectors) (types.Assignment, error) {
+	out, err := a.ListDefault(ctx, selectors)
+	if err != nil {
+		return types.Assignment{}, errors.Join(err, errAssignmentFindDefault)
 	}
 
-	if list == nil || len(list.Items) == 0 {
+	if len(out) == 0 {
 		return types.Assignment{}, errors.Join(ErrAssignmentNotFound, errAssignmentFindDefault)
 	}
 
-	return types.Assignment{
-		Name:        list.Items[0].Name,
-		ProfileName: list.Items[0].Spec.ProfileName,
-	}, nil
+	return out[0], nil
 }
 
 // --------------------------------------------- FindBySelectors --------------------------------------------- //
 
 func (a *assignment) FindBySelectors(ctx context.Context, selectors types.IPXESelectors) (types.Assignment, error) {
-	// list assignment
-	list := new(v1alpha1.AssignmentList)
-	if err := a.client.List(ctx, list,
-		buildarchLabelSelector(selectors.Buildarch),
-		uuidLabelSelector(selectors.UUID),
-	); err != nil {
-		return types.Assignment{}, errors.Join(err, errAssignmentList, errAssignmentFindBySelectors)
+	out, err := a.ListBySelectors(ctx, selectors)
+	if err != nil {
+		return types.Assignment{}, errors.Join(err, errAssignmentFindBySelectors)
 	}
 
-	if list == nil || len(list.Items) == 0 {
+	if len(out) == 0 {
 		return types.Assignment{}, errors.Join(ErrAssignmentNotFound, errAssignmentFindBySelectors)
 	}
 
-	return types.Assignment{
-		Name:        list.Items[0].Name,
-		ProfileName: list.Items[0].Spec.ProfileName,
-	}, nil
+	return out[0], nil
+}
+
+// --------------------------------------------- ListDefault -------------------------------------------------------- //
+
+func (a *assignment) ListDefault(ctx context.Context, selectors types.IPXESelectors) ([]types.Assignment, error) {
+	// Get the list of default matching the buildarch & platform
+	return a.list(ctx, matchingIndexes(
+		indexSelector{field: AssignmentDefaultIndex, value: indexedTrue},
+		indexSelector{field: AssignmentBuildarchIndex, value: selectors.Buildarch},
+		indexSelector{field: AssignmentPlatformIndex, value: selectors.Platform},
+	))
+}
+
+// --------------------------------------------- ListBySelectors ---------------------------------------------------- //
+
+func (a *assignment) ListBySelectors(ctx context.Context, selectors types.IPXESelectors) ([]types.Assignment, error) {
+	out := make([]types.Assignment, 0)
+	seen := make(map[string]struct{})
+
+	for _, subject := range subjectIndexSelectors(selectors) {
+		list, err := a.list(ctx, matchingIndexes(
+			subject,
+			indexSelector{field: AssignmentBuildarchIndex, value: selectors.Buildarch},
+			indexSelector{field: AssignmentPlatformIndex, value: selectors.Platform},
+		))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range list {
+			if _, ok := seen[item.Name]; ok {
+				continue
+			}
+
+			seen[item.Name] = struct{}{}
+			out = append(out, item)
+		}
+	}
+
+	return out, nil
 }
 
-// --------------------------------------------- UTILS -------------------------------------------------------------- //
-
-func buildarchLabelSelector(buildarch string) client.ListOption {
-	switch v1alpha1.Buildarch(buildarch) {
-	case v1alpha1.Arm32:
-		return client.HasLabels{v1alpha1.Arm32BuildarchLabelSelector}
-	case v1alpha1.Arm64:
-		return client.HasLabels{v1alpha1.Arm64BuildarchLabelSelector}
-	case v1alpha1.I386:
-		return client.HasLabels{v1alpha1.I386BuildarchLabelSelector}
-	case v1alpha1.X8664:
-		return client.HasLabels{v1alpha1.X8664BuildarchLabelSelector}
-	default:
-		// not specifying anything implies any buildarch
-		return nil
+// subjectIndexSelectors returns the index selectors of the subject identifiers specified in the selectors, in order of
+// precedence. A nil UUID is ignored, as many subjects report it.
+func subjectIndexSelectors(selectors types.IPXESelectors) []indexSelector {
+	out := make([]indexSelector, 0)
+
+	if selectors.UUID != uuid.Nil {
+		out = append(out, indexSelector{field: AssignmentUUIDIndex, value: selectors.UUID.String()})
+	}
+
+	if len(selectors.MAC) > 0 {
+		out = append(out, indexSelector{
+			field: AssignmentMACIndex,
+			value: v1alpha1.LabelSelectorName(v1alpha1.NewMACLabelSelector(selectors.MAC)),
+		})
+	}
+
+	if strings.TrimSpace(selectors.Serial) != "" {
+		out = append(out, indexSelector{
+			field: AssignmentSerialIndex,
+			value: v1alpha1.LabelSelectorName(v1alpha1.NewSerialLabelSelector(selectors.Serial)),
+		})
+	}
+
+	if strings.TrimSpace(selectors.Asset) != "" {
+		out = append(out, indexSelector{
+			field: AssignmentAssetIndex,
+			value: v1alpha1.LabelSelectorName(v1alpha1.NewAssetLabelSelector(selectors.Asset)),
+		})
 	}
+
+	if strings.TrimSpace(selectors.Hostname) != "" {
+		out = append(out, indexSelector{
+			field: AssignmentHostnameIndex,
+			value: v1alpha1.LabelSelectorName(v1alpha1.NewHostnameLabelSelector(selectors.Hostname)),
+		})
+	}
+
+	return out
 }
 
-func defaultAssignmentLabelSelector() client.ListOption {
-	return client.HasLabels{v1alpha1.DefaultAssignmentLabel}
+func (a *assignment) list(ctx context.Context, opts ...client.ListOption) ([]types.Assignment, error) {
+	list := new(v1alpha1.AssignmentList)
+	if err := a.client.List(ctx, list, append(opts, client.InNamespace(a.namespace))...); err != nil {
+		return nil, errors.Join(err, errAssignmentList)
+	}
+
+	out := make([]types.Assignment, 0, len(list.Items))
+	for _, item := range list.Items {
+		out = append(out, types.Assignment{
+			Name:        item.Name,
+			ProfileName: item.Spec.ProfileName,
+		})
+	}
+
+	return out, nil
 }
 
-func uuidLabelSelector(id uuid.UUID) client.ListOption {
-	return client.HasLabels{v1alpha1.NewUUIDLabelSelector(id)}
+// ------------------------------------------------ SubjectSelectors ------------------------------------------------ //
+
+// SubjectSelectors returns the selectors of each subject identifier specified by the assignment, i.e. one item per UUID,
+// MAC address, serial number, asset tag and hostname. Buildarch and platform are left unspecified.
+func SubjectSelectors(obj *v1alpha1.Assignment) ([]types.IPXESelectors, error) {
+	spec := obj.Spec.SubjectSelectors
+	out := make([]types.IPXESelectors, 0)
+
+	for _, s := range spec.UUIDList {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("parsing uuid %q", s), ErrInvalidSubjectSelectors)
+		}
+
+		out = append(out, types.IPXESelectors{UUID: id})
+	}
+
+	for _, s := range spec.MACList {
+		mac, err := net.ParseMAC(s)
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("parsing mac %q", s), ErrInvalidSubjectSelectors)
+		}
+
+		out = append(out, types.IPXESelectors{MAC: mac})
+	}
+
+	for _, s := range spec.SerialList {
+		out = append(out, types.IPXESelectors{Serial: s})
+	}
+
+	for _, s := range spec.AssetList {
+		out = append(out, types.IPXESelectors{Asset: s})
+	}
+
+	for _, s := range spec.HostnameList {
+		out = append(out, types.IPXESelectors{Hostname: s})
+	}
+
+	return out, nil
 }
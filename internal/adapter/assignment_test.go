@@ -4,6 +4,7 @@ package adapter_test
 
 import (
 	"context"
+	"net"
 	"testing"
 
 	"github.com/alexandremahdhaoui/ipxer/internal/types"
@@ -14,7 +15,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestAssignment(t *testing.T) {
@@ -22,8 +28,8 @@ func TestAssignment(t *testing.T) {
 		ctx       context.Context
 		namespace string
 
-		inputBuildarch                 string
-		expectedBuildarchLabelSelector string
+		inputBuildarch string
+		inputPlatform  string
 
 		expectedAssignment  types.Assignment
 		expectedListOptions []interface{}
@@ -39,7 +45,7 @@ func TestAssignment(t *testing.T) {
 		namespace = "test-assignment"
 
 		inputBuildarch = string(v1alpha1.Arm64)
-		expectedBuildarchLabelSelector = v1alpha1.Arm64BuildarchLabelSelector
+		inputPlatform = string(v1alpha1.EFI)
 
 		cl = mockclient.NewMockClient(t)
 		assignment = adapter.NewAssignment(cl, namespace)
@@ -51,6 +57,10 @@ func TestAssignment(t *testing.T) {
 		}
 	}
 
+	matchingFields := func(terms ...fields.Selector) client.ListOption {
+		return client.MatchingFieldsSelector{Selector: fields.AndSelectors(terms...)}
+	}
+
 	list := func(t *testing.T) {
 		t.Helper()
 
@@ -65,7 +75,7 @@ func TestAssignment(t *testing.T) {
 			})
 	}
 
-	t.Run("FindDefaultByBuildarch", func(t *testing.T) {
+	t.Run("FindDefault", func(t *testing.T) {
 		t.Run("Success", func(t *testing.T) {
 			defer setup(t)()
 
@@ -75,13 +85,43 @@ func TestAssignment(t *testing.T) {
 			}
 
 			expectedListOptions = []interface{}{
-				client.HasLabels{expectedBuildarchLabelSelector},
-				client.HasLabels{v1alpha1.DefaultAssignmentLabel},
+				matchingFields(
+					fields.OneTermEqualSelector(adapter.AssignmentDefaultIndex, "true"),
+					fields.OneTermEqualSelector(adapter.AssignmentBuildarchIndex, inputBuildarch),
+					fields.OneTermEqualSelector(adapter.AssignmentPlatformIndex, inputPlatform),
+				),
+				client.InNamespace(namespace),
+			}
+
+			list(t)
+
+			actual, err := assignment.FindDefault(ctx, types.IPXESelectors{
+				Buildarch: inputBuildarch,
+				Platform:  inputPlatform,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, expectedAssignment, actual)
+		})
+
+		t.Run("AnyPlatform", func(t *testing.T) {
+			defer setup(t)()
+
+			expectedAssignment = types.Assignment{
+				Name:        "",
+				ProfileName: uuid.New().String(),
+			}
+
+			expectedListOptions = []interface{}{
+				matchingFields(
+					fields.OneTermEqualSelector(adapter.AssignmentDefaultIndex, "true"),
+					fields.OneTermEqualSelector(adapter.AssignmentBuildarchIndex, inputBuildarch),
+				),
+				client.InNamespace(namespace),
 			}
 
 			list(t)
 
-			actual, err := assignment.FindDefaultByBuildarch(ctx, inputBuildarch)
+			actual, err := assignment.FindDefault(ctx, types.IPXESelectors{Buildarch: inputBuildarch})
 			assert.NoError(t, err)
 			assert.Equal(t, expectedAssignment, actual)
 		})
@@ -92,7 +132,7 @@ func TestAssignment(t *testing.T) {
 
 				cl.EXPECT().List(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(assert.AnError)
 
-				actual, err := assignment.FindDefaultByBuildarch(ctx, inputBuildarch)
+				actual, err := assignment.FindDefault(ctx, types.IPXESelectors{Buildarch: inputBuildarch})
 				assert.ErrorIs(t, err, assert.AnError)
 				assert.Empty(t, actual)
 			})
@@ -103,7 +143,7 @@ func TestAssignment(t *testing.T) {
 				// No assignment found.
 				cl.EXPECT().List(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-				actual, err := assignment.FindDefaultByBuildarch(ctx, inputBuildarch)
+				actual, err := assignment.FindDefault(ctx, types.IPXESelectors{Buildarch: inputBuildarch})
 				assert.ErrorIs(t, err, adapter.ErrAssignmentNotFound)
 				assert.Empty(t, actual)
 			})
@@ -123,11 +163,16 @@ func TestAssignment(t *testing.T) {
 			selectors := types.IPXESelectors{
 				UUID:      id,
 				Buildarch: inputBuildarch,
+				Platform:  inputPlatform,
 			}
 
 			expectedListOptions = []any{
-				client.HasLabels{expectedBuildarchLabelSelector},
-				client.HasLabels{v1alpha1.NewUUIDLabelSelector(id)},
+				matchingFields(
+					fields.OneTermEqualSelector(adapter.AssignmentUUIDIndex, id.String()),
+					fields.OneTermEqualSelector(adapter.AssignmentBuildarchIndex, inputBuildarch),
+					fields.OneTermEqualSelector(adapter.AssignmentPlatformIndex, inputPlatform),
+				),
+				client.InNamespace(namespace),
 			}
 
 			list(t)
@@ -143,21 +188,141 @@ func TestAssignment(t *testing.T) {
 
 				cl.EXPECT().List(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(assert.AnError)
 
-				actual, err := assignment.FindBySelectors(ctx, types.IPXESelectors{})
+				actual, err := assignment.FindBySelectors(ctx, types.IPXESelectors{
+					Buildarch: inputBuildarch,
+					UUID:      uuid.New(),
+				})
 				assert.ErrorIs(t, err, assert.AnError)
 				assert.Empty(t, actual)
 			})
 
+			t.Run("NoSubjectIdentifier", func(t *testing.T) {
+				defer setup(t)()
+
+				// a nil UUID selects nothing, and no List call is made.
+				actual, err := assignment.FindBySelectors(ctx, types.IPXESelectors{Buildarch: inputBuildarch})
+				assert.ErrorIs(t, err, adapter.ErrAssignmentNotFound)
+				assert.Empty(t, actual)
+			})
+
 			t.Run("NotFound", func(t *testing.T) {
 				defer setup(t)()
 
 				// No assignment found.
 				cl.EXPECT().List(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-				actual, err := assignment.FindBySelectors(ctx, types.IPXESelectors{})
+				actual, err := assignment.FindBySelectors(ctx, types.IPXESelectors{
+					Buildarch: inputBuildarch,
+					UUID:      uuid.New(),
+				})
 				assert.ErrorIs(t, err, adapter.ErrAssignmentNotFound)
 				assert.Empty(t, actual)
 			})
 		})
 	})
+
+	t.Run("ListBySelectors", func(t *testing.T) {
+		defer setup(t)()
+
+		id := uuid.New()
+
+		cl.EXPECT().List(ctx, mock.Anything,
+			matchingFields(fields.OneTermEqualSelector(adapter.AssignmentUUIDIndex, id.String())),
+			client.InNamespace(namespace),
+		).
+			RunAndReturn(func(_ context.Context, objList client.ObjectList, _ ...client.ListOption) error {
+				l := objList.(*v1alpha1.AssignmentList)
+				l.Items = make([]v1alpha1.Assignment, 2)
+				l.Items[0].Name, l.Items[0].Spec.ProfileName = "a", "profile-a"
+				l.Items[1].Name, l.Items[1].Spec.ProfileName = "b", "profile-b"
+
+				return nil
+			})
+
+		actual, err := assignment.ListBySelectors(ctx, types.IPXESelectors{UUID: id})
+		assert.NoError(t, err)
+		assert.Equal(t, []types.Assignment{
+			{Name: "a", ProfileName: "profile-a"},
+			{Name: "b", ProfileName: "profile-b"},
+		}, actual)
+	})
+
+	t.Run("ListBySelectorsWithSubjectIdentifiers", func(t *testing.T) {
+		defer setup(t)()
+
+		mac := net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+		serial := "CZ2D2507KF"
+
+		expectList := func(field, value string, names ...string) {
+			cl.EXPECT().List(ctx, mock.Anything,
+				matchingFields(
+					fields.OneTermEqualSelector(field, value),
+					fields.OneTermEqualSelector(adapter.AssignmentBuildarchIndex, inputBuildarch),
+				),
+				client.InNamespace(namespace),
+			).RunAndReturn(func(_ context.Context, objList client.ObjectList, _ ...client.ListOption) error {
+				l := objList.(*v1alpha1.AssignmentList)
+				for _, name := range names {
+					l.Items = append(l.Items, v1alpha1.Assignment{ObjectMeta: metav1.ObjectMeta{Name: name}})
+				}
+
+				return nil
+			}).Once()
+		}
+
+		// the nil UUID is ignored.
+		expectList(adapter.AssignmentMACIndex, "aa-bb-cc-dd-ee-ff", "a")
+		expectList(adapter.AssignmentSerialIndex,
+			v1alpha1.LabelSelectorName(v1alpha1.NewSerialLabelSelector(serial)), "a", "b")
+
+		actual, err := assignment.ListBySelectors(ctx, types.IPXESelectors{
+			Buildarch: inputBuildarch,
+			MAC:       mac,
+			Serial:    serial,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []types.Assignment{{Name: "a"}, {Name: "b"}}, actual)
+	})
+
+	t.Run("ListWithoutPlatformLabels", func(t *testing.T) {
+		// assignments created before platforms were labeled must still select any platform.
+		id := uuid.New()
+		labels := map[string]string{
+			v1alpha1.NewUUIDLabelSelector(id):    "",
+			v1alpha1.Arm64BuildarchLabelSelector: "",
+			v1alpha1.DefaultAssignmentLabel:      "",
+		}
+
+		scheme := runtime.NewScheme()
+		require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+		builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&v1alpha1.Assignment{
+			ObjectMeta: metav1.ObjectMeta{Name: "unlabeled", Namespace: "test-assignment", Labels: labels},
+			Spec:       v1alpha1.AssignmentSpec{ProfileName: "profile"},
+		})
+		require.NoError(t, adapter.IndexFields(context.Background(), fakeIndexer{builder: builder}))
+
+		assignment := adapter.NewAssignment(builder.Build(), "test-assignment")
+		selectors := types.IPXESelectors{UUID: id, Buildarch: string(v1alpha1.Arm64), Platform: string(v1alpha1.EFI)}
+		expected := []types.Assignment{{Name: "unlabeled", ProfileName: "profile"}}
+
+		actual, err := assignment.ListBySelectors(context.Background(), selectors)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+
+		actual, err = assignment.ListDefault(context.Background(), selectors)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+}
+
+// fakeIndexer registers the field indexes on a fake client.
+type fakeIndexer struct {
+	builder *fake.ClientBuilder
+}
+
+func (f fakeIndexer) IndexField(_ context.Context, obj client.Object, field string, extract client.IndexerFunc) error {
+	f.builder.WithIndex(obj, field, extract)
+
+	return nil
 }
@@ -0,0 +1,110 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	ErrBootloaderNotFound = errors.New("bootloader not found")
+	ErrBootloaderGet      = errors.New("getting bootloader")
+
+	errBootloaderInvalidFilename = errors.New("invalid bootloader filename")
+	errBootloaderReadFile        = errors.New("reading bootloader file")
+	errBootloaderGetConfigMap    = errors.New("getting bootloader configmap")
+)
+
+// --------------------------------------------------- INTERFACES --------------------------------------------------- //
+
+// Bootloader retrieves iPXE binaries, e.g. `undionly.kpxe` or `ipxe.efi`, built for a specific buildarch.
+type Bootloader interface {
+	Get(ctx context.Context, buildarch string, filename string) ([]byte, error)
+}
+
+// --------------------------------------------------- CONSTRUCTORS ------------------------------------------------- //
+
+// NewDirectoryBootloader serves bootloaders from a directory. Bootloaders are expected to be organized by buildarch,
+// i.e. `<dir>/<buildarch>/<filename>`.
+func NewDirectoryBootloader(dir string) Bootloader {
+	return &directoryBootloader{fsys: os.DirFS(dir)}
+}
+
+// NewConfigMapBootloader serves bootloaders from a ConfigMap. As ConfigMap keys cannot contain slashes, the bootloaders
+// are expected to be stored in `binaryData` (or `data`) under the key `<buildarch>.<filename>`. The ConfigMap is read on
+// every call, hence c should read it from an informer cache, e.g. kubeutil.NewConfigMapCache.
+func NewConfigMapBootloader(c client.Client, namespace, name string) Bootloader {
+	return &configMapBootloader{
+		client:    c,
+		namespace: namespace,
+		name:      name,
+	}
+}
+
+// ------------------------------------------------ DIRECTORY BOOTLOADER -------------------------------------------- //
+
+type directoryBootloader struct {
+	fsys fs.FS
+}
+
+func (b *directoryBootloader) Get(_ context.Context, buildarch string, filename string) ([]byte, error) {
+	name := path.Join(buildarch, filename)
+	if !fs.ValidPath(name) {
+		return nil, errors.Join(fmt.Errorf("got %q", name), errBootloaderInvalidFilename, ErrBootloaderGet)
+	}
+
+	out, err := fs.ReadFile(b.fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, errors.Join(err, ErrBootloaderNotFound, ErrBootloaderGet)
+	} else if err != nil {
+		return nil, errors.Join(err, errBootloaderReadFile, ErrBootloaderGet)
+	}
+
+	return out, nil
+}
+
+// ----------------------------------------------- CONFIGMAP BOOTLOADER --------------------------------------------- //
+
+type configMapBootloader struct {
+	client    client.Client
+	namespace string
+	name      string
+}
+
+func (b *configMapBootloader) Get(ctx context.Context, buildarch string, filename string) ([]byte, error) {
+	cm := new(corev1.ConfigMap)
+
+	if err := b.client.Get(ctx, k8stypes.NamespacedName{
+		Namespace: b.namespace,
+		Name:      b.name,
+	}, cm); apierrors.IsNotFound(err) {
+		return nil, errors.Join(err, ErrBootloaderNotFound, errBootloaderGetConfigMap, ErrBootloaderGet)
+	} else if err != nil {
+		return nil, errors.Join(err, errBootloaderGetConfigMap, ErrBootloaderGet)
+	}
+
+	key := ConfigMapBootloaderKey(buildarch, filename)
+
+	if out, ok := cm.BinaryData[key]; ok {
+		return out, nil
+	}
+
+	if out, ok := cm.Data[key]; ok {
+		return []byte(out), nil
+	}
+
+	return nil, errors.Join(fmt.Errorf("key %q not found", key), ErrBootloaderNotFound, ErrBootloaderGet)
+}
+
+// ConfigMapBootloaderKey returns the key used to store a bootloader in a ConfigMap.
+func ConfigMapBootloaderKey(buildarch string, filename string) string {
+	return fmt.Sprintf("%s.%s", buildarch, filename)
+}
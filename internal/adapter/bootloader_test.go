@@ -0,0 +1,161 @@
+//go:build unit
+
+package adapter_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/mocks/mockclient"
+)
+
+func TestDirectoryBootloader(t *testing.T) {
+	var (
+		ctx      context.Context
+		expected []byte
+
+		bootloader adapter.Bootloader
+	)
+
+	setup := func(t *testing.T) {
+		t.Helper()
+
+		ctx = context.Background()
+		expected = []byte("ipxe.efi binary")
+
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "x86_64"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "x86_64", "ipxe.efi"), expected, 0o600))
+
+		bootloader = adapter.NewDirectoryBootloader(dir)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		setup(t)
+
+		actual, err := bootloader.Get(ctx, "x86_64", "ipxe.efi")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("NotFound", func(t *testing.T) {
+			setup(t)
+
+			_, err := bootloader.Get(ctx, "arm64", "ipxe.efi")
+			assert.ErrorIs(t, err, adapter.ErrBootloaderNotFound)
+		})
+
+		t.Run("PathTraversal", func(t *testing.T) {
+			setup(t)
+
+			_, err := bootloader.Get(ctx, "x86_64", "../../etc/passwd")
+			assert.ErrorIs(t, err, adapter.ErrBootloaderGet)
+			assert.NotErrorIs(t, err, adapter.ErrBootloaderNotFound)
+		})
+	})
+}
+
+func TestConfigMapBootloader(t *testing.T) {
+	var (
+		ctx       context.Context
+		namespace string
+		name      string
+		expected  []byte
+		cm        corev1.ConfigMap
+		getErr    error
+
+		cl         *mockclient.MockClient
+		bootloader adapter.Bootloader
+	)
+
+	setup := func(t *testing.T) func() {
+		t.Helper()
+
+		ctx = context.Background()
+		namespace = "ipxer"
+		name = "bootloaders"
+		expected = []byte("undionly.kpxe binary")
+		getErr = nil
+
+		cm = corev1.ConfigMap{
+			BinaryData: map[string][]byte{
+				adapter.ConfigMapBootloaderKey("i386", "undionly.kpxe"): expected,
+			},
+		}
+
+		cl = mockclient.NewMockClient(t)
+		bootloader = adapter.NewConfigMapBootloader(cl, namespace, name)
+
+		return func() {
+			t.Helper()
+
+			cl.AssertExpectations(t)
+		}
+	}
+
+	get := func() {
+		cl.EXPECT().
+			Get(ctx, k8stypes.NamespacedName{Namespace: namespace, Name: name}, mock.Anything).
+			RunAndReturn(func(_ context.Context, _ k8stypes.NamespacedName, obj client.Object, _ ...client.GetOption) error {
+				*obj.(*corev1.ConfigMap) = cm
+
+				return getErr
+			}).
+			Once()
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		defer setup(t)()
+
+		get()
+
+		actual, err := bootloader.Get(ctx, "i386", "undionly.kpxe")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("KeyNotFound", func(t *testing.T) {
+			defer setup(t)()
+
+			get()
+
+			_, err := bootloader.Get(ctx, "arm64", "ipxe.efi")
+			assert.ErrorIs(t, err, adapter.ErrBootloaderNotFound)
+		})
+
+		t.Run("ConfigMapNotFound", func(t *testing.T) {
+			defer setup(t)()
+
+			getErr = apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, name)
+			get()
+
+			_, err := bootloader.Get(ctx, "i386", "undionly.kpxe")
+			assert.ErrorIs(t, err, adapter.ErrBootloaderNotFound)
+		})
+
+		t.Run("GetError", func(t *testing.T) {
+			defer setup(t)()
+
+			getErr = assert.AnError
+			get()
+
+			_, err := bootloader.Get(ctx, "i386", "undionly.kpxe")
+			assert.ErrorIs(t, err, assert.AnError)
+			assert.NotErrorIs(t, err, adapter.ErrBootloaderNotFound)
+		})
+	})
+}
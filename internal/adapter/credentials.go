@@ -0,0 +1,338 @@
+package adapter
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/jsonpath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// maxIdleConnsPerHost is the number of keep-alive connections pooled per webhook endpoint.
+	maxIdleConnsPerHost = 16
+
+	// informerIdleTimeout is the duration after which the watch of a resource which is no longer referenced is stopped.
+	informerIdleTimeout   = 10 * time.Minute
+	informerSweepInterval = time.Minute
+)
+
+var (
+	errResolvingCredentials    = errors.New("resolving credentials")
+	errResolvingBearerTokenRef = errors.New("resolving bearer token ref")
+	errWatchingObject          = errors.New("watching object")
+	errUnexpectedObjectType    = errors.New("unexpected object type")
+)
+
+// ---------------------------------------------------- INTERFACES -------------------------------------------------- //
+
+// CredentialCache caches the HTTP transports and the credentials built from the resources referenced by webhooks, so
+// connections are reused and referenced resources are not fetched on every call.
+type CredentialCache interface {
+	// Transport returns the transport presenting the client certificate referenced by ref, or a shared transport if ref
+	// is nil. Transports pool keep-alive connections per endpoint.
+	Transport(ctx context.Context, ref *types.MTLSObjectRef) (http.RoundTripper, error)
+	// BasicAuth returns the username and the password referenced by ref.
+	BasicAuth(ctx context.Context, ref *types.BasicAuthObjectRef) (string, string, error)
+	// BearerToken returns the token referenced by ref.
+	BearerToken(ctx context.Context, ref *types.BearerTokenObjectRef) (string, error)
+}
+
+// --------------------------------------------------- CONSTRUCTORS ------------------------------------------------- //
+
+// NewCredentialCache returns a CredentialCache watching the referenced resources until ctx is done, they are deleted or
+// they are not used for 10 minutes. Transports are keyed by their resource, its resourceVersion and the credentials,
+// and are evicted once their resource changes or is no longer watched.
+// disableTLSInsecureSkipVerify globally enforces TLS verification, regardless of the TLSInsecureSkipVerify of the
+// references.
+func NewCredentialCache(
+	ctx context.Context,
+	k8sClient dynamic.Interface,
+	disableTLSInsecureSkipVerify bool,
+) CredentialCache {
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+
+	return &credentialCache{
+		ctx:                          ctx,
+		k8s:                          k8sClient,
+		disableTLSInsecureSkipVerify: disableTLSInsecureSkipVerify,
+		defaultTransport:             transport,
+		informers:                    make(map[objectKey]*watchedObject),
+		lastSweep:                    time.Now(),
+		transports:                   make(map[string]cachedTransport),
+	}
+}
+
+// ---------------------------------------------------- CACHE ------------------------------------------------------- //
+
+type credentialCache struct {
+	ctx context.Context
+	k8s dynamic.Interface
+
+	// Allow GLOBALLY disabling
+	disableTLSInsecureSkipVerify bool
+
+	defaultTransport *http.Transport
+
+	mu         sync.Mutex
+	informers  map[objectKey]*watchedObject
+	lastSweep  time.Time
+	transports map[string]cachedTransport
+}
+
+// watchedObject is the informer watching a resource, stopped by cancel.
+type watchedObject struct {
+	informer informers.GenericInformer
+	cancel   context.CancelFunc
+	lastUsed time.Time
+}
+
+// objectKey identifies a watched resource.
+type objectKey struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+	name      string
+}
+
+type cachedTransport struct {
+	object    objectKey
+	transport *http.Transport
+}
+
+func (c *credentialCache) Transport(ctx context.Context, ref *types.MTLSObjectRef) (http.RoundTripper, error) {
+	if ref == nil {
+		return c.defaultTransport, nil
+	}
+
+	obj, err := c.object(ctx, ref.ObjectRef)
+	if err != nil {
+		return nil, errors.Join(err, errResolvingMTLSConfig)
+	}
+
+	res, err := objectValues(
+		obj.Object,
+		[]*jsonpath.JSONPath{ref.ClientKeyJSONPath, ref.ClientCertJSONPath, ref.CaBundleJSONPath},
+		ref.ObjectRef,
+	)
+	if err != nil {
+		return nil, errors.Join(err, errResolvingMTLSConfig)
+	}
+
+	insecureSkipVerify := !c.disableTLSInsecureSkipVerify && ref.TLSInsecureSkipVerify
+	key := transportKey(obj, insecureSkipVerify, res...)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.transports[key]; ok {
+		return cached.transport, nil
+	}
+
+	caCertPool := x509.NewCertPool()
+	caCertPool.AppendCertsFromPEM(res[2])
+
+	cert, err := tls.X509KeyPair(res[1], res[0])
+	if err != nil {
+		return nil, errors.Join(err, errResolvingMTLSConfig)
+	}
+
+	transport := c.defaultTransport.Clone()
+	transport.TLSClientConfig = &tls.Config{ //nolint:gosec // users explicitly allow self-signed certificates.
+		RootCAs:            caCertPool,
+		Certificates:       []tls.Certificate{cert},
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	c.transports[key] = cachedTransport{object: keyOf(ref.ObjectRef), transport: transport}
+
+	return transport, nil
+}
+
+func (c *credentialCache) BasicAuth(ctx context.Context, ref *types.BasicAuthObjectRef) (string, string, error) {
+	obj, err := c.object(ctx, ref.ObjectRef)
+	if err != nil {
+		return "", "", errors.Join(err, errResolvingBasicAuthRef)
+	}
+
+	res, err := objectValues(obj.Object, []*jsonpath.JSONPath{ref.UsernameJSONPath, ref.PasswordJSONPath}, ref.ObjectRef)
+	if err != nil {
+		return "", "", errors.Join(err, errResolvingBasicAuthRef)
+	}
+
+	return string(res[0]), string(res[1]), nil
+}
+
+func (c *credentialCache) BearerToken(ctx context.Context, ref *types.BearerTokenObjectRef) (string, error) {
+	obj, err := c.object(ctx, ref.ObjectRef)
+	if err != nil {
+		return "", errors.Join(err, errResolvingBearerTokenRef)
+	}
+
+	res, err := objectValues(obj.Object, []*jsonpath.JSONPath{ref.TokenJSONPath}, ref.ObjectRef)
+	if err != nil {
+		return "", errors.Join(err, errResolvingBearerTokenRef)
+	}
+
+	return string(res[0]), nil
+}
+
+// object returns the referenced resource from the store of its informer, starting the informer on first use.
+func (c *credentialCache) object(ctx context.Context, ref types.ObjectRef) (*unstructured.Unstructured, error) {
+	key := keyOf(ref)
+	informer := c.informer(key)
+
+	if !cache.WaitForCacheSync(ctx.Done(), informer.Informer().HasSynced) {
+		return nil, errors.Join(
+			fmt.Errorf("%s %s/%s", key.gvr.Resource, key.namespace, key.name),
+			ctx.Err(),
+			errWatchingObject,
+			errResolvingCredentials,
+		)
+	}
+
+	obj, err := informer.Lister().ByNamespace(key.namespace).Get(key.name)
+	if err != nil {
+		return nil, errors.Join(err, errResolvingCredentials)
+	}
+
+	out, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, errors.Join(fmt.Errorf("got: %T", obj), errUnexpectedObjectType, errResolvingCredentials)
+	}
+
+	return out, nil
+}
+
+// informer returns the informer watching the resource, which evicts the transports of the resource on change. The
+// informer is stopped once the resource is deleted, and the informers which are not used anymore are stopped
+// periodically, e.g. those of resources no longer referenced by any profile.
+func (c *credentialCache) informer(key objectKey) informers.GenericInformer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+
+	if now.Sub(c.lastSweep) > informerSweepInterval {
+		for k, watched := range c.informers {
+			if now.Sub(watched.lastUsed) > informerIdleTimeout {
+				c.stop(k)
+			}
+		}
+
+		c.lastSweep = now
+	}
+
+	if watched, ok := c.informers[key]; ok {
+		watched.lastUsed = now
+		return watched.informer
+	}
+
+	informer := dynamicinformer.NewFilteredDynamicInformer(
+		c.k8s,
+		key.gvr,
+		key.namespace,
+		0,
+		cache.Indexers{},
+		func(opts *metav1.ListOptions) { opts.FieldSelector = "metadata.name=" + key.name },
+	)
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	watched := &watchedObject{informer: informer, cancel: cancel, lastUsed: now}
+
+	// the handler is registered before the informer starts, hence never fails.
+	_, _ = informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(_, _ any) { c.evict(key) },
+		DeleteFunc: func(any) { c.forget(key, watched) },
+	})
+
+	go informer.Informer().Run(ctx.Done())
+
+	c.informers[key] = watched
+
+	return informer
+}
+
+// evict removes the transports of the resource and closes their idle connections.
+func (c *credentialCache) evict(object objectKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evictTransports(object)
+}
+
+// forget stops the informer of the deleted resource, unless it was already replaced.
+func (c *credentialCache) forget(object objectKey, watched *watchedObject) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.informers[object] == watched {
+		c.stop(object)
+	}
+}
+
+// stop stops the informer of the resource and evicts its transports, which would not be evicted on change anymore.
+// c.mu must be held.
+func (c *credentialCache) stop(object objectKey) {
+	c.informers[object].cancel()
+	delete(c.informers, object)
+	c.evictTransports(object)
+}
+
+// evictTransports removes the transports of the resource and closes their idle connections. c.mu must be held.
+func (c *credentialCache) evictTransports(object objectKey) {
+	for key, cached := range c.transports {
+		if cached.object == object {
+			cached.transport.CloseIdleConnections()
+			delete(c.transports, key)
+		}
+	}
+}
+
+func keyOf(ref types.ObjectRef) objectKey {
+	return objectKey{
+		gvr: schema.GroupVersionResource{
+			Group:    ref.Group,
+			Version:  ref.Version,
+			Resource: ref.Resource,
+		},
+		namespace: ref.Namespace,
+		name:      ref.Name,
+	}
+}
+
+// transportKey keys a transport by its resource, the resourceVersion of the resource and the credentials, as several
+// references may read distinct credentials from the same resource.
+func transportKey(obj *unstructured.Unstructured, insecureSkipVerify bool, credentials ...[]byte) string {
+	h := sha256.New()
+
+	for _, credential := range credentials {
+		h.Write(credential)
+		h.Write([]byte{0})
+	}
+
+	return fmt.Sprintf("%s/%s/%s/%s/%t/%s",
+		obj.GetAPIVersion(),
+		obj.GetNamespace(),
+		obj.GetName(),
+		obj.GetResourceVersion(),
+		insecureSkipVerify,
+		hex.EncodeToString(h.Sum(nil)),
+	)
+}
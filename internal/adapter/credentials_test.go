@@ -0,0 +1,226 @@
+//go:build unit
+
+package adapter_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
+	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/certutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+func TestCredentialCache(t *testing.T) {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+
+		ca           *certutil.CA
+		secret       *unstructured.Unstructured
+		mtlsRef      *types.MTLSObjectRef
+		basicAuthRef *types.BasicAuthObjectRef
+
+		cl    *fake.FakeDynamicClient
+		cache adapter.CredentialCache
+	)
+
+	secretGVR := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+	// setSecretData sets the data of the secret, encoded as kubernetes stores them.
+	setSecretData := func(t *testing.T) {
+		t.Helper()
+
+		clientKey, clientCert, err := ca.NewCertifiedKeyPEM("localhost")
+		require.NoError(t, err)
+
+		data := map[string][]byte{
+			"client.key": clientKey,
+			"client.crt": clientCert,
+			"ca.crt":     ca.Cert(),
+			"username":   []byte("qwe123"),
+			"password":   []byte("321ewq"),
+			"token":      []byte("t0k3n"),
+		}
+
+		encoded := make(map[string]any, len(data))
+		for key, value := range data {
+			encoded[key] = base64.StdEncoding.EncodeToString(value)
+		}
+
+		secret.Object["data"] = encoded
+	}
+
+	setup := func(t *testing.T) func() {
+		t.Helper()
+
+		ctx, cancel = context.WithCancel(context.Background())
+
+		var err error
+		ca, err = certutil.NewCA()
+		require.NoError(t, err)
+
+		secret = &unstructured.Unstructured{}
+		secret.SetAPIVersion("v1")
+		secret.SetKind("Secret")
+		secret.SetName("creds")
+		secret.SetNamespace("ns")
+		setSecretData(t)
+
+		objectRef := types.ObjectRef{Version: "v1", Resource: "secrets", Namespace: "ns", Name: "creds"}
+
+		mtlsRef = &types.MTLSObjectRef{
+			ObjectRef:          objectRef,
+			ClientKeyJSONPath:  jsonpath.New(""),
+			ClientCertJSONPath: jsonpath.New(""),
+			CaBundleJSONPath:   jsonpath.New(""),
+		}
+		require.NoError(t, mtlsRef.ClientKeyJSONPath.Parse(`{.data.client\.key}`))
+		require.NoError(t, mtlsRef.ClientCertJSONPath.Parse(`{.data.client\.crt}`))
+		require.NoError(t, mtlsRef.CaBundleJSONPath.Parse(`{.data.ca\.crt}`))
+
+		basicAuthRef = &types.BasicAuthObjectRef{
+			ObjectRef:        objectRef,
+			UsernameJSONPath: jsonpath.New(""),
+			PasswordJSONPath: jsonpath.New(""),
+		}
+		require.NoError(t, basicAuthRef.UsernameJSONPath.Parse(`{.data.username}`))
+		require.NoError(t, basicAuthRef.PasswordJSONPath.Parse(`{.data.password}`))
+
+		cl = fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			secretGVR: "SecretList",
+		})
+		require.NoError(t, cl.Tracker().Create(secretGVR, secret, "ns"))
+
+		cache = adapter.NewCredentialCache(ctx, cl, false)
+
+		return func() {
+			t.Helper()
+
+			cancel()
+		}
+	}
+
+	t.Run("Transport", func(t *testing.T) {
+		t.Run("Default", func(t *testing.T) {
+			defer setup(t)()
+
+			first, err := cache.Transport(ctx, nil)
+			require.NoError(t, err)
+
+			second, err := cache.Transport(ctx, nil)
+			require.NoError(t, err)
+
+			assert.Same(t, first, second)
+		})
+
+		t.Run("MTLS", func(t *testing.T) {
+			defer setup(t)()
+
+			first, err := cache.Transport(ctx, mtlsRef)
+			require.NoError(t, err)
+			require.IsType(t, &http.Transport{}, first)
+			assert.Len(t, first.(*http.Transport).TLSClientConfig.Certificates, 1)
+
+			// the transport is reused while the secret is unchanged.
+			second, err := cache.Transport(ctx, mtlsRef)
+			require.NoError(t, err)
+			assert.Same(t, first, second)
+
+			// the transport is rebuilt once the secret changes.
+			setSecretData(t)
+			require.NoError(t, cl.Tracker().Update(secretGVR, secret, "ns"))
+
+			assert.Eventually(t, func() bool {
+				third, err := cache.Transport(ctx, mtlsRef)
+				return err == nil && third != first
+			}, 5*time.Second, 10*time.Millisecond)
+		})
+
+		t.Run("DisableTLSInsecureSkipVerify", func(t *testing.T) {
+			defer setup(t)()
+
+			mtlsRef.TLSInsecureSkipVerify = true
+
+			transport, err := cache.Transport(ctx, mtlsRef)
+			require.NoError(t, err)
+			assert.True(t, transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify)
+
+			// TLS verification is globally enforced.
+			cache = adapter.NewCredentialCache(ctx, cl, true)
+
+			transport, err = cache.Transport(ctx, mtlsRef)
+			require.NoError(t, err)
+			assert.False(t, transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify)
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			defer setup(t)()
+
+			mtlsRef.Name = "not-found"
+
+			_, err := cache.Transport(ctx, mtlsRef)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("BasicAuth", func(t *testing.T) {
+		defer setup(t)()
+
+		username, password, err := cache.BasicAuth(ctx, basicAuthRef)
+		require.NoError(t, err)
+		assert.Equal(t, "qwe123", username)
+		assert.Equal(t, "321ewq", password)
+	})
+
+	t.Run("DeletedObject", func(t *testing.T) {
+		defer setup(t)()
+
+		// lists counts the informers started to watch the secret.
+		lists := func() int {
+			n := 0
+
+			for _, action := range cl.Actions() {
+				if action.GetVerb() == "list" && action.GetResource() == secretGVR {
+					n++
+				}
+			}
+
+			return n
+		}
+
+		_, _, err := cache.BasicAuth(ctx, basicAuthRef)
+		require.NoError(t, err)
+		require.Equal(t, 1, lists())
+
+		// the watch of the deleted secret is stopped, hence a new informer is started on the next call.
+		require.NoError(t, cl.Tracker().Delete(secretGVR, "ns", "creds"))
+
+		assert.Eventually(t, func() bool {
+			_, _, err := cache.BasicAuth(ctx, basicAuthRef)
+			return err != nil && lists() == 2
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("BearerToken", func(t *testing.T) {
+		defer setup(t)()
+
+		ref := &types.BearerTokenObjectRef{ObjectRef: basicAuthRef.ObjectRef, TokenJSONPath: jsonpath.New("")}
+		require.NoError(t, ref.TokenJSONPath.Parse(`{.data.token}`))
+
+		token, err := cache.BearerToken(ctx, ref)
+		require.NoError(t, err)
+		assert.Equal(t, "t0k3n", token)
+	})
+}
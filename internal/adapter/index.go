@@ -0,0 +1,167 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Field indexes queried by the adapters. They must be registered with IndexFields on the informer cache the adapters'
+// client reads from, as the API server does not support field selectors on custom fields.
+const (
+	// AssignmentUUIDIndex indexes assignments by the UUIDs of the subjects they select.
+	AssignmentUUIDIndex = "ipxer.assignment.uuid"
+	// AssignmentMACIndex indexes assignments by the names of their MAC label selectors.
+	AssignmentMACIndex = "ipxer.assignment.mac"
+	// AssignmentSerialIndex indexes assignments by the names of their serial number label selectors.
+	AssignmentSerialIndex = "ipxer.assignment.serial"
+	// AssignmentAssetIndex indexes assignments by the names of their asset tag label selectors.
+	AssignmentAssetIndex = "ipxer.assignment.asset"
+	// AssignmentHostnameIndex indexes assignments by the names of their hostname label selectors.
+	AssignmentHostnameIndex = "ipxer.assignment.hostname"
+	// AssignmentBuildarchIndex indexes assignments by the buildarch they select.
+	AssignmentBuildarchIndex = "ipxer.assignment.buildarch"
+	// AssignmentPlatformIndex indexes assignments by the platforms they select.
+	AssignmentPlatformIndex = "ipxer.assignment.platform"
+	// AssignmentDefaultIndex indexes default assignments under the value "true".
+	AssignmentDefaultIndex = "ipxer.assignment.default"
+	// AssignmentProfileIndex indexes assignments by the name of the profile they assign.
+	AssignmentProfileIndex = "ipxer.assignment.profile"
+	// ProfileContentUUIDIndex indexes profiles by the UUIDs of their exposed contents.
+	ProfileContentUUIDIndex = "ipxer.profile.contentUUID"
+
+	indexedTrue = "true"
+)
+
+var errIndexingFields = errors.New("indexing fields")
+
+// IndexFields registers the field indexes queried by the adapters.
+func IndexFields(ctx context.Context, indexer client.FieldIndexer) error {
+	for _, idx := range []struct {
+		obj     client.Object
+		field   string
+		extract client.IndexerFunc
+	}{
+		{obj: &v1alpha1.Assignment{}, field: AssignmentUUIDIndex, extract: indexUUIDs},
+		{obj: &v1alpha1.Assignment{}, field: AssignmentMACIndex, extract: indexLabelSelectorNames(v1alpha1.MACPrefix)},
+		{obj: &v1alpha1.Assignment{}, field: AssignmentSerialIndex, extract: indexLabelSelectorNames(v1alpha1.SerialPrefix)},
+		{obj: &v1alpha1.Assignment{}, field: AssignmentAssetIndex, extract: indexLabelSelectorNames(v1alpha1.AssetPrefix)},
+		{
+			obj:     &v1alpha1.Assignment{},
+			field:   AssignmentHostnameIndex,
+			extract: indexLabelSelectorNames(v1alpha1.HostnamePrefix),
+		},
+		{obj: &v1alpha1.Assignment{}, field: AssignmentBuildarchIndex, extract: indexAssignmentBuildarch},
+		{obj: &v1alpha1.Assignment{}, field: AssignmentPlatformIndex, extract: indexAssignmentPlatform},
+		{obj: &v1alpha1.Assignment{}, field: AssignmentDefaultIndex, extract: indexAssignmentDefault},
+		{obj: &v1alpha1.Assignment{}, field: AssignmentProfileIndex, extract: indexAssignmentProfile},
+		{obj: &v1alpha1.Profile{}, field: ProfileContentUUIDIndex, extract: indexUUIDs},
+	} {
+		if err := indexer.IndexField(ctx, idx.obj, idx.field, idx.extract); err != nil {
+			return errors.Join(err, errIndexingFields)
+		}
+	}
+
+	return nil
+}
+
+// indexUUIDs extracts the UUIDs of UUID label selectors. Invalid labels are not indexed.
+func indexUUIDs(obj client.Object) []string {
+	ids, _, err := v1alpha1.UUIDLabelSelectors(obj.GetLabels())
+	if err != nil {
+		return nil
+	}
+
+	out := make([]string, 0, len(ids))
+	for id := range ids {
+		out = append(out, id.String())
+	}
+
+	return out
+}
+
+// indexLabelSelectorNames returns an extractor of the names of the label selectors with the specified prefix.
+func indexLabelSelectorNames(prefix string) client.IndexerFunc {
+	return func(obj client.Object) []string {
+		return v1alpha1.LabelSelectorNames(obj.GetLabels(), prefix)
+	}
+}
+
+func indexAssignmentBuildarch(obj client.Object) []string {
+	assignment, ok := obj.(*v1alpha1.Assignment)
+	if !ok {
+		return nil
+	}
+
+	out := make([]string, 0)
+	for _, b := range assignment.GetBuildarchList() {
+		out = append(out, b.String())
+	}
+
+	return out
+}
+
+// indexAssignmentPlatform extracts the platforms selected by an assignment. Assignments without platform labels, i.e.
+// created before platforms were labeled by the defaulting webhook, select any platform as the webhook would default
+// them to.
+func indexAssignmentPlatform(obj client.Object) []string {
+	assignment, ok := obj.(*v1alpha1.Assignment)
+	if !ok {
+		return nil
+	}
+
+	platforms := assignment.GetPlatformList()
+	if len(platforms) == 0 {
+		platforms = v1alpha1.AllowedPlatformList
+	}
+
+	out := make([]string, 0, len(platforms))
+	for _, p := range platforms {
+		out = append(out, p.String())
+	}
+
+	return out
+}
+
+func indexAssignmentDefault(obj client.Object) []string {
+	if _, ok := obj.GetLabels()[v1alpha1.DefaultAssignmentLabel]; ok {
+		return []string{indexedTrue}
+	}
+
+	return nil
+}
+
+func indexAssignmentProfile(obj client.Object) []string {
+	assignment, ok := obj.(*v1alpha1.Assignment)
+	if !ok || assignment.Spec.ProfileName == "" {
+		return nil
+	}
+
+	return []string{assignment.Spec.ProfileName}
+}
+
+// ---------------------------------------------------- SELECTORS --------------------------------------------------- //
+
+// indexSelector is a field selector term.
+type indexSelector struct {
+	field string
+	value string
+}
+
+// matchingIndexes returns a list option selecting objects by indexes. The cache looks up the first term and filters the
+// result with the following ones, which are ignored if their value is empty: terms must be ordered from the most to the
+// least selective.
+func matchingIndexes(first indexSelector, terms ...indexSelector) client.ListOption {
+	selectors := []fields.Selector{fields.OneTermEqualSelector(first.field, first.value)}
+
+	for _, term := range terms {
+		if term.value != "" {
+			selectors = append(selectors, fields.OneTermEqualSelector(term.field, term.value))
+		}
+	}
+
+	return client.MatchingFieldsSelector{Selector: fields.AndSelectors(selectors...)}
+}
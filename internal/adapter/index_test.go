@@ -0,0 +1,74 @@
+//go:build unit
+
+package adapter_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
+	"github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fieldIndexer records the registered extractors.
+type fieldIndexer map[string]client.IndexerFunc
+
+func (f fieldIndexer) IndexField(_ context.Context, _ client.Object, field string, extract client.IndexerFunc) error {
+	f[field] = extract
+
+	return nil
+}
+
+func TestIndexFields(t *testing.T) {
+	indexer := make(fieldIndexer)
+	require.NoError(t, adapter.IndexFields(context.Background(), indexer))
+
+	t.Run("Assignment", func(t *testing.T) {
+		id := uuid.New()
+		obj := &v1alpha1.Assignment{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+			v1alpha1.NewUUIDLabelSelector(id):                                                  "",
+			v1alpha1.Arm64BuildarchLabelSelector:                                               "",
+			v1alpha1.EFIPlatformLabelSelector:                                                  "",
+			v1alpha1.DefaultAssignmentLabel:                                                    "",
+			v1alpha1.NewMACLabelSelector(net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}): "",
+			v1alpha1.NewSerialLabelSelector("CZ2D2507KF"):                                      "",
+		}}}
+
+		assert.Equal(t, []string{id.String()}, indexer[adapter.AssignmentUUIDIndex](obj))
+		assert.Equal(t, []string{v1alpha1.Arm64.String()}, indexer[adapter.AssignmentBuildarchIndex](obj))
+		assert.Equal(t, []string{v1alpha1.EFI.String()}, indexer[adapter.AssignmentPlatformIndex](obj))
+		assert.Equal(t, []string{"true"}, indexer[adapter.AssignmentDefaultIndex](obj))
+		assert.Equal(t, []string{"aa-bb-cc-dd-ee-ff"}, indexer[adapter.AssignmentMACIndex](obj))
+		assert.Equal(t,
+			[]string{v1alpha1.LabelSelectorName(v1alpha1.NewSerialLabelSelector("CZ2D2507KF"))},
+			indexer[adapter.AssignmentSerialIndex](obj),
+		)
+		assert.Empty(t, indexer[adapter.AssignmentHostnameIndex](obj))
+
+		obj.Spec.ProfileName = "profile"
+		assert.Equal(t, []string{"profile"}, indexer[adapter.AssignmentProfileIndex](obj))
+
+		assert.Empty(t, indexer[adapter.AssignmentDefaultIndex](&v1alpha1.Assignment{}))
+
+		// assignments without platform labels select any platform.
+		assert.Equal(t,
+			[]string{v1alpha1.EFI.String(), v1alpha1.PCBIOS.String()},
+			indexer[adapter.AssignmentPlatformIndex](&v1alpha1.Assignment{}),
+		)
+	})
+
+	t.Run("Profile", func(t *testing.T) {
+		id := uuid.New()
+		obj := &v1alpha1.Profile{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+			v1alpha1.NewUUIDLabelSelector(id): "content",
+		}}}
+
+		assert.Equal(t, []string{id.String()}, indexer[adapter.ProfileContentUUIDIndex](obj))
+	})
+}
@@ -2,7 +2,12 @@ package adapter
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"maps"
+	"time"
 
 	"k8s.io/utils/ptr"
 
@@ -23,7 +28,8 @@ var (
 
 	// Conversions
 
-	errConvertingProfile       = errors.New("converting profile")
+	// ErrConvertingProfile is returned when a Profile cannot be converted, i.e. the Profile is invalid.
+	ErrConvertingProfile       = errors.New("converting profile")
 	errAddContExposedButNoUUID = errors.New(
 		"additional content is exposed but doesn't have a UUID",
 	)
@@ -85,7 +91,9 @@ func (p *v1a1Profile) ListByContentID(
 ) ([]types.Profile, error) {
 	// list profiles
 	obj := new(v1alpha1.ProfileList)
-	if err := p.client.List(ctx, obj, uuidLabelSelector(configID)); apierrors.IsNotFound(err) ||
+	if err := p.client.List(ctx, obj, client.InNamespace(p.namespace), matchingIndexes(
+		indexSelector{field: ProfileContentUUIDIndex, value: configID.String()},
+	)); apierrors.IsNotFound(err) ||
 		len(obj.Items) == 0 {
 		return nil, errors.Join(err, ErrProfileNotFound, errProfileListByContentID)
 	} else if err != nil {
@@ -114,10 +122,11 @@ type ipxev1a1 struct{}
 func (ipxev1a1) toProfile(input *v1alpha1.Profile) (types.Profile, error) {
 	idNameMap, rev, err := v1alpha1.UUIDLabelSelectors(input.Labels)
 	if err != nil {
-		return types.Profile{}, errors.Join(err, errConvertingProfile)
+		return types.Profile{}, errors.Join(err, ErrConvertingProfile)
 	}
 
 	out := types.Profile{
+		Name:               input.Name,
 		IPXETemplate:       input.Spec.IPXETemplate,
 		AdditionalContent:  make(map[string]types.Content),
 		ContentIDToNameMap: idNameMap,
@@ -126,6 +135,19 @@ func (ipxev1a1) toProfile(input *v1alpha1.Profile) (types.Profile, error) {
 	for _, c := range input.Spec.AdditionalContent {
 		content := types.Content{}
 		content.Name = c.Name
+		content.Template = c.Template
+		content.NotFoundAsEmpty = c.NotFoundAsEmpty
+
+		if c.CacheTTL != nil {
+			content.CacheTTL = c.CacheTTL.Duration
+		}
+
+		hash, err := hashContent(c)
+		if err != nil {
+			return types.Profile{}, errors.Join(err, ErrConvertingProfile)
+		}
+
+		content.Hash = hash
 
 		// 1. Is content exposed?
 		if c.Exposed {
@@ -135,7 +157,7 @@ func (ipxev1a1) toProfile(input *v1alpha1.Profile) (types.Profile, error) {
 			if !ok {
 				return types.Profile{}, errors.Join(
 					errAddContExposedButNoUUID,
-					errConvertingProfile,
+					ErrConvertingProfile,
 				)
 			}
 
@@ -145,7 +167,7 @@ func (ipxev1a1) toProfile(input *v1alpha1.Profile) (types.Profile, error) {
 		// 2. Post transformers.
 		transformers, err := fromV1alpha1.toTransformerConfig(c.PostTransformations)
 		if err != nil {
-			return types.Profile{}, errors.Join(err, errConvertingProfile)
+			return types.Profile{}, errors.Join(err, ErrConvertingProfile)
 		}
 
 		content.PostTransformers = transformers
@@ -158,7 +180,7 @@ func (ipxev1a1) toProfile(input *v1alpha1.Profile) (types.Profile, error) {
 		case c.ObjectRef != nil:
 			ref, err := fromV1alpha1.toObjectRef(c.ObjectRef)
 			if err != nil {
-				return types.Profile{}, errors.Join(err, errConvertingProfile)
+				return types.Profile{}, errors.Join(err, ErrConvertingProfile)
 			}
 
 			content.ResolverKind = types.ObjectRefResolverKind
@@ -166,11 +188,43 @@ func (ipxev1a1) toProfile(input *v1alpha1.Profile) (types.Profile, error) {
 		case c.Webhook != nil:
 			cfg, err := fromV1alpha1.toWebhookConfig(c.Webhook)
 			if err != nil {
-				return types.Profile{}, errors.Join(err, errConvertingProfile)
+				return types.Profile{}, errors.Join(err, ErrConvertingProfile)
 			}
 
 			content.ResolverKind = types.WebhookResolverKind
 			content.WebhookConfig = &cfg
+		case c.HTTP != nil:
+			cfg, err := fromV1alpha1.toHTTPConfig(c.HTTP)
+			if err != nil {
+				return types.Profile{}, errors.Join(err, ErrConvertingProfile)
+			}
+
+			content.ResolverKind = types.HTTPResolverKind
+			content.HTTPConfig = &cfg
+		case c.S3 != nil:
+			cfg, err := fromV1alpha1.toS3Config(c.S3)
+			if err != nil {
+				return types.Profile{}, errors.Join(err, ErrConvertingProfile)
+			}
+
+			content.ResolverKind = types.S3ResolverKind
+			content.S3Config = &cfg
+		case c.OCI != nil:
+			cfg, err := fromV1alpha1.toOCIConfig(c.OCI)
+			if err != nil {
+				return types.Profile{}, errors.Join(err, ErrConvertingProfile)
+			}
+
+			content.ResolverKind = types.OCIResolverKind
+			content.OCIConfig = &cfg
+		case c.Git != nil:
+			cfg, err := fromV1alpha1.toGitConfig(c.Git)
+			if err != nil {
+				return types.Profile{}, errors.Join(err, ErrConvertingProfile)
+			}
+
+			content.ResolverKind = types.GitResolverKind
+			content.GitConfig = &cfg
 		}
 
 		// 4. Add content to the map.
@@ -180,6 +234,18 @@ func (ipxev1a1) toProfile(input *v1alpha1.Profile) (types.Profile, error) {
 	return out, nil
 }
 
+// hashContent returns the hexadecimal sha256 checksum of the specification of the content.
+func hashContent(c v1alpha1.AdditionalContent) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
 var errConvertingObjectRef = errors.New("converting object ref")
 
 func (ipxev1a1) toObjectRef(objectRef *v1alpha1.ObjectRef) (types.ObjectRef, error) {
@@ -195,6 +261,9 @@ func (ipxev1a1) toObjectRef(objectRef *v1alpha1.ObjectRef) (types.ObjectRef, err
 		Namespace: objectRef.Namespace,
 		Name:      objectRef.Name,
 		JSONPath:  jp,
+
+		Encoding:      types.ObjectRefEncoding(objectRef.Encoding),
+		JoinSeparator: objectRef.JoinSeparator,
 	}, nil
 }
 
@@ -230,6 +299,8 @@ var errConvertingWebhookConfig = errors.New("converting webhook config")
 func (ipxev1a1) toWebhookConfig(input *v1alpha1.WebhookConfig) (types.WebhookConfig, error) {
 	out := types.WebhookConfig{}
 	out.URL = input.URL
+	out.Headers = maps.Clone(input.Headers)
+	out.QueryParams = maps.Clone(input.QueryParams)
 
 	if input.MTLSObjectRef != nil {
 		ref, err := fromV1alpha1.toMTLSObjectRef(input.MTLSObjectRef)
@@ -249,6 +320,63 @@ func (ipxev1a1) toWebhookConfig(input *v1alpha1.WebhookConfig) (types.WebhookCon
 		out.BasicAuthObjectRef = ref
 	}
 
+	if input.BearerTokenObjectRef != nil {
+		ref, err := fromV1alpha1.toBearerTokenObjectRef(input.BearerTokenObjectRef)
+		if err != nil {
+			return types.WebhookConfig{}, errors.Join(err, errConvertingWebhookConfig)
+		}
+
+		out.BearerTokenObjectRef = ref
+	}
+
+	out.Timeout = types.DefaultWebhookTimeout
+	if input.TimeoutSeconds != nil {
+		out.Timeout = time.Duration(*input.TimeoutSeconds) * time.Second
+	}
+
+	out.Retries = types.DefaultWebhookRetries
+	if input.Retries != nil {
+		out.Retries = int(*input.Retries)
+	}
+
+	out.MaxBodySize = types.DefaultWebhookMaxBodySize
+	if input.MaxBodySizeBytes != nil {
+		out.MaxBodySize = *input.MaxBodySizeBytes
+	}
+
+	return out, nil
+}
+
+var errConvertingHTTPConfig = errors.New("converting http config")
+
+func (ipxev1a1) toHTTPConfig(input *v1alpha1.HTTPConfig) (types.HTTPConfig, error) {
+	out := types.HTTPConfig{}
+	out.URL = input.URL
+	out.Checksum = input.Checksum
+
+	out.MaxBodySize = types.DefaultHTTPMaxBodySize
+	if input.MaxBodySizeBytes != nil {
+		out.MaxBodySize = *input.MaxBodySizeBytes
+	}
+
+	if input.MTLSObjectRef != nil {
+		ref, err := fromV1alpha1.toMTLSObjectRef(input.MTLSObjectRef)
+		if err != nil {
+			return types.HTTPConfig{}, errors.Join(err, errConvertingHTTPConfig)
+		}
+
+		out.MTLSObjectRef = ref
+	}
+
+	if input.BasicAuthObjectRef != nil {
+		ref, err := fromV1alpha1.toBasicAuthObjectRef(input.BasicAuthObjectRef)
+		if err != nil {
+			return types.HTTPConfig{}, errors.Join(err, errConvertingHTTPConfig)
+		}
+
+		out.BasicAuthObjectRef = ref
+	}
+
 	return out, nil
 }
 
@@ -313,6 +441,143 @@ func (ipxev1a1) toBasicAuthObjectRef(
 	}, nil
 }
 
+var errConvertingBearerTokenObjectRef = errors.New("converting bearer token object ref")
+
+func (ipxev1a1) toBearerTokenObjectRef(ref *v1alpha1.BearerTokenObjectRef) (*types.BearerTokenObjectRef, error) {
+	tjp, err := toJSONPath(ref.TokenJSONPath)
+	if err != nil {
+		return nil, errors.Join(err, errConvertingBearerTokenObjectRef)
+	}
+
+	return &types.BearerTokenObjectRef{
+		ObjectRef: types.ObjectRef{
+			Group:     ref.Group,
+			Version:   ref.Version,
+			Resource:  ref.Resource,
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+		},
+		TokenJSONPath: tjp,
+	}, nil
+}
+
+var errConvertingS3Config = errors.New("converting s3 config")
+
+func (ipxev1a1) toS3Config(input *v1alpha1.S3Config) (types.S3Config, error) {
+	out := types.S3Config{
+		Bucket:         input.Bucket,
+		Key:            input.Key,
+		Region:         input.Region,
+		Endpoint:       input.Endpoint,
+		ForcePathStyle: input.ForcePathStyle,
+		MaxBodySize:    types.DefaultS3MaxBodySize,
+	}
+
+	if input.MaxBodySizeBytes != nil {
+		out.MaxBodySize = *input.MaxBodySizeBytes
+	}
+
+	if ref := input.CredentialsRef; ref != nil {
+		akjp, err := toJSONPath(ref.AccessKeyIDJSONPath)
+		if err != nil {
+			return types.S3Config{}, errors.Join(err, errConvertingS3Config)
+		}
+
+		skjp, err := toJSONPath(ref.SecretAccessKeyJSONPath)
+		if err != nil {
+			return types.S3Config{}, errors.Join(err, errConvertingS3Config)
+		}
+
+		out.CredentialsRef = &types.S3CredentialsObjectRef{
+			ObjectRef: types.ObjectRef{
+				Group:     ref.Group,
+				Version:   ref.Version,
+				Resource:  ref.Resource,
+				Namespace: ref.Namespace,
+				Name:      ref.Name,
+			},
+			AccessKeyIDJSONPath:     akjp,
+			SecretAccessKeyJSONPath: skjp,
+		}
+
+		if ref.SessionTokenJSONPath != "" {
+			if out.CredentialsRef.SessionTokenJSONPath, err = toJSONPath(ref.SessionTokenJSONPath); err != nil {
+				return types.S3Config{}, errors.Join(err, errConvertingS3Config)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+var errConvertingOCIConfig = errors.New("converting oci config")
+
+func (ipxev1a1) toOCIConfig(input *v1alpha1.OCIConfig) (types.OCIConfig, error) {
+	ref, err := types.ParseOCIReference(input.Reference)
+	if err != nil {
+		return types.OCIConfig{}, errors.Join(err, errConvertingOCIConfig)
+	}
+
+	out := types.OCIConfig{
+		Reference: ref,
+		MediaType: input.MediaType,
+		PlainHTTP: input.PlainHTTP,
+	}
+
+	if input.CredentialsRef != nil {
+		if out.CredentialsRef, err = fromV1alpha1.toBasicAuthObjectRef(input.CredentialsRef); err != nil {
+			return types.OCIConfig{}, errors.Join(err, errConvertingOCIConfig)
+		}
+	}
+
+	return out, nil
+}
+
+var errConvertingGitConfig = errors.New("converting git config")
+
+func (ipxev1a1) toGitConfig(input *v1alpha1.GitConfig) (types.GitConfig, error) {
+	out := types.GitConfig{
+		URL:  input.URL,
+		Ref:  input.Ref,
+		Path: input.Path,
+	}
+
+	if input.BasicAuthObjectRef != nil {
+		ref, err := fromV1alpha1.toBasicAuthObjectRef(input.BasicAuthObjectRef)
+		if err != nil {
+			return types.GitConfig{}, errors.Join(err, errConvertingGitConfig)
+		}
+
+		out.BasicAuthObjectRef = ref
+	}
+
+	if ref := input.SSHKeyObjectRef; ref != nil {
+		pkjp, err := toJSONPath(ref.PrivateKeyJSONPath)
+		if err != nil {
+			return types.GitConfig{}, errors.Join(err, errConvertingGitConfig)
+		}
+
+		khjp, err := toJSONPath(ref.KnownHostsJSONPath)
+		if err != nil {
+			return types.GitConfig{}, errors.Join(err, errConvertingGitConfig)
+		}
+
+		out.SSHKeyObjectRef = &types.SSHKeyObjectRef{
+			ObjectRef: types.ObjectRef{
+				Group:     ref.Group,
+				Version:   ref.Version,
+				Resource:  ref.Resource,
+				Namespace: ref.Namespace,
+				Name:      ref.Name,
+			},
+			PrivateKeyJSONPath: pkjp,
+			KnownHostsJSONPath: khjp,
+		}
+	}
+
+	return out, nil
+}
+
 var errConvertingStringToJSONPath = errors.New("converting string to JSONPath")
 
 func toJSONPath(s string) (*jsonpath.JSONPath, error) {
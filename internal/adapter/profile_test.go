@@ -5,13 +5,17 @@ package adapter_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
 	"github.com/alexandremahdhaoui/ipxer/internal/util/mocks/mockclient"
 	"github.com/alexandremahdhaoui/ipxer/internal/util/testutil"
 	"github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types2 "k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -79,6 +83,50 @@ func TestProfile(t *testing.T) {
 			assert.Equal(t, expected, testutil.MakeProfileComparable(actual))
 		})
 
+		t.Run("ExposedContent", func(t *testing.T) {
+			defer setup(t)()
+
+			id := uuid.New()
+			v1alpha1Profile.Spec.AdditionalContent[0].Exposed = true
+			v1alpha1Profile.Labels = map[string]string{
+				v1alpha1.NewUUIDLabelSelector(id): v1alpha1Profile.Spec.AdditionalContent[0].Name,
+			}
+
+			expected := testutil.NewTypesProfile()
+			content := expected.AdditionalContent[v1alpha1Profile.Spec.AdditionalContent[0].Name]
+			content.Exposed = true
+			content.ExposedUUID = id
+			expected.AdditionalContent[content.Name] = content
+			expected.ContentIDToNameMap[id] = content.Name
+
+			get(t)
+
+			actual, err := profile.Get(ctx, inputProfileName)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, testutil.MakeProfileComparable(actual))
+		})
+
+		t.Run("CacheTTLAndHash", func(t *testing.T) {
+			defer setup(t)()
+
+			name := v1alpha1Profile.Spec.AdditionalContent[0].Name
+			v1alpha1Profile.Spec.AdditionalContent[0].CacheTTL = &metav1.Duration{Duration: time.Minute}
+
+			get(t)
+
+			before, err := profile.Get(ctx, inputProfileName)
+			require.NoError(t, err)
+			assert.Equal(t, time.Minute, before.AdditionalContent[name].CacheTTL)
+			assert.NotEmpty(t, before.AdditionalContent[name].Hash)
+
+			// any change to the specification of a content changes its hash.
+			v1alpha1Profile.Spec.AdditionalContent[0].Template = true
+
+			after, err := profile.Get(ctx, inputProfileName)
+			require.NoError(t, err)
+			assert.NotEqual(t, before.AdditionalContent[name].Hash, after.AdditionalContent[name].Hash)
+		})
+
 		t.Run("Failure", func(t *testing.T) {
 			t.Run("Get error", func(t *testing.T) {
 				defer setup(t)()
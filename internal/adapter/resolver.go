@@ -2,20 +2,40 @@ package adapter
 
 import (
 	"bytes"
+	"container/list"
 	"context"
-	"crypto/tls"
-	"crypto/x509"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"maps"
 	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/util/jsonpath"
 
 	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/templateutil"
+	"github.com/alexandremahdhaoui/ipxer/pkg/generated/resolverclient"
 )
 
 var (
@@ -24,6 +44,9 @@ var (
 	ErrWebhookResolver   = errors.New("resolving webhook")
 
 	errObjectRefMustBeSpecified = errors.New("object ref must be specified")
+	errJSONPathNoValue          = errors.New("jsonpath matched no value")
+	errJSONPathMultipleValues   = errors.New("jsonpath matched several values")
+	errDecodingBase64           = errors.New("decoding base64")
 	errResolvingMTLSConfig      = errors.New("resolving mTLS config")
 	errResolvingBasicAuthRef    = errors.New("resolving basic auth ref")
 
@@ -36,7 +59,7 @@ type Resolver interface {
 	Resolve(
 		ctx context.Context,
 		content types.Content,
-		attributes types.IPXESelectors,
+		attributes types.Attributes,
 	) ([]byte, error)
 }
 
@@ -50,6 +73,13 @@ type ObjectRefResolver interface {
 	) ([][]byte, error)
 }
 
+type GitResolver interface {
+	Resolver
+
+	// Revision returns the commit the content resolves to, ensuring its file exists at this commit.
+	Revision(ctx context.Context, content types.Content) (string, error)
+}
+
 // ------------------------------------------------- INLINE RESOLVER ------------------------------------------------ //
 
 func NewInlineResolver() Resolver {
@@ -61,7 +91,7 @@ type inlineResolver struct{}
 func (r *inlineResolver) Resolve(
 	_ context.Context,
 	content types.Content,
-	_ types.IPXESelectors,
+	_ types.Attributes,
 ) ([]byte, error) {
 	return []byte(content.Inline), nil
 }
@@ -79,7 +109,7 @@ type objectRefResolver struct {
 func (r *objectRefResolver) Resolve(
 	ctx context.Context,
 	content types.Content,
-	_ types.IPXESelectors,
+	_ types.Attributes,
 ) ([]byte, error) {
 	if content.ObjectRef == nil {
 		return nil, errors.Join(errObjectRefMustBeSpecified, ErrResolverResolve)
@@ -89,16 +119,15 @@ func (r *objectRefResolver) Resolve(
 
 	out, err := r.ResolvePaths(ctx, []*jsonpath.JSONPath{ref.JSONPath}, ref)
 	if err != nil {
-		return nil, err // TODO: wrap
-	}
-
-	if len(out) < 1 {
-		return nil, errors.New("TODO") // TODO
+		return nil, errors.Join(err, ErrResolverResolve)
 	}
 
 	return out[0], nil
 }
 
+// ResolvePaths returns the value matched by each path in the referenced resource, decoded as specified by the
+// encoding of ref. A path matching no value is an error, and so is a path matching several values unless ref
+// specifies a join separator.
 func (r *objectRefResolver) ResolvePaths(
 	ctx context.Context,
 	paths []*jsonpath.JSONPath,
@@ -116,46 +145,134 @@ func (r *objectRefResolver) ResolvePaths(
 		return nil, errors.Join(err, ErrObjectRefResolver)
 	}
 
+	out, err := objectValues(obj.Object, paths, ref)
+	if err != nil {
+		return nil, errors.Join(err, ErrObjectRefResolver)
+	}
+
+	return out, nil
+}
+
+// objectValues returns the value matched by each path in the object, decoded as specified by the encoding of ref.
+func objectValues(obj map[string]any, paths []*jsonpath.JSONPath, ref types.ObjectRef) ([][]byte, error) {
+	data := obj
+
+	// the data of secrets are decoded beforehand, so paths to other fields, e.g. to labels, are read as is.
+	if ref.Encoding == types.ObjectRefDefaultEncoding && isSecret(ref) {
+		var err error
+		if data, err = decodeSecretData(obj); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([][]byte, 0, len(paths))
+
+	for i, path := range paths {
+		values, err := jsonPathValues(path, data)
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("jsonpath %d of %s", i, ref.Name))
+		}
+
+		if ref.Encoding == types.ObjectRefBase64Encoding {
+			for j, value := range values {
+				if values[j], err = base64.StdEncoding.DecodeString(string(value)); err != nil {
+					return nil, errors.Join(err, errDecodingBase64)
+				}
+			}
+		}
+
+		switch {
+		case len(values) == 0:
+			return nil, errors.Join(fmt.Errorf("jsonpath %d of %s", i, ref.Name), errJSONPathNoValue)
+		case len(values) > 1 && ref.JoinSeparator == nil:
+			return nil, errors.Join(
+				fmt.Errorf("jsonpath %d of %s: got: %d values; want: 1 value", i, ref.Name, len(values)),
+				errors.New("a join separator must be specified to join several values"),
+				errJSONPathMultipleValues,
+			)
+		case len(values) > 1:
+			out = append(out, bytes.Join(values, []byte(*ref.JoinSeparator)))
+		default:
+			out = append(out, values[0])
+		}
+	}
+
+	return out, nil
+}
+
+// jsonPathValues returns the values matched by the path, printed as the path would print them.
+func jsonPathValues(path *jsonpath.JSONPath, data map[string]any) ([][]byte, error) {
+	results, err := path.FindResults(data)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
 	out := make([][]byte, 0)
 
-	for _, path := range paths {
-		buf := bytes.NewBuffer(make([]byte, 0))
-		if err := path.Execute(buf, obj.Object); err != nil {
-			return nil, errors.Join(err, ErrObjectRefResolver)
+	for _, result := range results {
+		for _, value := range result {
+			buf := bytes.NewBuffer(make([]byte, 0))
+			if err := path.PrintResults(buf, []reflect.Value{value}); err != nil {
+				return nil, err //nolint:wrapcheck
+			}
+
+			out = append(out, buf.Bytes())
 		}
+	}
+
+	return out, nil
+}
+
+func isSecret(ref types.ObjectRef) bool {
+	return ref.Group == "" && ref.Version == "v1" && ref.Resource == "secrets"
+}
+
+// decodeSecretData returns a shallow copy of the secret whose data are decoded from base64.
+func decodeSecretData(secret map[string]any) (map[string]any, error) {
+	data, ok := secret["data"].(map[string]any)
+	if !ok {
+		return secret, nil
+	}
 
-		out = append(out, buf.Bytes())
+	decoded := make(map[string]any, len(data))
+
+	for key, value := range data {
+		s, ok := value.(string)
+		if !ok {
+			decoded[key] = value
+			continue
+		}
+
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, errors.Join(err, fmt.Errorf("secret data %q", key), errDecodingBase64)
+		}
+
+		decoded[key] = string(b)
 	}
 
+	out := maps.Clone(secret)
+	out["data"] = decoded
+
 	return out, nil
 }
 
 // ------------------------------------------------ WEBHOOK RESOLVER ------------------------------------------------ //
 
-const (
-	buildarchParam = "buildarch"
-	uuidParam      = "uuid"
-)
-
-// NewWebhookResolver requires a k8sClient in order to resolve object reference if needed.
-func NewWebhookResolver(resolver ObjectRefResolver) Resolver {
-	return &webhookResolver{objectRefResolver: resolver}
+// NewWebhookResolver requires a WebhookClient in order to call the webhook with its credentials.
+func NewWebhookResolver(client WebhookClient) Resolver {
+	return &webhookResolver{client: client}
 }
 
 type webhookResolver struct {
-	objectRefResolver ObjectRefResolver
-
-	// Allow GLOBALLY disabling
-	disableTLSInsecureSkipVerify bool
+	client WebhookClient
 }
 
 func (r *webhookResolver) Resolve(
 	ctx context.Context,
 	content types.Content,
-	attributes types.IPXESelectors,
+	attributes types.Attributes,
 ) ([]byte, error) {
-	// TODO: make use of content.WebhookConfig.MTLSObjectRef.TLSInsecureSkipVerify
-
 	if content.WebhookConfig == nil {
 		return nil, errors.Join(
 			errWebhookConfigShouldNotBeNil,
@@ -164,121 +281,1258 @@ func (r *webhookResolver) Resolve(
 		)
 	}
 
-	url := fmt.Sprintf("https://%s?%s=%s&%s=%s",
-		content.WebhookConfig.URL,
-		buildarchParam, attributes.Buildarch,
-		uuidParam, attributes.UUID,
-	)
+	out, err := r.resolve(ctx, *content.WebhookConfig, attributes)
+	if errors.Is(err, ErrWebhookNotFound) && content.NotFoundAsEmpty {
+		return []byte{}, nil
+	} else if err != nil {
+		return nil, errors.Join(err, ErrWebhookResolver, ErrResolverResolve)
+	}
+
+	return out, nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+func (r *webhookResolver) resolve(
+	ctx context.Context,
+	cfg types.WebhookConfig,
+	attributes types.Attributes,
+) ([]byte, error) {
+	doer, err := r.client.Doer(ctx, cfg)
 	if err != nil {
-		return nil, errors.Join(err, ErrWebhookResolver, ErrResolverResolve)
+		return nil, err //nolint:wrapcheck
 	}
 
-	httpClient := new(http.Client)
-	if err := r.mTLSConfig(ctx, httpClient, content.WebhookConfig.MTLSObjectRef); err != nil {
-		return nil, errors.Join(err, ErrWebhookResolver, ErrResolverResolve)
+	server, route, err := webhookEndpoint(cfg.URL)
+	if err != nil {
+		return nil, errors.Join(err, ErrWebhookClient)
 	}
 
-	if err := r.setBasicAuth(ctx, req, content.WebhookConfig.BasicAuthObjectRef); err != nil {
-		return nil, errors.Join(err, ErrWebhookResolver, ErrResolverResolve)
+	cl, err := resolverclient.NewClientWithResponses(server, resolverclient.WithHTTPClient(doer))
+	if err != nil {
+		return nil, errors.Join(err, ErrWebhookClient)
+	}
+
+	resp, err := cl.ResolveWithResponse(ctx, route, &resolverclient.ResolveParams{
+		Uuid:       attributes.UUID,
+		Buildarch:  resolverclient.Buildarch(attributes.Buildarch),
+		Attributes: newWebhookAttributes(attributes),
+	})
+	if err != nil {
+		return nil, errors.Join(err, ErrWebhookClient)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, newWebhookError(resp.StatusCode(), resp.Body)
+	}
+
+	if resp.JSON200 == nil {
+		return nil, errors.Join(errWebhookResponseNotJSON, ErrWebhookClient)
+	}
+
+	if resp.JSON200.Data == nil {
+		return []byte{}, nil
+	}
+
+	return []byte(*resp.JSON200.Data), nil
+}
+
+// ------------------------------------------------- HTTP RESOLVER -------------------------------------------------- //
+
+var (
+	ErrHTTPResolver = errors.New("resolving http content")
+
+	errHTTPConfigShouldNotBeNil = errors.New("http config should not be nil")
+	errHTTPResponseTooLarge     = errors.New("http response exceeds the maximum body size")
+	errUnexpectedStatusCode     = errors.New("unexpected status code")
+	errUnsupportedChecksum      = errors.New("unsupported checksum")
+	errChecksumMismatch         = errors.New("checksum mismatch")
+)
+
+// NewHTTPResolver returns a Resolver fetching plain remote files. Responses are revalidated using their ETag or
+// Last-Modified headers, hence unchanged files are not downloaded again. The least recently used responses are evicted
+// once they exceed maxCacheSize bytes.
+func NewHTTPResolver(credentials CredentialCache, maxCacheSize int64) Resolver {
+	return &httpResolver{
+		credentials: credentials,
+		maxSize:     maxCacheSize,
+		responses:   make(map[string]*list.Element),
+		lru:         list.New(),
+	}
+}
+
+type httpResolver struct {
+	credentials CredentialCache
+	maxSize     int64
+
+	// responses holds the last response of the URLs which can be revalidated, the most recently used first in lru.
+	mu        sync.Mutex
+	responses map[string]*list.Element
+	lru       *list.List
+	size      int64
+}
+
+type httpResponse struct {
+	url          string
+	etag         string
+	lastModified string
+	body         []byte
+}
+
+func (r *httpResolver) Resolve(
+	ctx context.Context,
+	content types.Content,
+	_ types.Attributes,
+) ([]byte, error) {
+	cfg := content.HTTPConfig
+	if cfg == nil {
+		return nil, errors.Join(errHTTPConfigShouldNotBeNil, ErrHTTPResolver, ErrResolverResolve)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.URL, nil)
+	if err != nil {
+		return nil, errors.Join(err, ErrHTTPResolver, ErrResolverResolve)
+	}
+
+	httpClient, err := newHTTPClient(ctx, r.credentials, cfg.MTLSObjectRef)
+	if err != nil {
+		return nil, errors.Join(err, ErrHTTPResolver, ErrResolverResolve)
+	}
+
+	if err := setBasicAuth(ctx, r.credentials, req, cfg.BasicAuthObjectRef); err != nil {
+		return nil, errors.Join(err, ErrHTTPResolver, ErrResolverResolve)
+	}
+
+	cached, ok := r.cachedResponse(cfg.URL)
+	if ok {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
 	}
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, errors.Join(err, ErrWebhookResolver, ErrResolverResolve)
+		return nil, errors.Join(err, ErrHTTPResolver, ErrResolverResolve)
 	}
 
 	defer resp.Body.Close()
-	out, err := io.ReadAll(resp.Body)
+
+	var out []byte
+
+	switch {
+	case ok && resp.StatusCode == http.StatusNotModified:
+		out = cached.body
+	case resp.StatusCode == http.StatusOK:
+		maxBodySize := cfg.MaxBodySize
+		if maxBodySize <= 0 {
+			maxBodySize = types.DefaultHTTPMaxBodySize
+		}
+
+		if out, err = readBody(resp.Body, maxBodySize, errHTTPResponseTooLarge); err != nil {
+			return nil, errors.Join(err, fmt.Errorf("GET %s", cfg.URL), ErrHTTPResolver, ErrResolverResolve)
+		}
+	default:
+		return nil, errors.Join(
+			fmt.Errorf("GET %s: %s", cfg.URL, resp.Status),
+			errUnexpectedStatusCode,
+			ErrHTTPResolver,
+			ErrResolverResolve,
+		)
+	}
+
+	if err := verifyChecksum(out, cfg.Checksum); err != nil {
+		return nil, errors.Join(err, ErrHTTPResolver, ErrResolverResolve)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		r.storeResponse(cfg.URL, resp.Header, out)
+	}
+
+	return out, nil
+}
+
+// readBody reads up to maxBodySize bytes of the body, failing with errTooLarge if the body is larger.
+func readBody(body io.Reader, maxBodySize int64, errTooLarge error) ([]byte, error) {
+	out, err := io.ReadAll(io.LimitReader(body, maxBodySize+1))
 	if err != nil {
-		return nil, errors.Join(err, ErrWebhookResolver, ErrResolverResolve)
+		return nil, err //nolint:wrapcheck
+	}
+
+	if int64(len(out)) > maxBodySize {
+		return nil, errors.Join(fmt.Errorf("got: more than %d bytes", maxBodySize), errTooLarge)
 	}
 
 	return out, nil
 }
 
-// TODO: lru cache that config?
-func (r *webhookResolver) mTLSConfig(
-	ctx context.Context,
-	httpClient *http.Client,
-	ref *types.MTLSObjectRef,
-) error {
-	if ref == nil {
+// cachedResponse returns the last response of the URL, marking it as the most recently used.
+func (r *httpResolver) cachedResponse(url string) (httpResponse, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	elem, ok := r.responses[url]
+	if !ok {
+		return httpResponse{}, false
+	}
+
+	r.lru.MoveToFront(elem)
+
+	return *elem.Value.(*httpResponse), true //nolint:forcetypeassert
+}
+
+// storeResponse stores the response of the URL if it can be revalidated, or forgets the previous one otherwise. The
+// least recently used responses are evicted while the responses exceed the maximum size.
+func (r *httpResolver) storeResponse(url string, header http.Header, body []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if elem, ok := r.responses[url]; ok {
+		r.remove(elem)
+	}
+
+	etag, lastModified := header.Get("ETag"), header.Get("Last-Modified")
+	if (etag == "" && lastModified == "") || int64(len(body)) > r.maxSize {
+		return
+	}
+
+	r.responses[url] = r.lru.PushFront(&httpResponse{url: url, etag: etag, lastModified: lastModified, body: body})
+	r.size += int64(len(body))
+
+	for r.size > r.maxSize {
+		r.remove(r.lru.Back())
+	}
+}
+
+func (r *httpResolver) remove(elem *list.Element) {
+	resp := r.lru.Remove(elem).(*httpResponse) //nolint:forcetypeassert
+	delete(r.responses, resp.url)
+	r.size -= int64(len(resp.body))
+}
+
+// verifyChecksum ensures the checksum of b matches checksum, formatted as `<algorithm>:<hex digest>`. An empty checksum
+// is not verified.
+func verifyChecksum(b []byte, checksum string) error {
+	if checksum == "" {
 		return nil
 	}
 
-	paths := []*jsonpath.JSONPath{
-		ref.ClientKeyJSONPath,
-		ref.ClientCertJSONPath,
-		ref.CaBundleJSONPath,
+	h, err := checksumHash(checksum)
+	if err != nil {
+		return err
 	}
 
-	res, err := r.objectRefResolver.ResolvePaths(ctx, paths, ref.ObjectRef)
+	h.Write(b)
+
+	return matchChecksum(h, checksum)
+}
+
+// checksumHash returns the hash of the algorithm of the checksum.
+func checksumHash(checksum string) (hash.Hash, error) {
+	switch algorithm, _, _ := strings.Cut(checksum, ":"); algorithm {
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	default:
+		return nil, errors.Join(fmt.Errorf("algorithm %q", algorithm), errUnsupportedChecksum)
+	}
+}
+
+// matchChecksum ensures the sum of h matches the checksum.
+func matchChecksum(h hash.Hash, checksum string) error {
+	algorithm, digest, _ := strings.Cut(checksum, ":")
+
+	if actual := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(actual, digest) {
+		return errors.Join(fmt.Errorf("expected %s; got %s:%s", checksum, algorithm, actual), errChecksumMismatch)
+	}
+
+	return nil
+}
+
+// -------------------------------------------------- S3 RESOLVER --------------------------------------------------- //
+
+const defaultS3Region = "us-east-1"
+
+var (
+	ErrS3Resolver = errors.New("resolving s3 content")
+
+	errS3ConfigShouldNotBeNil = errors.New("s3 config should not be nil")
+	errRenderingS3Key         = errors.New("rendering s3 key")
+	errResolvingS3Credentials = errors.New("resolving s3 credentials")
+	errS3ObjectTooLarge       = errors.New("s3 object exceeds the maximum body size")
+)
+
+// NewS3Resolver returns a Resolver reading objects from S3-compatible object storages. The resolver requires an
+// ObjectRefResolver to resolve the credentials. Clients are cached per endpoint, region and credentials, and share a
+// transport pooling keep-alive connections.
+func NewS3Resolver(resolver ObjectRefResolver) Resolver {
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+
+	return &s3Resolver{
+		objectRefResolver: resolver,
+		httpClient:        &http.Client{Transport: transport},
+		clients:           make(map[s3ClientKey]cachedS3Client),
+	}
+}
+
+type s3Resolver struct {
+	objectRefResolver ObjectRefResolver
+	httpClient        *http.Client
+
+	mu      sync.Mutex
+	clients map[s3ClientKey]cachedS3Client
+}
+
+// s3ClientKey identifies the object storage a client connects to and the resource its credentials are read from, i.e.
+// the zero objectKey for anonymous clients.
+type s3ClientKey struct {
+	endpoint       string
+	region         string
+	forcePathStyle bool
+	credentials    objectKey
+}
+
+type cachedS3Client struct {
+	// digest of the credentials, replacing the client once they are rotated.
+	digest string
+	client *s3.Client
+}
+
+func (r *s3Resolver) Resolve(
+	ctx context.Context,
+	content types.Content,
+	attributes types.Attributes,
+) ([]byte, error) {
+	cfg := content.S3Config
+	if cfg == nil {
+		return nil, errors.Join(errS3ConfigShouldNotBeNil, ErrS3Resolver, ErrResolverResolve)
+	}
+
+	key, err := renderS3Key(cfg.Key, attributes.Machine)
 	if err != nil {
-		return errors.Join(err, errResolvingMTLSConfig)
+		return nil, errors.Join(err, ErrS3Resolver, ErrResolverResolve)
 	}
 
-	if nRes := len(res); nRes < 3 {
-		return errors.Join(
-			fmt.Errorf("expected: 3 results; actual: %d results", nRes),
-			errors.New(
-				"mTLS configuration expected 1 client key, 1 client crt, and 1 ca bundle/crt",
-			),
-			errResolvingMTLSConfig,
-		)
+	cl, err := r.client(ctx, cfg)
+	if err != nil {
+		return nil, errors.Join(err, ErrS3Resolver, ErrResolverResolve)
+	}
+
+	obj, err := cl.GetObject(ctx, &s3.GetObjectInput{ //nolint:exhaustruct
+		Bucket: aws.String(cfg.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("getting s3://%s/%s", cfg.Bucket, key), ErrS3Resolver, ErrResolverResolve)
+	}
+
+	defer obj.Body.Close()
+
+	maxBodySize := cfg.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = types.DefaultS3MaxBodySize
 	}
 
-	clientKey := res[0]
-	clientCert := res[1]
-	caBundle := res[2]
+	out, err := readBody(obj.Body, maxBodySize, errS3ObjectTooLarge)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("getting s3://%s/%s", cfg.Bucket, key), ErrS3Resolver, ErrResolverResolve)
+	}
 
-	caCertPool := x509.NewCertPool()
-	caCertPool.AppendCertsFromPEM(caBundle)
+	return out, nil
+}
 
-	cert, err := tls.X509KeyPair(clientCert, clientKey)
+// client returns the cached client of the object storage, authenticating with the credentials referenced by the config.
+func (r *s3Resolver) client(ctx context.Context, cfg *types.S3Config) (*s3.Client, error) {
+	provider, digest, err := r.credentials(ctx, cfg.CredentialsRef)
 	if err != nil {
-		return errors.Join(err, errResolvingMTLSConfig)
+		return nil, err
 	}
 
-	httpClient.Transport = &http.Transport{
-		TLSClientConfig: &tls.Config{
-			RootCAs:      caCertPool,
-			Certificates: []tls.Certificate{cert},
-			// disableTLSInsecureSkipVerify globally enforce tls verification. //TODO: add test cases.
-			InsecureSkipVerify: (!r.disableTLSInsecureSkipVerify) && ref.TLSInsecureSkipVerify,
-		},
+	region := cfg.Region
+	if region == "" {
+		region = defaultS3Region
 	}
 
-	return nil
+	key := s3ClientKey{endpoint: cfg.Endpoint, region: region, forcePathStyle: cfg.ForcePathStyle}
+	if cfg.CredentialsRef != nil {
+		key.credentials = keyOf(cfg.CredentialsRef.ObjectRef)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cached, ok := r.clients[key]; ok && cached.digest == digest {
+		return cached.client, nil
+	}
+
+	opts := s3.Options{ //nolint:exhaustruct
+		Region:       region,
+		Credentials:  provider,
+		UsePathStyle: cfg.ForcePathStyle,
+		HTTPClient:   r.httpClient,
+	}
+
+	if cfg.Endpoint != "" {
+		opts.BaseEndpoint = aws.String(cfg.Endpoint)
+	}
+
+	cl := s3.New(opts)
+	r.clients[key] = cachedS3Client{digest: digest, client: cl}
+
+	return cl, nil
 }
 
-func (r *webhookResolver) setBasicAuth(
+// credentials returns the static credentials referenced by ref and their digest, or anonymous credentials if ref is
+// nil.
+func (r *s3Resolver) credentials(
 	ctx context.Context,
-	req *http.Request,
-	ref *types.BasicAuthObjectRef,
-) error {
+	ref *types.S3CredentialsObjectRef,
+) (aws.CredentialsProvider, string, error) {
 	if ref == nil {
-		return nil
+		return aws.AnonymousCredentials{}, "", nil
 	}
 
-	paths := []*jsonpath.JSONPath{ref.UsernameJSONPath, ref.PasswordJSONPath}
+	paths := []*jsonpath.JSONPath{ref.AccessKeyIDJSONPath, ref.SecretAccessKeyJSONPath}
+	if ref.SessionTokenJSONPath != nil {
+		paths = append(paths, ref.SessionTokenJSONPath)
+	}
 
 	res, err := r.objectRefResolver.ResolvePaths(ctx, paths, ref.ObjectRef)
 	if err != nil {
-		return errors.Join(err, errResolvingBasicAuthRef)
+		return nil, "", errors.Join(err, errResolvingS3Credentials)
 	}
 
-	if nRes := len(res); nRes < 2 {
-		return errors.Join(
-			fmt.Errorf("got: %d results; want: 2 results", nRes),
-			errors.New("basic auth credentials expected 1 username, and 1 password"),
-			errResolvingBasicAuthRef)
+	if nRes := len(res); nRes < len(paths) {
+		return nil, "", errors.Join(
+			fmt.Errorf("got: %d results; want: %d results", nRes, len(paths)),
+			errResolvingS3Credentials,
+		)
 	}
 
-	username, password := res[0], res[1]
+	var sessionToken string
+	if len(res) > 2 {
+		sessionToken = string(res[2])
+	}
 
-	req.SetBasicAuth(string(username), string(password))
+	h := sha256.New()
+	for _, credential := range res {
+		h.Write(credential)
+		h.Write([]byte{0})
+	}
 
-	return nil
+	provider := credentials.NewStaticCredentialsProvider(string(res[0]), string(res[1]), sessionToken)
+
+	return provider, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// renderS3Key renders the key template with the facts about the booting machine.
+func renderS3Key(key string, machine types.Machine) (string, error) {
+	tpl, err := templateutil.New("key").Parse(key)
+	if err != nil {
+		return "", errors.Join(err, errRenderingS3Key)
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0))
+	if err := tpl.Execute(buf, struct{ Machine types.Machine }{Machine: machine}); err != nil {
+		return "", errors.Join(err, errRenderingS3Key)
+	}
+
+	return buf.String(), nil
+}
+
+// -------------------------------------------------- OCI RESOLVER -------------------------------------------------- //
+
+const (
+	ociImageManifestMediaType  = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestV2MediaType  = "application/vnd.docker.distribution.manifest.v2+json"
+	ociManifestMaxSize         = 4 << 20
+	ociBlobCacheFilePermission = 0o600
+	ociBlobCacheDirPermission  = 0o750
+)
+
+var (
+	ErrOCIResolver = errors.New("resolving oci content")
+
+	errOCIConfigShouldNotBeNil  = errors.New("oci config should not be nil")
+	errFetchingOCIManifest      = errors.New("fetching oci manifest")
+	errFetchingOCIBlob          = errors.New("fetching oci blob")
+	errOCILayerNotFound         = errors.New("oci layer not found")
+	errInvalidOCIDigest         = errors.New("invalid oci digest")
+	errInvalidOCISize           = errors.New("invalid oci size")
+	errOCIBlobTooLarge          = errors.New("oci blob exceeds the size of its descriptor")
+	errAuthenticatingToRegistry = errors.New("authenticating to registry")
+	errCachingOCIBlob           = errors.New("caching oci blob")
+
+	ociAuthParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// NewOCIResolver returns a Resolver pulling a layer of an OCI artifact from a registry implementing the OCI
+// distribution specification. The layer is verified against its digest and cached in blobCacheDir, hence blobs are
+// downloaded once. The manifests of artifacts referenced by digest are cached too, so their layer is served without
+// contacting the registry. The resolver requires an ObjectRefResolver to resolve the registry credentials.
+func NewOCIResolver(resolver ObjectRefResolver, blobCacheDir string) Resolver {
+	return &ociResolver{
+		objectRefResolver: resolver,
+		blobCacheDir:      blobCacheDir,
+		verified:          make(map[string]struct{}),
+	}
+}
+
+type ociResolver struct {
+	objectRefResolver ObjectRefResolver
+	blobCacheDir      string
+
+	// verified holds the digests of the cached blobs which were verified, i.e. written by this process or read once.
+	mu       sync.Mutex
+	verified map[string]struct{}
+}
+
+type ociManifest struct {
+	Layers []ociDescriptor `json:"layers"`
+}
+
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+func (r *ociResolver) Resolve(
+	ctx context.Context,
+	content types.Content,
+	_ types.Attributes,
+) ([]byte, error) {
+	cfg := content.OCIConfig
+	if cfg == nil {
+		return nil, errors.Join(errOCIConfigShouldNotBeNil, ErrOCIResolver, ErrResolverResolve)
+	}
+
+	// artifacts referenced by digest are immutable, hence their cached layer is served as is.
+	if digest := cfg.Reference.Digest; digest != "" {
+		if manifest, ok := r.cachedBlob(digest); ok {
+			if layer, err := ociLayer(manifest, cfg.MediaType); err == nil {
+				if out, ok := r.cachedBlob(layer.Digest); ok {
+					return out, nil
+				}
+			}
+		}
+	}
+
+	out, err := r.pull(ctx, cfg)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("pulling %s", cfg.Reference), ErrOCIResolver, ErrResolverResolve)
+	}
+
+	return out, nil
+}
+
+// pull pulls the layer of the artifact, unless its blob is already cached.
+func (r *ociResolver) pull(ctx context.Context, cfg *types.OCIConfig) ([]byte, error) {
+	registry := &ociRegistry{
+		httpClient: new(http.Client),
+		baseURL:    "https://" + cfg.Reference.Registry,
+		repository: cfg.Reference.Repository,
+	}
+
+	if cfg.PlainHTTP {
+		registry.baseURL = "http://" + cfg.Reference.Registry
+	}
+
+	if cfg.CredentialsRef != nil {
+		var err error
+		if registry.username, registry.password, err = resolveBasicAuth(
+			ctx,
+			r.objectRefResolver,
+			cfg.CredentialsRef,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	manifest, err := registry.manifest(ctx, cfg.Reference)
+	if err != nil {
+		return nil, err
+	}
+
+	layer, err := ociLayer(manifest, cfg.MediaType)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Reference.Digest != "" {
+		if err := r.cacheBlob(cfg.Reference.Digest, int64(len(manifest)), bytes.NewReader(manifest)); err != nil {
+			return nil, err
+		}
+	}
+
+	if out, ok := r.cachedBlob(layer.Digest); ok {
+		return out, nil
+	}
+
+	body, err := registry.blob(ctx, layer.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	defer body.Close()
+
+	if err := r.cacheBlob(layer.Digest, layer.Size, body); err != nil {
+		return nil, errors.Join(err, errFetchingOCIBlob)
+	}
+
+	out, err := os.ReadFile(r.blobPath(layer.Digest))
+	if err != nil {
+		return nil, errors.Join(err, errCachingOCIBlob)
+	}
+
+	return out, nil
+}
+
+// cachedBlob returns the cached blob of the digest. Blobs are verified once, e.g. the blobs cached by a previous run,
+// and blobs which do not match their digest are ignored.
+func (r *ociResolver) cachedBlob(digest string) ([]byte, bool) {
+	b, err := os.ReadFile(r.blobPath(digest))
+	if err != nil {
+		return nil, false
+	}
+
+	r.mu.Lock()
+	_, ok := r.verified[digest]
+	r.mu.Unlock()
+
+	if ok {
+		return b, true
+	}
+
+	if verifyChecksum(b, digest) != nil {
+		return nil, false
+	}
+
+	r.markVerified(digest)
+
+	return b, true
+}
+
+func (r *ociResolver) markVerified(digest string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.verified[digest] = struct{}{}
+}
+
+// cacheBlob streams the blob to the cache, verifying it against its digest and its size. The blob is written
+// atomically, so concurrent resolutions never read partially written blobs.
+func (r *ociResolver) cacheBlob(digest string, size int64, blob io.Reader) error {
+	h, err := checksumHash(digest)
+	if err != nil {
+		return errors.Join(err, errCachingOCIBlob)
+	}
+
+	path := r.blobPath(digest)
+
+	if err := os.MkdirAll(filepath.Dir(path), ociBlobCacheDirPermission); err != nil {
+		return errors.Join(err, errCachingOCIBlob)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".blob-*")
+	if err != nil {
+		return errors.Join(err, errCachingOCIBlob)
+	}
+
+	defer os.Remove(f.Name()) //nolint:errcheck // the file does not exist once renamed.
+
+	n, err := io.Copy(io.MultiWriter(f, h), io.LimitReader(blob, size+1))
+	if err != nil {
+		_ = f.Close()
+		return errors.Join(err, errCachingOCIBlob)
+	}
+
+	if err := f.Close(); err != nil {
+		return errors.Join(err, errCachingOCIBlob)
+	}
+
+	if n > size {
+		return errors.Join(fmt.Errorf("got: more than %d bytes", size), errOCIBlobTooLarge)
+	}
+
+	if err := matchChecksum(h, digest); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(f.Name(), ociBlobCacheFilePermission); err != nil {
+		return errors.Join(err, errCachingOCIBlob)
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		return errors.Join(err, errCachingOCIBlob)
+	}
+
+	r.markVerified(digest)
+
+	return nil
+}
+
+// blobPath returns the path of the blob in the cache, i.e. `<blobCacheDir>/<algorithm>/<hex digest>`. Digests are
+// validated beforehand, hence cannot escape the cache directory.
+func (r *ociResolver) blobPath(digest string) string {
+	algorithm, hexDigest, _ := strings.Cut(digest, ":")
+
+	return filepath.Join(r.blobCacheDir, algorithm, hexDigest)
+}
+
+// ociRegistry pulls the manifests and blobs of a repository, authenticating with basic auth or with a bearer token
+// as challenged by the registry.
+type ociRegistry struct {
+	httpClient *http.Client
+	baseURL    string
+	repository string
+
+	username string
+	password string
+
+	useBasicAuth bool
+	token        string
+}
+
+// manifest returns the manifest of the artifact, verified against the digest of the reference if any.
+func (c *ociRegistry) manifest(ctx context.Context, ref types.OCIReference) ([]byte, error) {
+	resp, err := c.get(
+		ctx,
+		fmt.Sprintf("/v2/%s/manifests/%s", c.repository, ref.Reference()),
+		ociImageManifestMediaType+", "+dockerManifestV2MediaType,
+	)
+	if err != nil {
+		return nil, errors.Join(err, errFetchingOCIManifest)
+	}
+
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, ociManifestMaxSize))
+	if err != nil {
+		return nil, errors.Join(err, errFetchingOCIManifest)
+	}
+
+	// manifests pulled by tag can only be verified against the digest advertised by the registry, which is pointless.
+	if ref.Digest != "" {
+		if err := verifyChecksum(b, ref.Digest); err != nil {
+			return nil, errors.Join(err, errFetchingOCIManifest)
+		}
+	}
+
+	return b, nil
+}
+
+// blob returns the body of the blob of the digest, which must be verified by the caller.
+func (c *ociRegistry) blob(ctx context.Context, digest string) (io.ReadCloser, error) {
+	resp, err := c.get(ctx, fmt.Sprintf("/v2/%s/blobs/%s", c.repository, digest), "")
+	if err != nil {
+		return nil, errors.Join(err, errFetchingOCIBlob)
+	}
+
+	return resp.Body, nil
+}
+
+// ociLayer returns the descriptor of the single layer of the manifest matching the media type.
+func ociLayer(b []byte, mediaType string) (ociDescriptor, error) {
+	manifest := ociManifest{}
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return ociDescriptor{}, errors.Join(err, errFetchingOCIManifest)
+	}
+
+	var out []ociDescriptor
+
+	for _, layer := range manifest.Layers {
+		if layer.MediaType == mediaType {
+			out = append(out, layer)
+		}
+	}
+
+	if len(out) != 1 {
+		return ociDescriptor{}, errors.Join(
+			fmt.Errorf("got: %d layers of media type %q; want: 1 layer", len(out), mediaType),
+			errOCILayerNotFound,
+		)
+	}
+
+	if !types.IsOCIDigest(out[0].Digest) {
+		return ociDescriptor{}, errors.Join(fmt.Errorf("layer digest %q", out[0].Digest), errInvalidOCIDigest)
+	}
+
+	if out[0].Size <= 0 {
+		return ociDescriptor{}, errors.Join(fmt.Errorf("layer size %d", out[0].Size), errInvalidOCISize)
+	}
+
+	return out[0], nil
+}
+
+// get requests the path of the registry, authenticating once if challenged. The response status must be OK.
+func (c *ociRegistry) get(ctx context.Context, path, accept string) (*http.Response, error) {
+	resp, err := c.do(ctx, path, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+
+		if err := c.authenticate(ctx, challenge); err != nil {
+			return nil, err
+		}
+
+		if resp, err = c.do(ctx, path, accept); err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.Join(fmt.Errorf("GET %s: %s", path, resp.Status), errUnexpectedStatusCode)
+	}
+
+	return resp, nil
+}
+
+func (c *ociRegistry) do(ctx context.Context, path, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.useBasicAuth:
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	return c.httpClient.Do(req) //nolint:wrapcheck
+}
+
+// authenticate answers the `WWW-Authenticate` challenge of the registry, either by using basic auth or by requesting
+// a bearer token from the advertised realm.
+func (c *ociRegistry) authenticate(ctx context.Context, challenge string) error {
+	scheme, rawParams, _ := strings.Cut(challenge, " ")
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if c.username == "" || c.useBasicAuth {
+			return errors.Join(errors.New("registry credentials denied or missing"), errAuthenticatingToRegistry)
+		}
+
+		c.useBasicAuth = true
+
+		return nil
+	case "bearer":
+		params := make(map[string]string)
+		for _, match := range ociAuthParamRegex.FindAllStringSubmatch(rawParams, -1) {
+			params[strings.ToLower(match[1])] = match[2]
+		}
+
+		return c.requestToken(ctx, params)
+	default:
+		return errors.Join(fmt.Errorf("unsupported challenge %q", challenge), errAuthenticatingToRegistry)
+	}
+}
+
+func (c *ociRegistry) requestToken(ctx context.Context, params map[string]string) error {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return errors.Join(fmt.Errorf("invalid realm %q", params["realm"]), errAuthenticatingToRegistry)
+	}
+
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", c.repository)
+	}
+
+	query := realm.Query()
+	query.Set("scope", scope)
+
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return errors.Join(err, errAuthenticatingToRegistry)
+	}
+
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Join(err, errAuthenticatingToRegistry)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Join(
+			fmt.Errorf("GET %s: %s", realm.Redacted(), resp.Status),
+			errUnexpectedStatusCode,
+			errAuthenticatingToRegistry,
+		)
+	}
+
+	token := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return errors.Join(err, errAuthenticatingToRegistry)
+	}
+
+	if c.token = token.Token; c.token == "" {
+		c.token = token.AccessToken
+	}
+
+	if c.token == "" {
+		return errors.Join(errors.New("registry returned an empty token"), errAuthenticatingToRegistry)
+	}
+
+	return nil
+}
+
+// -------------------------------------------------- GIT RESOLVER -------------------------------------------------- //
+
+const (
+	// gitRemoteHEAD is the ref the default branch of the remote is fetched to.
+	gitRemoteHEAD = "refs/ipxer/HEAD"
+
+	gitCredentialsDirPermission  = 0o700
+	gitCredentialsFilePermission = 0o600
+)
+
+var (
+	ErrGitResolver = errors.New("resolving git content")
+
+	errGitConfigShouldNotBeNil = errors.New("git config should not be nil")
+	errFetchingGitRepository   = errors.New("fetching git repository")
+	errResolvingGitRef         = errors.New("resolving git ref")
+	errReadingGitFile          = errors.New("reading git file")
+	errResolvingSSHKeyRef      = errors.New("resolving ssh key ref")
+)
+
+// NewGitResolver returns a GitResolver reading files of Git repositories with the git CLI. Repositories are kept as
+// bare clones in cacheDir, which are fetched at most once per fetchInterval unless the ref is unknown to the clone.
+// The resolver requires an ObjectRefResolver to resolve the credentials.
+func NewGitResolver(resolver ObjectRefResolver, cacheDir string, fetchInterval time.Duration) GitResolver {
+	return &gitResolver{
+		objectRefResolver: resolver,
+		cacheDir:          cacheDir,
+		fetchInterval:     fetchInterval,
+		repositories:      make(map[string]*gitRepository),
+	}
+}
+
+type gitResolver struct {
+	objectRefResolver ObjectRefResolver
+	cacheDir          string
+	fetchInterval     time.Duration
+
+	mu           sync.Mutex
+	repositories map[string]*gitRepository
+}
+
+// gitRepository is the bare clone of a repository. Its mutex serializes the git commands run in the clone.
+type gitRepository struct {
+	mu        sync.Mutex
+	dir       string
+	lastFetch time.Time
+}
+
+func (r *gitResolver) Resolve(
+	ctx context.Context,
+	content types.Content,
+	_ types.Attributes,
+) ([]byte, error) {
+	cfg := content.GitConfig
+	if cfg == nil {
+		return nil, errors.Join(errGitConfigShouldNotBeNil, ErrGitResolver, ErrResolverResolve)
+	}
+
+	repo := r.repository(cfg.URL)
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	commit, err := r.resolveCommit(ctx, repo, cfg)
+	if err != nil {
+		return nil, errors.Join(err, ErrGitResolver, ErrResolverResolve)
+	}
+
+	out, err := runGit(ctx, repo.dir, nil, "cat-file", "blob", gitObject(commit, cfg.Path))
+	if err != nil {
+		return nil, errors.Join(
+			err,
+			fmt.Errorf("%s at %s", cfg.Path, commit),
+			errReadingGitFile,
+			ErrGitResolver,
+			ErrResolverResolve,
+		)
+	}
+
+	return out, nil
+}
+
+func (r *gitResolver) Revision(ctx context.Context, content types.Content) (string, error) {
+	cfg := content.GitConfig
+	if cfg == nil {
+		return "", errors.Join(errGitConfigShouldNotBeNil, ErrGitResolver)
+	}
+
+	repo := r.repository(cfg.URL)
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	commit, err := r.resolveCommit(ctx, repo, cfg)
+	if err != nil {
+		return "", errors.Join(err, ErrGitResolver)
+	}
+
+	if _, err := runGit(ctx, repo.dir, nil, "cat-file", "-e", gitObject(commit, cfg.Path)); err != nil {
+		return "", errors.Join(err, fmt.Errorf("%s at %s", cfg.Path, commit), errReadingGitFile, ErrGitResolver)
+	}
+
+	return commit, nil
+}
+
+// repository returns the bare clone of the repository, which is located at `<cacheDir>/<sha256 of the url>`.
+func (r *gitResolver) repository(url string) *gitRepository {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	repo, ok := r.repositories[url]
+	if !ok {
+		sum := sha256.Sum256([]byte(url))
+		repo = &gitRepository{dir: filepath.Join(r.cacheDir, hex.EncodeToString(sum[:]))}
+		r.repositories[url] = repo
+	}
+
+	return repo
+}
+
+// resolveCommit returns the commit the ref of cfg resolves to, fetching the repository if the clone is older than the
+// fetch interval. Refs unknown to the clone trigger a fetch, e.g. a tag which was just pushed. The repository must be
+// locked.
+func (r *gitResolver) resolveCommit(ctx context.Context, repo *gitRepository, cfg *types.GitConfig) (string, error) {
+	ref := cfg.Ref
+	if ref == "" {
+		ref = gitRemoteHEAD
+	}
+
+	fetched := false
+	if time.Since(repo.lastFetch) > r.fetchInterval {
+		if err := r.fetch(ctx, repo, cfg); err != nil {
+			return "", err
+		}
+
+		fetched = true
+	}
+
+	out, err := runGit(ctx, repo.dir, nil, "rev-parse", "--verify", "--quiet", "--end-of-options", ref+"^{commit}")
+	if err != nil && !fetched {
+		if err := r.fetch(ctx, repo, cfg); err != nil {
+			return "", err
+		}
+
+		out, err = runGit(ctx, repo.dir, nil, "rev-parse", "--verify", "--quiet", "--end-of-options", ref+"^{commit}")
+	}
+
+	if err != nil {
+		return "", errors.Join(err, fmt.Errorf("ref %q of %s", ref, cfg.URL), errResolvingGitRef)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// fetch fetches the default branch, the branches and the tags of the repository into its bare clone, initializing
+// the clone if needed.
+func (r *gitResolver) fetch(ctx context.Context, repo *gitRepository, cfg *types.GitConfig) error {
+	if _, err := os.Stat(filepath.Join(repo.dir, "HEAD")); err != nil {
+		if _, err := runGit(ctx, "", nil, "init", "--quiet", "--bare", repo.dir); err != nil {
+			return errors.Join(err, errFetchingGitRepository)
+		}
+	}
+
+	env, cleanup, err := r.credentialsEnv(ctx, cfg)
+	if err != nil {
+		return errors.Join(err, errFetchingGitRepository)
+	}
+
+	defer cleanup()
+
+	if _, err := runGit(ctx, repo.dir, env,
+		"fetch", "--quiet", "--prune", "--force", "--update-head-ok", "--", cfg.URL,
+		"+HEAD:"+gitRemoteHEAD, "+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*",
+	); err != nil {
+		return errors.Join(err, fmt.Errorf("fetching %s", cfg.URL), errFetchingGitRepository)
+	}
+
+	repo.lastFetch = time.Now()
+
+	return nil
+}
+
+// credentialsEnv returns the environment passing the credentials of cfg to git, without writing them to the
+// configuration of the clone nor exposing them in the arguments of the process. SSH keys are written to a temporary
+// directory removed by the returned cleanup function.
+func (r *gitResolver) credentialsEnv(ctx context.Context, cfg *types.GitConfig) ([]string, func(), error) {
+	noop := func() {}
+
+	if ref := cfg.BasicAuthObjectRef; ref != nil {
+		username, password, err := resolveBasicAuth(ctx, r.objectRefResolver, ref)
+		if err != nil {
+			return nil, noop, err
+		}
+
+		header := "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+
+		return []string{"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=http.extraHeader", "GIT_CONFIG_VALUE_0=" + header}, noop, nil
+	}
+
+	ref := cfg.SSHKeyObjectRef
+	if ref == nil {
+		return nil, noop, nil
+	}
+
+	res, err := r.objectRefResolver.ResolvePaths(
+		ctx,
+		[]*jsonpath.JSONPath{ref.PrivateKeyJSONPath, ref.KnownHostsJSONPath},
+		ref.ObjectRef,
+	)
+	if err != nil {
+		return nil, noop, errors.Join(err, errResolvingSSHKeyRef)
+	}
+
+	if nRes := len(res); nRes < 2 {
+		return nil, noop, errors.Join(
+			fmt.Errorf("got: %d results; want: 2 results", nRes),
+			errors.New("ssh key expected 1 private key, and 1 known hosts"),
+			errResolvingSSHKeyRef,
+		)
+	}
+
+	dir, err := os.MkdirTemp("", "ipxer-git-ssh-*")
+	if err != nil {
+		return nil, noop, errors.Join(err, errResolvingSSHKeyRef)
+	}
+
+	cleanup := func() { _ = os.RemoveAll(dir) }
+
+	if err := os.Chmod(dir, gitCredentialsDirPermission); err != nil {
+		cleanup()
+		return nil, noop, errors.Join(err, errResolvingSSHKeyRef)
+	}
+
+	keyPath, knownHostsPath := filepath.Join(dir, "id"), filepath.Join(dir, "known_hosts")
+	for path, b := range map[string][]byte{keyPath: res[0], knownHostsPath: res[1]} {
+		if err := os.WriteFile(path, b, gitCredentialsFilePermission); err != nil {
+			cleanup()
+			return nil, noop, errors.Join(err, errResolvingSSHKeyRef)
+		}
+	}
+
+	// the host key of the server must be known, as the repository is trusted to provide boot configurations.
+	sshCommand := fmt.Sprintf(
+		"ssh -F /dev/null -o IdentitiesOnly=yes -o StrictHostKeyChecking=yes -o UserKnownHostsFile='%s' -i '%s'",
+		knownHostsPath,
+		keyPath,
+	)
+
+	return []string{"GIT_SSH_COMMAND=" + sshCommand}, cleanup, nil
+}
+
+// gitObject returns the name of the file at path in the commit. Paths are relative to the root of the repository.
+func gitObject(commit, path string) string {
+	return commit + ":" + strings.TrimPrefix(path, "/")
+}
+
+// runGit runs the git command in dir with the additional environment, and returns its output. Prompts are disabled,
+// so git fails instead of waiting for credentials.
+func runGit(ctx context.Context, dir string, env []string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+
+	stderr := new(bytes.Buffer)
+	cmd.Stderr = stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String())))
+	}
+
+	return out, nil
+}
+
+// --------------------------------------------------- CREDENTIALS -------------------------------------------------- //
+
+// newHTTPClient returns an http client using the pooled transport presenting the client certificate referenced by
+// ref, if any.
+func newHTTPClient(ctx context.Context, credentials CredentialCache, ref *types.MTLSObjectRef) (*http.Client, error) {
+	transport, err := credentials.Transport(ctx, ref)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// setBasicAuth sets the basic auth credentials referenced by ref to the request, if any.
+func setBasicAuth(
+	ctx context.Context,
+	credentials CredentialCache,
+	req *http.Request,
+	ref *types.BasicAuthObjectRef,
+) error {
+	if ref == nil {
+		return nil
+	}
+
+	username, password, err := credentials.BasicAuth(ctx, ref)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	req.SetBasicAuth(username, password)
+
+	return nil
+}
+
+// resolveBasicAuth returns the username and the password referenced by ref.
+func resolveBasicAuth(
+	ctx context.Context,
+	objectRefResolver ObjectRefResolver,
+	ref *types.BasicAuthObjectRef,
+) (string, string, error) {
+	paths := []*jsonpath.JSONPath{ref.UsernameJSONPath, ref.PasswordJSONPath}
+
+	res, err := objectRefResolver.ResolvePaths(ctx, paths, ref.ObjectRef)
+	if err != nil {
+		return "", "", errors.Join(err, errResolvingBasicAuthRef)
+	}
+
+	if nRes := len(res); nRes < 2 {
+		return "", "", errors.Join(
+			fmt.Errorf("got: %d results; want: 2 results", nRes),
+			errors.New("basic auth credentials expected 1 username, and 1 password"),
+			errResolvingBasicAuthRef)
+	}
+
+	return string(res[0]), string(res[1]), nil
 }
@@ -4,10 +4,18 @@ package adapter_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -17,6 +25,7 @@ import (
 	"github.com/alexandremahdhaoui/ipxer/internal/util/httputil"
 	"github.com/alexandremahdhaoui/ipxer/internal/util/testutil"
 	"github.com/alexandremahdhaoui/ipxer/pkg/generated/resolverserver"
+	"github.com/alexandremahdhaoui/ipxer/pkg/generated/webhookattributes"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -25,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic/fake"
 	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/util/jsonpath"
 	"k8s.io/utils/ptr"
 )
 
@@ -41,9 +51,9 @@ func TestInlineResolver(t *testing.T) {
 		expected := []byte("test")
 
 		content := types.Content{Inline: string(expected)}
-		ipxeSelectors := types.IPXESelectors{}
+		attributes := types.Attributes{}
 
-		out, err := resolver.Resolve(nil, content, ipxeSelectors)
+		out, err := resolver.Resolve(nil, content, attributes)
 		assert.NoError(t, err)
 		assert.Equal(t, expected, out)
 	})
@@ -53,10 +63,10 @@ func TestObjectRefResolver(t *testing.T) {
 	var (
 		ctx context.Context
 
-		expected      []byte
-		content       types.Content
-		ipxeSelectors types.IPXESelectors
-		object        *unstructured.Unstructured
+		expected   []byte
+		content    types.Content
+		attributes types.Attributes
+		object     *unstructured.Unstructured
 
 		cl       *fake.FakeDynamicClient
 		resolver adapter.Resolver
@@ -84,7 +94,7 @@ func TestObjectRefResolver(t *testing.T) {
 		cl = fake.NewSimpleDynamicClient(runtime.NewScheme(), object)
 		resolver = adapter.NewObjectRefResolver(cl)
 
-		ipxeSelectors = types.IPXESelectors{}
+		attributes = types.Attributes{}
 	}
 
 	t.Run("Resolve", func(t *testing.T) {
@@ -96,10 +106,101 @@ func TestObjectRefResolver(t *testing.T) {
 				return true, object, nil
 			})
 
-			actual, err := resolver.Resolve(ctx, content, ipxeSelectors)
+			actual, err := resolver.Resolve(ctx, content, attributes)
 			assert.NoError(t, err)
 			assert.Equal(t, expected, actual)
 		})
+
+		t.Run("Secret", func(t *testing.T) {
+			setup(t)
+
+			content.ObjectRef.Group = ""
+			content.ObjectRef.Resource = "secrets"
+
+			object.SetUnstructuredContent(map[string]any{
+				"metadata": map[string]any{"name": "secret"},
+				"data":     map[string]any{"test": base64.StdEncoding.EncodeToString(expected)},
+			})
+			cl.PrependReactor("get", "secrets", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+				return true, object, nil
+			})
+
+			// the data of secrets are decoded by default.
+			actual, err := resolver.Resolve(ctx, content, attributes)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, actual)
+
+			// other fields are read as is.
+			content.ObjectRef.JSONPath = jsonpath.New("")
+			require.NoError(t, content.ObjectRef.JSONPath.Parse("{.metadata.name}"))
+
+			actual, err = resolver.Resolve(ctx, content, attributes)
+			assert.NoError(t, err)
+			assert.Equal(t, "secret", string(actual))
+
+			content.ObjectRef.JSONPath = jsonpath.New("")
+			require.NoError(t, content.ObjectRef.JSONPath.Parse("{.data.test}"))
+			content.ObjectRef.Encoding = types.ObjectRefRawEncoding
+
+			actual, err = resolver.Resolve(ctx, content, attributes)
+			assert.NoError(t, err)
+			assert.Equal(t, base64.StdEncoding.EncodeToString(expected), string(actual))
+		})
+
+		t.Run("Base64Encoding", func(t *testing.T) {
+			setup(t)
+
+			content.ObjectRef.Encoding = types.ObjectRefBase64Encoding
+
+			object.SetUnstructuredContent(map[string]any{"data": map[string]any{
+				"test": base64.StdEncoding.EncodeToString(expected),
+			}})
+			cl.PrependReactor("get", "ConfigMap", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+				return true, object, nil
+			})
+
+			actual, err := resolver.Resolve(ctx, content, attributes)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+
+		t.Run("MultipleValues", func(t *testing.T) {
+			setup(t)
+
+			content.ObjectRef.JSONPath = jsonpath.New("")
+			require.NoError(t, content.ObjectRef.JSONPath.Parse("{.data.keys[*]}"))
+
+			object.SetUnstructuredContent(map[string]any{"data": map[string]any{"keys": []any{"a", "b"}}})
+			cl.PrependReactor("get", "ConfigMap", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+				return true, object, nil
+			})
+
+			_, err := resolver.Resolve(ctx, content, attributes)
+			assert.ErrorIs(t, err, adapter.ErrObjectRefResolver)
+			assert.ErrorContains(t, err, "got: 2 values; want: 1 value")
+
+			content.ObjectRef.JoinSeparator = ptr.To("\n")
+
+			actual, err := resolver.Resolve(ctx, content, attributes)
+			assert.NoError(t, err)
+			assert.Equal(t, "a\nb", string(actual))
+		})
+
+		t.Run("NoValue", func(t *testing.T) {
+			setup(t)
+
+			content.ObjectRef.JSONPath = jsonpath.New("")
+			require.NoError(t, content.ObjectRef.JSONPath.Parse("{.data.keys[*]}"))
+
+			object.SetUnstructuredContent(map[string]any{"data": map[string]any{"keys": []any{}}})
+			cl.PrependReactor("get", "ConfigMap", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+				return true, object, nil
+			})
+
+			_, err := resolver.Resolve(ctx, content, attributes)
+			assert.ErrorIs(t, err, adapter.ErrObjectRefResolver)
+			assert.ErrorContains(t, err, "jsonpath matched no value")
+		})
 	})
 }
 
@@ -112,7 +213,7 @@ func TestWebhookResolver(t *testing.T) {
 		basicAuthObject *unstructured.Unstructured
 		mtlsObject      *unstructured.Unstructured
 		content         types.Content
-		ipxeSelectors   types.IPXESelectors
+		attributes      types.Attributes
 
 		mock *resolverserverfake.Fake
 
@@ -120,6 +221,14 @@ func TestWebhookResolver(t *testing.T) {
 		resolver adapter.Resolver
 	)
 
+	basicAuthGVR := schema.GroupVersionResource{
+		Group:    "yoursecret.alexandre.mahdhaoui.com",
+		Version:  "v1beta2",
+		Resource: "YourSecret",
+	}
+
+	mtlsGVR := schema.GroupVersionResource{Group: "core", Version: "v1", Resource: "Secret"}
+
 	setup := func(t *testing.T) func() {
 		t.Helper()
 
@@ -134,9 +243,14 @@ func TestWebhookResolver(t *testing.T) {
 		require.NoError(t, content.WebhookConfig.MTLSObjectRef.ClientCertJSONPath.Parse(`{.data.client\.crt}`))
 		require.NoError(t, content.WebhookConfig.MTLSObjectRef.CaBundleJSONPath.Parse(`{.data.ca\.crt}`))
 
-		ipxeSelectors = types.IPXESelectors{
-			Buildarch: string(resolverserver.Arm64),
-			UUID:      uuid.New(),
+		attributes = types.Attributes{
+			IPXESelectors: types.IPXESelectors{
+				Buildarch: string(webhookattributes.Arm64),
+				UUID:      uuid.New(),
+			},
+			Machine:        types.Machine{MAC: "aa:bb:cc:dd:ee:ff", IP: "10.0.0.42"},
+			AssignmentName: "worker",
+			ProfileName:    "fcos",
 		}
 
 		// -------------------------------------------------- Webhook Server  --------------------------------------- //
@@ -194,14 +308,21 @@ func TestWebhookResolver(t *testing.T) {
 
 		// -------------------------------------------------- Client and Adapter ------------------------------------ //
 
-		cl = fake.NewSimpleDynamicClient(runtime.NewScheme(), basicAuthObject, mtlsObject)
+		cl = fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			basicAuthGVR: "YourSecretList",
+			mtlsGVR:      "SecretList",
+		})
+
+		require.NoError(t, cl.Tracker().Create(basicAuthGVR, basicAuthObject, basicAuthObject.GetNamespace()))
+		require.NoError(t, cl.Tracker().Create(mtlsGVR, mtlsObject, mtlsObject.GetNamespace()))
 
-		objectRefResolver := adapter.NewObjectRefResolver(cl)
-		resolver = adapter.NewWebhookResolver(objectRefResolver)
+		cacheCtx, cancel := context.WithCancel(ctx)
+		resolver = adapter.NewWebhookResolver(adapter.NewWebhookClient(adapter.NewCredentialCache(cacheCtx, cl, false)))
 
 		return func() {
 			t.Helper()
 
+			cancel()
 			mock.AssertExpectationsAndShutdown()
 		}
 	}
@@ -210,9 +331,20 @@ func TestWebhookResolver(t *testing.T) {
 		t.Run("Success", func(t *testing.T) {
 			defer setup(t)()
 
-			expected = fmt.Sprintf("{\"data\":\"%s + %s\"}\n", ipxeSelectors.Buildarch, ipxeSelectors.UUID.String())
+			expected = fmt.Sprintf("%s + %s", attributes.Buildarch, attributes.UUID.String())
 
 			mock.AppendExpectation(func(_ context.Context, request resolverserver.ResolveRequestObject) (resolverserver.ResolveResponseObject, error) { //nolint:lll
+				// the versioned attributes are sent along the legacy selectors.
+				assert.Equal(t, &resolverserver.Attributes{
+					Version:    webhookattributes.V1,
+					Uuid:       attributes.UUID.String(),
+					Buildarch:  webhookattributes.Arm64,
+					Mac:        ptr.To("aa:bb:cc:dd:ee:ff"),
+					Ip:         ptr.To("10.0.0.42"),
+					Assignment: ptr.To("worker"),
+					Profile:    ptr.To("fcos"),
+				}, request.Params.Attributes)
+
 				return resolverserver.Resolve200JSONResponse{
 					ResolveRespJSONResponse: resolverserver.ResolveRespJSONResponse{
 						Data: ptr.To(fmt.Sprintf(`%s + %s`, request.Params.Buildarch, request.Params.Uuid.String())),
@@ -220,41 +352,811 @@ func TestWebhookResolver(t *testing.T) {
 				}, nil
 			})
 
-			cl.PrependReactor("get", "YourSecret", func(_ k8stesting.Action) (bool, runtime.Object, error) {
-				return true, basicAuthObject, nil
+			actual, err := resolver.Resolve(ctx, content, attributes)
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(actual))
+		})
+
+		t.Run("Fail", func(t *testing.T) {
+			defer setup(t)()
+
+			basicAuthObject.Object["data"] = map[string]any{
+				"username": "not a username",
+				"password": "not a password",
+			}
+
+			require.NoError(t, cl.Tracker().Update(basicAuthGVR, basicAuthObject, basicAuthObject.GetNamespace()))
+
+			_, err := resolver.Resolve(ctx, content, attributes)
+			assert.ErrorIs(t, err, adapter.ErrWebhookResolver)
+			assert.ErrorIs(t, err, adapter.ErrWebhookUnauthorized)
+			assert.ErrorContains(t, err, "401 Unauthorized: Unauthorized")
+		})
+
+		t.Run("NotFound", func(t *testing.T) {
+			notFound := func(_ context.Context, _ resolverserver.ResolveRequestObject) (resolverserver.ResolveResponseObject, error) { //nolint:lll
+				return resolverserver.Resolve404JSONResponse{
+					N404JSONResponse: resolverserver.N404JSONResponse{Code: 404, Message: "no config for this machine"},
+				}, nil
+			}
+
+			t.Run("Error", func(t *testing.T) {
+				defer setup(t)()
+
+				mock.AppendExpectation(notFound)
+
+				_, err := resolver.Resolve(ctx, content, attributes)
+				assert.ErrorIs(t, err, adapter.ErrWebhookNotFound)
+
+				var webhookErr *adapter.WebhookError
+				require.ErrorAs(t, err, &webhookErr)
+				assert.Equal(t, http.StatusNotFound, webhookErr.StatusCode)
+				assert.Equal(t, "no config for this machine", webhookErr.Message)
+			})
+
+			t.Run("AsEmpty", func(t *testing.T) {
+				defer setup(t)()
+
+				content.NotFoundAsEmpty = true
+				mock.AppendExpectation(notFound)
+
+				actual, err := resolver.Resolve(ctx, content, attributes)
+				require.NoError(t, err)
+				assert.Empty(t, actual)
+			})
+		})
+	})
+}
+
+func TestHTTPResolver(t *testing.T) {
+	var (
+		ctx context.Context
+
+		content   types.Content
+		downloads int
+		server    *httptest.Server
+
+		cl       *fake.FakeDynamicClient
+		resolver adapter.Resolver
+	)
+
+	const (
+		body        = "variant: fcos"
+		etag        = `"v1"`
+		wrongDigest = "4bdb0b8eab3dbd8cafe6d2e0d6d8d8a5b8b1b7e0a0f1d0c6f4c7f0e9d6a2b3c4"
+	)
+
+	secretGVR := schema.GroupVersionResource{Version: "v1", Resource: "Secret"}
+
+	setup := func(t *testing.T, handler http.HandlerFunc) func() {
+		t.Helper()
+
+		ctx = context.Background()
+		downloads = 0
+		server = httptest.NewServer(handler)
+
+		content = types.Content{
+			Name:         "http",
+			ResolverKind: types.HTTPResolverKind,
+			HTTPConfig:   &types.HTTPConfig{URL: server.URL + "/config.bu"},
+		}
+
+		cl = fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			secretGVR: "SecretList",
+		})
+
+		cacheCtx, cancel := context.WithCancel(ctx)
+		// the cache holds a single response.
+		resolver = adapter.NewHTTPResolver(adapter.NewCredentialCache(cacheCtx, cl, false), int64(len(body)))
+
+		return func() {
+			t.Helper()
+
+			cancel()
+			server.Close()
+		}
+	}
+
+	// etagHandler serves the body, or 304 if the client already has it.
+	etagHandler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		downloads++
+
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(body))
+	}
+
+	t.Run("Resolve", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			defer setup(t, etagHandler)()
+
+			sum := sha256.Sum256([]byte(body))
+			content.HTTPConfig.Checksum = "sha256:" + hex.EncodeToString(sum[:])
+
+			actual, err := resolver.Resolve(ctx, content, types.Attributes{})
+			require.NoError(t, err)
+			assert.Equal(t, body, string(actual))
+		})
+
+		t.Run("ETag", func(t *testing.T) {
+			defer setup(t, etagHandler)()
+
+			for range 2 {
+				actual, err := resolver.Resolve(ctx, content, types.Attributes{})
+				require.NoError(t, err)
+				assert.Equal(t, body, string(actual))
+			}
+
+			// the second request is revalidated.
+			assert.Equal(t, 1, downloads)
+		})
+
+		t.Run("LastModified", func(t *testing.T) {
+			const lastModified = "Wed, 21 Oct 2015 07:28:00 GMT"
+
+			defer setup(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("If-Modified-Since") == lastModified {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+
+				downloads++
+
+				w.Header().Set("Last-Modified", lastModified)
+				_, _ = w.Write([]byte(body))
+			})()
+
+			for range 2 {
+				actual, err := resolver.Resolve(ctx, content, types.Attributes{})
+				require.NoError(t, err)
+				assert.Equal(t, body, string(actual))
+			}
+
+			assert.Equal(t, 1, downloads)
+		})
+
+		t.Run("Eviction", func(t *testing.T) {
+			defer setup(t, etagHandler)()
+
+			other := content
+			other.HTTPConfig = &types.HTTPConfig{URL: server.URL + "/other.bu"}
+
+			// the response of the other URL evicts the first one, which is then downloaded again.
+			for _, c := range []types.Content{content, other, content} {
+				_, err := resolver.Resolve(ctx, c, types.Attributes{})
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, 3, downloads)
+		})
+
+		t.Run("BasicAuth", func(t *testing.T) {
+			username, password := "qwe123", "321ewq"
+
+			defer setup(t, func(w http.ResponseWriter, r *http.Request) {
+				if u, p, ok := r.BasicAuth(); !ok || u != username || p != password {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				_, _ = w.Write([]byte(body))
+			})()
+
+			secret := &unstructured.Unstructured{}
+			secret.SetUnstructuredContent(map[string]any{"data": map[string]any{
+				"username": username,
+				"password": password,
+			}})
+			secret.SetName("creds")
+			secret.SetNamespace("ns")
+
+			require.NoError(t, cl.Tracker().Create(secretGVR, secret, "ns"))
+
+			ref := &types.BasicAuthObjectRef{
+				ObjectRef:        types.ObjectRef{Version: "v1", Resource: "Secret", Namespace: "ns", Name: "creds"},
+				UsernameJSONPath: jsonpath.New(""),
+				PasswordJSONPath: jsonpath.New(""),
+			}
+			require.NoError(t, ref.UsernameJSONPath.Parse(`{.data.username}`))
+			require.NoError(t, ref.PasswordJSONPath.Parse(`{.data.password}`))
+			content.HTTPConfig.BasicAuthObjectRef = ref
+
+			actual, err := resolver.Resolve(ctx, content, types.Attributes{})
+			require.NoError(t, err)
+			assert.Equal(t, body, string(actual))
+		})
+
+		t.Run("Failure", func(t *testing.T) {
+			t.Run("ChecksumMismatch", func(t *testing.T) {
+				defer setup(t, etagHandler)()
+
+				content.HTTPConfig.Checksum = "sha256:" + wrongDigest
+
+				_, err := resolver.Resolve(ctx, content, types.Attributes{})
+				assert.ErrorIs(t, err, adapter.ErrHTTPResolver)
+				assert.ErrorContains(t, err, "checksum mismatch")
+			})
+
+			t.Run("BodyTooLarge", func(t *testing.T) {
+				defer setup(t, etagHandler)()
+
+				content.HTTPConfig.MaxBodySize = int64(len(body)) - 1
+
+				_, err := resolver.Resolve(ctx, content, types.Attributes{})
+				assert.ErrorIs(t, err, adapter.ErrHTTPResolver)
+				assert.ErrorContains(t, err, "exceeds the maximum body size")
 			})
 
-			cl.PrependReactor("get", "Secret", func(_ k8stesting.Action) (bool, runtime.Object, error) {
-				return true, mtlsObject, nil
+			t.Run("UnexpectedStatusCode", func(t *testing.T) {
+				defer setup(t, func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusNotFound)
+				})()
+
+				_, err := resolver.Resolve(ctx, content, types.Attributes{})
+				assert.ErrorIs(t, err, adapter.ErrHTTPResolver)
+				assert.ErrorContains(t, err, "404 Not Found")
 			})
+		})
+	})
+}
+
+func TestS3Resolver(t *testing.T) {
+	var (
+		ctx context.Context
+
+		content    types.Content
+		attributes types.Attributes
+		objectPath string
+		requests   []*http.Request
+		server     *httptest.Server
+
+		cl       *fake.FakeDynamicClient
+		resolver adapter.Resolver
+	)
+
+	const (
+		bucket      = "ignition"
+		body        = `{"ignition":{"version":"3.4.0"}}`
+		accessKeyID = "AKIAEXAMPLE"
+	)
+
+	setup := func(t *testing.T) func() {
+		t.Helper()
+
+		ctx = context.Background()
+		selectors := types.IPXESelectors{UUID: uuid.New(), Buildarch: "arm64"}
+		attributes = types.Attributes{IPXESelectors: selectors, Machine: types.NewMachine(selectors, types.IpxeParams{})}
+		objectPath = fmt.Sprintf("/%s/%s/%s.ign", bucket, attributes.Buildarch, attributes.UUID)
+		requests = nil
+
+		// the fake object storage serves path-style requests, as MinIO does.
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r)
+
+			if r.URL.Path != objectPath {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`<Error><Code>NoSuchKey</Code></Error>`))
+
+				return
+			}
+
+			_, _ = w.Write([]byte(body))
+		}))
 
-			actual, err := resolver.Resolve(ctx, content, ipxeSelectors)
+		content = types.Content{
+			Name:         "s3",
+			ResolverKind: types.S3ResolverKind,
+			S3Config: &types.S3Config{
+				Bucket:         bucket,
+				Key:            "{{ .Machine.Buildarch }}/{{ .Machine.UUID }}.ign",
+				Endpoint:       server.URL,
+				ForcePathStyle: true,
+			},
+		}
+
+		cl = fake.NewSimpleDynamicClient(runtime.NewScheme())
+		resolver = adapter.NewS3Resolver(adapter.NewObjectRefResolver(cl))
+
+		return func() {
+			t.Helper()
+
+			server.Close()
+		}
+	}
+
+	t.Run("Resolve", func(t *testing.T) {
+		t.Run("Anonymous", func(t *testing.T) {
+			defer setup(t)()
+
+			actual, err := resolver.Resolve(ctx, content, attributes)
 			require.NoError(t, err)
-			assert.Equal(t, expected, string(actual))
+			assert.Equal(t, body, string(actual))
+
+			require.Len(t, requests, 1)
+			assert.Empty(t, requests[0].Header.Get("Authorization"))
 		})
 
-		t.Run("Fail", func(t *testing.T) {
+		t.Run("MachineFacts", func(t *testing.T) {
+			defer setup(t)()
+
+			// the MAC address is only known from the params forwarded by iPXE.
+			attributes.Machine.MAC = "aa:bb:cc:dd:ee:ff"
+			content.S3Config.Key = "{{ .Machine.MAC }}.ign"
+			objectPath = fmt.Sprintf("/%s/aa:bb:cc:dd:ee:ff.ign", bucket)
+
+			actual, err := resolver.Resolve(ctx, content, attributes)
+			require.NoError(t, err)
+			assert.Equal(t, body, string(actual))
+		})
+
+		t.Run("Credentials", func(t *testing.T) {
 			defer setup(t)()
 
-			cl.PrependReactor("get", "YourSecret", func(_ k8stesting.Action) (handled bool, ret runtime.Object, err error) {
-				basicAuthObject.SetUnstructuredContent(map[string]any{"data": map[string]any{
-					"username": "not a username",
-					"password": "not a password",
-				}})
+			secret := &unstructured.Unstructured{}
+			secret.SetUnstructuredContent(map[string]any{"data": map[string]any{
+				"accessKeyID":     base64.StdEncoding.EncodeToString([]byte(accessKeyID)),
+				"secretAccessKey": base64.StdEncoding.EncodeToString([]byte("secret")),
+			}})
 
-				return true, basicAuthObject, nil
+			cl.PrependReactor("get", "secrets", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+				return true, secret, nil
 			})
 
-			cl.PrependReactor("get", "Secret", func(_ k8stesting.Action) (handled bool, ret runtime.Object, err error) {
-				return true, mtlsObject, nil
+			ref := &types.S3CredentialsObjectRef{
+				ObjectRef:               types.ObjectRef{Version: "v1", Resource: "secrets", Namespace: "ns", Name: "s3"},
+				AccessKeyIDJSONPath:     jsonpath.New(""),
+				SecretAccessKeyJSONPath: jsonpath.New(""),
+			}
+			require.NoError(t, ref.AccessKeyIDJSONPath.Parse(`{.data.accessKeyID}`))
+			require.NoError(t, ref.SecretAccessKeyJSONPath.Parse(`{.data.secretAccessKey}`))
+			content.S3Config.CredentialsRef = ref
+
+			actual, err := resolver.Resolve(ctx, content, attributes)
+			require.NoError(t, err)
+			assert.Equal(t, body, string(actual))
+
+			// requests are signed with the referenced access key.
+			require.Len(t, requests, 1)
+			assert.Contains(t, requests[0].Header.Get("Authorization"), "Credential="+accessKeyID+"/")
+
+			// the cached client is replaced once the credentials are rotated.
+			secret.Object["data"].(map[string]any)["accessKeyID"] = base64.StdEncoding.EncodeToString([]byte("AKIAROTATED"))
+
+			_, err = resolver.Resolve(ctx, content, attributes)
+			require.NoError(t, err)
+			require.Len(t, requests, 2)
+			assert.Contains(t, requests[1].Header.Get("Authorization"), "Credential=AKIAROTATED/")
+		})
+
+		t.Run("Failure", func(t *testing.T) {
+			t.Run("NoSuchKey", func(t *testing.T) {
+				defer setup(t)()
+
+				content.S3Config.Key = "unknown"
+
+				_, err := resolver.Resolve(ctx, content, attributes)
+				assert.ErrorIs(t, err, adapter.ErrS3Resolver)
+				assert.ErrorContains(t, err, "s3://ignition/unknown")
+			})
+
+			t.Run("InvalidKey", func(t *testing.T) {
+				defer setup(t)()
+
+				content.S3Config.Key = "{{ .Machine.Unknown }}"
+
+				_, err := resolver.Resolve(ctx, content, attributes)
+				assert.ErrorIs(t, err, adapter.ErrS3Resolver)
+				assert.Empty(t, requests)
 			})
 
-			expected = `{"message":"Unauthorized"}`
-			expected = string(append([]byte(expected), byte(0x0a)))
+			t.Run("BodyTooLarge", func(t *testing.T) {
+				defer setup(t)()
+
+				content.S3Config.MaxBodySize = int64(len(body)) - 1
+
+				_, err := resolver.Resolve(ctx, content, attributes)
+				assert.ErrorIs(t, err, adapter.ErrS3Resolver)
+				assert.ErrorContains(t, err, "s3 object exceeds the maximum body size")
+			})
+		})
+	})
+}
+
+func TestOCIResolver(t *testing.T) {
+	var (
+		ctx context.Context
+
+		content        types.Content
+		requests       []string
+		manifest       string
+		manifestDigest string
+		blob           string
+		served         string
+		token          string
+		server         *httptest.Server
+
+		blobCacheDir string
+		resolver     adapter.Resolver
+	)
+
+	const (
+		repository = "ignition/worker"
+		mediaType  = "application/vnd.coreos.ignition+json"
+	)
+
+	digest := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+
+		return "sha256:" + hex.EncodeToString(sum[:])
+	}
+
+	setup := func(t *testing.T) func() {
+		t.Helper()
+
+		ctx = context.Background()
+		requests = nil
+		token = ""
+		blob = `{"ignition":{"version":"3.4.0"}}`
+		served = blob
+		manifest = fmt.Sprintf(`{"schemaVersion":2,"layers":[`+
+			`{"mediaType":"application/vnd.oci.empty.v1+json","digest":"%s","size":2},`+
+			`{"mediaType":"%s","digest":"%s","size":%d}]}`, digest("{}"), mediaType, digest(blob), len(blob))
+		manifestDigest = digest(manifest)
+
+		// the fake registry requires a bearer token if token is set.
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.URL.Path)
+
+			if r.URL.Path == "/token" {
+				assert.Equal(t, "repository:"+repository+":pull", r.URL.Query().Get("scope"))
+				_, _ = w.Write([]byte(fmt.Sprintf(`{"token":%q}`, token)))
+
+				return
+			}
+
+			if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="http://%s/token",service="fake"`, r.Host))
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			switch r.URL.Path {
+			case "/v2/" + repository + "/manifests/v1", "/v2/" + repository + "/manifests/" + manifestDigest:
+				assert.Contains(t, r.Header.Get("Accept"), "application/vnd.oci.image.manifest.v1+json")
+				_, _ = w.Write([]byte(manifest))
+			case "/v2/" + repository + "/blobs/" + digest(blob):
+				_, _ = w.Write([]byte(served))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+		reference, err := types.ParseOCIReference(strings.TrimPrefix(server.URL, "http://") + "/" + repository + ":v1")
+		require.NoError(t, err)
+
+		content = types.Content{
+			Name:         "oci",
+			ResolverKind: types.OCIResolverKind,
+			OCIConfig: &types.OCIConfig{
+				Reference: reference,
+				MediaType: mediaType,
+				PlainHTTP: true,
+			},
+		}
+
+		cl := fake.NewSimpleDynamicClient(runtime.NewScheme())
+		blobCacheDir = t.TempDir()
+		resolver = adapter.NewOCIResolver(adapter.NewObjectRefResolver(cl), blobCacheDir)
+
+		return func() {
+			t.Helper()
 
-			actual, err := resolver.Resolve(ctx, content, ipxeSelectors)
+			server.Close()
+		}
+	}
+
+	t.Run("Resolve", func(t *testing.T) {
+		t.Run("Tag", func(t *testing.T) {
+			defer setup(t)()
+
+			actual, err := resolver.Resolve(ctx, content, types.Attributes{})
 			require.NoError(t, err)
-			assert.Equal(t, expected, string(actual))
+			assert.Equal(t, blob, string(actual))
+		})
+
+		t.Run("Digest", func(t *testing.T) {
+			defer setup(t)()
+
+			content.OCIConfig.Reference.Digest = manifestDigest
+
+			actual, err := resolver.Resolve(ctx, content, types.Attributes{})
+			require.NoError(t, err)
+			assert.Equal(t, blob, string(actual))
+			assert.Equal(t, "/v2/"+repository+"/manifests/"+manifestDigest, requests[0])
+		})
+
+		t.Run("CachedBlob", func(t *testing.T) {
+			defer setup(t)()
+
+			_, err := resolver.Resolve(ctx, content, types.Attributes{})
+			require.NoError(t, err)
+
+			requests = nil
+
+			// only the manifest is fetched, as the tag may reference another artifact.
+			actual, err := resolver.Resolve(ctx, content, types.Attributes{})
+			require.NoError(t, err)
+			assert.Equal(t, blob, string(actual))
+			assert.Equal(t, []string{"/v2/" + repository + "/manifests/v1"}, requests)
+		})
+
+		t.Run("CachedDigest", func(t *testing.T) {
+			defer setup(t)()
+
+			token = "token"
+			content.OCIConfig.Reference.Digest = manifestDigest
+
+			_, err := resolver.Resolve(ctx, content, types.Attributes{})
+			require.NoError(t, err)
+
+			requests = nil
+
+			// neither the manifest nor a token is fetched, as the digest references an immutable artifact.
+			actual, err := resolver.Resolve(ctx, content, types.Attributes{})
+			require.NoError(t, err)
+			assert.Equal(t, blob, string(actual))
+			assert.Empty(t, requests)
+		})
+
+		t.Run("VerifiedOnce", func(t *testing.T) {
+			defer setup(t)()
+
+			content.OCIConfig.Reference.Digest = manifestDigest
+
+			_, err := resolver.Resolve(ctx, content, types.Attributes{})
+			require.NoError(t, err)
+
+			// blobs are trusted once verified, hence they are not hashed on every resolution.
+			path := filepath.Join(blobCacheDir, "sha256", strings.TrimPrefix(digest(blob), "sha256:"))
+			require.NoError(t, os.WriteFile(path, []byte("tampered"), 0o600))
+
+			actual, err := resolver.Resolve(ctx, content, types.Attributes{})
+			require.NoError(t, err)
+			assert.Equal(t, "tampered", string(actual))
+
+			// blobs cached by a previous run are verified on first read.
+			requests = nil
+			cl := fake.NewSimpleDynamicClient(runtime.NewScheme())
+			resolver = adapter.NewOCIResolver(adapter.NewObjectRefResolver(cl), blobCacheDir)
+
+			actual, err = resolver.Resolve(ctx, content, types.Attributes{})
+			require.NoError(t, err)
+			assert.Equal(t, blob, string(actual))
+			assert.Contains(t, requests, "/v2/"+repository+"/blobs/"+digest(blob))
+		})
+
+		t.Run("BearerToken", func(t *testing.T) {
+			defer setup(t)()
+
+			token = "token"
+
+			actual, err := resolver.Resolve(ctx, content, types.Attributes{})
+			require.NoError(t, err)
+			assert.Equal(t, blob, string(actual))
+			assert.Contains(t, requests, "/token")
+		})
+
+		t.Run("Failure", func(t *testing.T) {
+			t.Run("ManifestDigestMismatch", func(t *testing.T) {
+				defer setup(t)()
+
+				content.OCIConfig.Reference.Tag = ""
+				content.OCIConfig.Reference.Digest = digest("other")
+
+				// the fake registry serves the manifest regardless of the digest.
+				manifestDigest = content.OCIConfig.Reference.Digest
+
+				_, err := resolver.Resolve(ctx, content, types.Attributes{})
+				assert.ErrorIs(t, err, adapter.ErrOCIResolver)
+				assert.ErrorContains(t, err, "checksum mismatch")
+			})
+
+			t.Run("BlobDigestMismatch", func(t *testing.T) {
+				defer setup(t)()
+
+				blob = "tampered"
+				manifest = strings.ReplaceAll(manifest, digest(`{"ignition":{"version":"3.4.0"}}`), digest(blob))
+
+				_, err := resolver.Resolve(ctx, content, types.Attributes{})
+				assert.ErrorIs(t, err, adapter.ErrOCIResolver)
+				assert.ErrorContains(t, err, "checksum mismatch")
+			})
+
+			t.Run("BlobTooLarge", func(t *testing.T) {
+				defer setup(t)()
+
+				served = blob + strings.Repeat(" ", 1024)
+
+				_, err := resolver.Resolve(ctx, content, types.Attributes{})
+				assert.ErrorIs(t, err, adapter.ErrOCIResolver)
+				assert.ErrorContains(t, err, "oci blob exceeds the size of its descriptor")
+			})
+
+			t.Run("LayerNotFound", func(t *testing.T) {
+				defer setup(t)()
+
+				content.OCIConfig.MediaType = "application/vnd.unknown"
+
+				_, err := resolver.Resolve(ctx, content, types.Attributes{})
+				assert.ErrorIs(t, err, adapter.ErrOCIResolver)
+				assert.ErrorContains(t, err, "got: 0 layers")
+			})
+
+			t.Run("ManifestNotFound", func(t *testing.T) {
+				defer setup(t)()
+
+				content.OCIConfig.Reference.Tag = "unknown"
+
+				_, err := resolver.Resolve(ctx, content, types.Attributes{})
+				assert.ErrorIs(t, err, adapter.ErrOCIResolver)
+				assert.ErrorContains(t, err, "404")
+			})
+		})
+	})
+}
+
+func TestGitResolver(t *testing.T) {
+	var (
+		ctx context.Context
+
+		content  types.Content
+		src      string
+		resolver adapter.GitResolver
+	)
+
+	// git runs a git command in the source repository, returning its trimmed output.
+	git := func(t *testing.T, args ...string) string {
+		t.Helper()
+
+		args = append([]string{"-c", "user.name=ipxer", "-c", "user.email=ipxer@example.com"}, args...)
+		cmd := exec.Command("git", args...)
+		cmd.Dir = src
+
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+
+		return strings.TrimSpace(string(out))
+	}
+
+	commit := func(t *testing.T, path, body string) string {
+		t.Helper()
+
+		require.NoError(t, os.WriteFile(filepath.Join(src, path), []byte(body), 0o600))
+		git(t, "add", path)
+		git(t, "commit", "--quiet", "-m", "update "+path)
+
+		return git(t, "rev-parse", "HEAD")
+	}
+
+	setup := func(t *testing.T, fetchInterval time.Duration) func() {
+		t.Helper()
+
+		ctx = context.Background()
+		src = t.TempDir()
+		git(t, "init", "--quiet", "--initial-branch", "main")
+
+		content = types.Content{
+			Name:         "git",
+			ResolverKind: types.GitResolverKind,
+			GitConfig:    &types.GitConfig{URL: src, Path: "/fcos/worker.bu"},
+		}
+
+		cl := fake.NewSimpleDynamicClient(runtime.NewScheme())
+		resolver = adapter.NewGitResolver(adapter.NewObjectRefResolver(cl), t.TempDir(), fetchInterval)
+
+		return func() {
+			t.Helper()
+		}
+	}
+
+	t.Run("Resolve", func(t *testing.T) {
+		t.Run("DefaultBranch", func(t *testing.T) {
+			defer setup(t, 0)()
+
+			require.NoError(t, os.Mkdir(filepath.Join(src, "fcos"), 0o700))
+			commit(t, "fcos/worker.bu", "v1")
+
+			actual, err := resolver.Resolve(ctx, content, types.Attributes{})
+			require.NoError(t, err)
+			assert.Equal(t, "v1", string(actual))
+
+			// the branch moved and the clone is fetched again.
+			commit(t, "fcos/worker.bu", "v2")
+
+			actual, err = resolver.Resolve(ctx, content, types.Attributes{})
+			require.NoError(t, err)
+			assert.Equal(t, "v2", string(actual))
+		})
+
+		t.Run("Tag", func(t *testing.T) {
+			defer setup(t, time.Hour)()
+
+			require.NoError(t, os.Mkdir(filepath.Join(src, "fcos"), 0o700))
+			commit(t, "fcos/worker.bu", "v1")
+			git(t, "tag", "v1")
+			commit(t, "fcos/worker.bu", "v2")
+
+			content.GitConfig.Ref = "v1"
+
+			actual, err := resolver.Resolve(ctx, content, types.Attributes{})
+			require.NoError(t, err)
+			assert.Equal(t, "v1", string(actual))
+
+			// the clone is not fetched within the fetch interval, but unknown refs trigger a fetch.
+			commit(t, "fcos/worker.bu", "v3")
+			git(t, "tag", "v3")
+
+			content.GitConfig.Ref = "main"
+
+			actual, err = resolver.Resolve(ctx, content, types.Attributes{})
+			require.NoError(t, err)
+			assert.Equal(t, "v2", string(actual))
+
+			content.GitConfig.Ref = "v3"
+
+			actual, err = resolver.Resolve(ctx, content, types.Attributes{})
+			require.NoError(t, err)
+			assert.Equal(t, "v3", string(actual))
+		})
+
+		t.Run("Failure", func(t *testing.T) {
+			t.Run("UnknownRef", func(t *testing.T) {
+				defer setup(t, 0)()
+
+				commit(t, "README.md", "readme")
+
+				content.GitConfig.Ref = "unknown"
+
+				_, err := resolver.Resolve(ctx, content, types.Attributes{})
+				assert.ErrorIs(t, err, adapter.ErrGitResolver)
+				assert.ErrorContains(t, err, `ref "unknown"`)
+			})
+
+			t.Run("UnknownPath", func(t *testing.T) {
+				defer setup(t, 0)()
+
+				commit(t, "README.md", "readme")
+
+				_, err := resolver.Resolve(ctx, content, types.Attributes{})
+				assert.ErrorIs(t, err, adapter.ErrGitResolver)
+			})
+		})
+	})
+
+	t.Run("Revision", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			defer setup(t, 0)()
+
+			require.NoError(t, os.Mkdir(filepath.Join(src, "fcos"), 0o700))
+			expected := commit(t, "fcos/worker.bu", "v1")
+
+			actual, err := resolver.Revision(ctx, content)
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+
+		t.Run("UnknownPath", func(t *testing.T) {
+			defer setup(t, 0)()
+
+			commit(t, "README.md", "readme")
+
+			_, err := resolver.Revision(ctx, content)
+			assert.ErrorIs(t, err, adapter.ErrGitResolver)
 		})
 	})
 }
@@ -1,29 +1,31 @@
 package adapter
 
 import (
-	"bytes"
 	"context"
-	"crypto/tls"
-	"crypto/x509"
-	"encoding/json"
 	"errors"
-	"fmt"
-	"io"
 	"net/http"
 
 	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/alexandremahdhaoui/ipxer/pkg/generated/transformerclient"
 	butaneconfig "github.com/coreos/butane/config"
 	butanecommon "github.com/coreos/butane/config/common"
-
-	"k8s.io/client-go/util/jsonpath"
+	"k8s.io/utils/ptr"
 )
 
-var ErrTransformerTransform = errors.New("transforming content")
+var (
+	ErrTransformerTransform = errors.New("transforming content")
+	ErrWebhookTransformer   = errors.New("transforming content with webhook")
+)
 
 // --------------------------------------------------- INTERFACE ---------------------------------------------------- //
 
 type Transformer interface {
-	Transform(ctx context.Context, cfg types.TransformerConfig, content []byte, selectors types.IPXESelectors) ([]byte, error)
+	Transform(
+		ctx context.Context,
+		cfg types.TransformerConfig,
+		content []byte,
+		attributes types.Attributes,
+	) ([]byte, error)
 }
 
 // ----------------------------------------------- BUTANE TRANSFORMER ----------------------------------------------- //
@@ -38,7 +40,7 @@ func (t *butaneTransformer) Transform(
 	_ context.Context,
 	_ types.TransformerConfig,
 	content []byte,
-	_ types.IPXESelectors,
+	_ types.Attributes,
 ) ([]byte, error) {
 	b, _, err := butaneconfig.TranslateBytes(content, butanecommon.TranslateBytesOptions{Raw: true})
 	if err != nil {
@@ -50,133 +52,74 @@ func (t *butaneTransformer) Transform(
 
 // ---------------------------------------------- WEBHOOK TRANSFORMER ----------------------------------------------- //
 
-func NewWebhookTransformer(resolver ObjectRefResolver) Transformer {
-	return &webhookTransformer{objectRefResolver: resolver}
+func NewWebhookTransformer(client WebhookClient) Transformer {
+	return &webhookTransformer{client: client}
 }
 
 type webhookTransformer struct {
-	objectRefResolver ObjectRefResolver
-}
-
-type webhookTransformerRequest struct {
-	Content    []byte            `json:"content"`
-	Attributes map[string]string `json:"attributes"`
+	client WebhookClient
 }
 
 func (t *webhookTransformer) Transform(
 	ctx context.Context,
 	cfg types.TransformerConfig,
 	content []byte,
-	attributes types.IPXESelectors,
+	attributes types.Attributes,
 ) ([]byte, error) {
 	if cfg.Webhook == nil {
-		return nil, errors.New("TODO") // TODO: err & wrap err
+		return nil, errors.Join(errWebhookConfigShouldNotBeNil, ErrWebhookTransformer, ErrTransformerTransform)
 	}
 
-	requestBody := webhookTransformerRequest{
-		Content: content,
-		Attributes: map[string]string{ // TODO: use const for keys && a type-conversion func to build that map
-			"uuid":      attributes.UUID.String(),
-			"buildarch": attributes.Buildarch,
-		},
-	}
-
-	body, err := json.Marshal(requestBody)
+	out, err := t.transform(ctx, *cfg.Webhook, content, attributes)
 	if err != nil {
-		return nil, errors.Join(err) // TODO: wrap err
+		return nil, errors.Join(err, ErrWebhookTransformer, ErrTransformerTransform)
 	}
 
-	url := fmt.Sprintf("https://%s?uuid=%s&buildarch=%s",
-		cfg.Webhook.URL,
-		attributes.UUID.String(),
-		attributes.Buildarch)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
-	if err != nil {
-		return nil, errors.Join(err) // TODO: wrap err
-	}
-
-	httpClient := new(http.Client)
-	if err := t.mTLSConfig(ctx, httpClient, cfg.Webhook.MTLSObjectRef); err != nil {
-		return nil, errors.Join(err, ErrWebhookResolver, ErrResolverResolve)
-	}
-
-	if err := t.setBasicAuth(ctx, req, cfg.Webhook.BasicAuthObjectRef); err != nil {
-		return nil, errors.Join(err, ErrWebhookResolver, ErrResolverResolve)
-	}
+	return out, nil
+}
 
-	resp, err := httpClient.Do(req)
+func (t *webhookTransformer) transform(
+	ctx context.Context,
+	cfg types.WebhookConfig,
+	content []byte,
+	attributes types.Attributes,
+) ([]byte, error) {
+	doer, err := t.client.Doer(ctx, cfg)
 	if err != nil {
-		return nil, errors.Join(err, ErrWebhookResolver, ErrResolverResolve)
+		return nil, err //nolint:wrapcheck
 	}
 
-	defer resp.Body.Close()
-	out, err := io.ReadAll(resp.Body)
+	server, route, err := webhookEndpoint(cfg.URL)
 	if err != nil {
-		return nil, errors.Join(err, ErrWebhookResolver, ErrResolverResolve)
+		return nil, errors.Join(err, ErrWebhookClient)
 	}
 
-	return out, nil
-}
-
-func (t *webhookTransformer) mTLSConfig(ctx context.Context, httpClient *http.Client, ref *types.MTLSObjectRef) error {
-	if ref == nil {
-		return nil
-	}
-
-	paths := []*jsonpath.JSONPath{ref.ClientKeyJSONPath, ref.ClientCertJSONPath, ref.CaBundleJSONPath}
-
-	res, err := t.objectRefResolver.ResolvePaths(ctx, paths, ref.ObjectRef)
+	cl, err := transformerclient.NewClientWithResponses(server, transformerclient.WithHTTPClient(doer))
 	if err != nil {
-		return errors.Join(err, errResolvingMTLSConfig)
+		return nil, errors.Join(err, ErrWebhookClient)
 	}
 
-	if len(res) < 3 {
-		return errors.New("TODO") // TODO
+	body := transformerclient.TransformRequest{
+		Attributes: newWebhookAttributes(attributes),
+		Content:    ptr.To(string(content)),
 	}
 
-	clientKey := res[0]
-	clientCert := res[1]
-	caBundle := res[2]
-
-	caCertPool := x509.NewCertPool()
-	caCertPool.AppendCertsFromPEM(caBundle)
-
-	cert, err := tls.X509KeyPair(clientCert, clientKey)
+	resp, err := cl.TransformWithResponse(ctx, route, body)
 	if err != nil {
-		return errors.Join(err, errResolvingMTLSConfig)
+		return nil, errors.Join(err, ErrWebhookClient)
 	}
 
-	httpClient.Transport = &http.Transport{
-		TLSClientConfig: &tls.Config{
-			RootCAs:      caCertPool,
-			Certificates: []tls.Certificate{cert},
-		},
+	if resp.StatusCode() != http.StatusOK {
+		return nil, newWebhookError(resp.StatusCode(), resp.Body)
 	}
 
-	return nil
-}
-
-func (t *webhookTransformer) setBasicAuth(ctx context.Context, req *http.Request, ref *types.BasicAuthObjectRef) error {
-	if ref == nil {
-		return nil
-	}
-
-	paths := []*jsonpath.JSONPath{ref.UsernameJSONPath, ref.PasswordJSONPath}
-
-	res, err := t.objectRefResolver.ResolvePaths(ctx, paths, ref.ObjectRef)
-	if err != nil {
-		return err // TODO: wrap
+	if resp.JSON200 == nil {
+		return nil, errors.Join(errWebhookResponseNotJSON, ErrWebhookClient)
 	}
 
-	if len(res) < 2 {
-		return errors.New("TODO") // TODO
+	if resp.JSON200.Data == nil {
+		return []byte{}, nil
 	}
 
-	username := res[0]
-	password := res[1]
-
-	req.SetBasicAuth(string(username), string(password))
-
-	return nil
+	return []byte(*resp.JSON200.Data), nil
 }
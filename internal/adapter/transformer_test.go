@@ -4,6 +4,7 @@ package adapter_test
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"strings"
@@ -16,6 +17,7 @@ import (
 	"github.com/alexandremahdhaoui/ipxer/internal/util/mocks/mockadapter"
 	"github.com/alexandremahdhaoui/ipxer/internal/util/testutil"
 	"github.com/alexandremahdhaoui/ipxer/pkg/generated/transformerserver"
+	"github.com/alexandremahdhaoui/ipxer/pkg/generated/webhookattributes"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -45,15 +47,15 @@ passwd:
     - name: core
 `)
 
-		inputSelectors := types.IPXESelectors{
+		inputAttributes := types.Attributes{IPXESelectors: types.IPXESelectors{
 			UUID:      uuid.New(),
 			Buildarch: "arm64",
-		}
+		}}
 
 		expected := []byte(`{"ignition":{"version":"3.4.0"},"passwd":{"users":[{"name":"core"}]}}`)
 
 		ctx := context.Background()
-		actual, err := transformer.Transform(ctx, inputCfg, inputContent, inputSelectors)
+		actual, err := transformer.Transform(ctx, inputCfg, inputContent, inputAttributes)
 		assert.NoError(t, err)
 		assert.Equal(t, expected, actual)
 	})
@@ -66,11 +68,11 @@ func TestWebhookTransformer(t *testing.T) {
 
 		inputConfig     types.TransformerConfig
 		inputContent    []byte
-		inputAttributes types.IPXESelectors
+		inputAttributes types.Attributes
 
-		objectRefResolver *mockadapter.MockObjectRefResolver
-		transformer       adapter.Transformer
-		serverMock        *transformerserverfake.Fake
+		credentials *mockadapter.MockCredentialCache
+		transformer adapter.Transformer
+		serverMock  *transformerserverfake.Fake
 	)
 
 	setup := func(t *testing.T) func() {
@@ -85,15 +87,17 @@ func TestWebhookTransformer(t *testing.T) {
 
 		inputConfig = testutil.NewTypesTransformerConfigWebhook()
 		inputContent = []byte("this should be templated: {{ .uuid }}, {{ .buildarch }}")
-		inputAttributes = types.IPXESelectors{
-			UUID:      id,
-			Buildarch: buildarch,
+		inputAttributes = types.Attributes{
+			IPXESelectors:  types.IPXESelectors{UUID: id, Buildarch: buildarch},
+			Machine:        types.Machine{Hostname: "node-1", IP: "10.0.0.42"},
+			AssignmentName: "worker",
+			ProfileName:    "fcos",
 		}
 
 		// -------------------------------------------------- Client and Adapter ------------------------------------ //
 
-		objectRefResolver = mockadapter.NewMockObjectRefResolver(t)
-		transformer = adapter.NewWebhookTransformer(objectRefResolver)
+		credentials = mockadapter.NewMockCredentialCache(t)
+		transformer = adapter.NewWebhookTransformer(adapter.NewWebhookClient(credentials))
 
 		// -------------------------------------------------- Webhook Server Fake ----------------------------------- //
 
@@ -102,13 +106,18 @@ func TestWebhookTransformer(t *testing.T) {
 
 		clientKey, clientCert, err := serverMock.CA.NewCertifiedKeyPEM(addr)
 		require.NoError(t, err)
-		caCert := serverMock.CA.Cert()
 
 		// -------------------------------------------------- mTLS  ------------------------------------------------- //
 
-		objectRefResolver.EXPECT().
-			ResolvePaths(mock.Anything, mock.Anything, mock.Anything).
-			Return([][]byte{clientKey, clientCert, caCert}, nil).
+		cert, err := tls.X509KeyPair(clientCert, clientKey)
+		require.NoError(t, err)
+
+		credentials.EXPECT().
+			Transport(mock.Anything, inputConfig.Webhook.MTLSObjectRef).
+			Return(&http.Transport{TLSClientConfig: &tls.Config{ //nolint:gosec
+				RootCAs:      serverMock.CA.Pool(),
+				Certificates: []tls.Certificate{cert},
+			}}, nil).
 			Once()
 
 		// -------------------------------------------------- Basic Auth -------------------------------------------- //
@@ -120,9 +129,9 @@ func TestWebhookTransformer(t *testing.T) {
 			return u == username && p == password, nil
 		})
 
-		objectRefResolver.EXPECT().
-			ResolvePaths(mock.Anything, mock.Anything, mock.Anything).
-			Return([][]byte{[]byte(username), []byte(password)}, nil).
+		credentials.EXPECT().
+			BasicAuth(mock.Anything, inputConfig.Webhook.BasicAuthObjectRef).
+			Return(username, password, nil).
 			Once()
 
 		// -------------------------------------------------- Teardown  --------------------------------------------- //
@@ -130,7 +139,7 @@ func TestWebhookTransformer(t *testing.T) {
 		return func() { //nolint:contextcheck
 			t.Helper()
 
-			objectRefResolver.AssertExpectations(t)
+			credentials.AssertExpectations(t)
 			serverMock.AssertExpectationsAndShutdown()
 		}
 	}
@@ -139,14 +148,26 @@ func TestWebhookTransformer(t *testing.T) {
 		t.Run("Success", func(t *testing.T) {
 			defer setup(t)()
 
-			expected = fmt.Sprintf("{\"data\":\"%s + %s\"}\n", inputAttributes.Buildarch, inputAttributes.UUID.String())
+			expected = fmt.Sprintf("%s + %s", inputAttributes.Buildarch, inputAttributes.UUID.String())
 
 			serverMock.AppendExpectation(func(_ context.Context, request transformerserver.TransformRequestObject) (transformerserver.TransformResponseObject, error) { //nolint:lll
 				t.Helper()
 
+				// the content is sent as a string, as documented by the API.
+				assert.Equal(t, string(inputContent), *request.Body.Content)
+				assert.Equal(t, &transformerserver.Attributes{
+					Version:    webhookattributes.V1,
+					Uuid:       inputAttributes.UUID.String(),
+					Buildarch:  webhookattributes.Arm64,
+					Hostname:   ptr.To("node-1"),
+					Ip:         ptr.To("10.0.0.42"),
+					Assignment: ptr.To("worker"),
+					Profile:    ptr.To("fcos"),
+				}, request.Body.Attributes)
+
 				return transformerserver.Transform200JSONResponse{
 					TransformRespJSONResponse: transformerserver.TransformRespJSONResponse{
-						Data: ptr.To(fmt.Sprintf("%s + %s", request.Body.Attributes.Buildarch, request.Body.Attributes.Uuid.String())), //nolint:lll
+						Data: ptr.To(fmt.Sprintf("%s + %s", request.Body.Attributes.Buildarch, request.Body.Attributes.Uuid)), //nolint:lll
 					},
 				}, nil
 			})
@@ -159,8 +180,6 @@ func TestWebhookTransformer(t *testing.T) {
 		t.Run("Failure", func(t *testing.T) {
 			defer setup(t)()
 
-			expected = "{\"code\":400,\"message\":\"error\"}\n"
-
 			serverMock.AppendExpectation(func(_ context.Context, _ transformerserver.TransformRequestObject) (transformerserver.TransformResponseObject, error) { //nolint:lll
 				t.Helper()
 
@@ -172,9 +191,11 @@ func TestWebhookTransformer(t *testing.T) {
 				}, nil
 			})
 
-			actual, err := transformer.Transform(ctx, inputConfig, inputContent, inputAttributes)
-			require.NoError(t, err)
-			assert.Equal(t, expected, string(actual))
+			// client errors are not retried.
+			_, err := transformer.Transform(ctx, inputConfig, inputContent, inputAttributes)
+			assert.ErrorIs(t, err, adapter.ErrWebhookTransformer)
+			assert.ErrorIs(t, err, adapter.ErrWebhookBadRequest)
+			assert.ErrorContains(t, err, "400 Bad Request: error")
 		})
 	})
 }
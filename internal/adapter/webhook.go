@@ -0,0 +1,346 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/alexandremahdhaoui/ipxer/pkg/generated/webhookattributes"
+)
+
+const (
+	// webhookInitialBackoff is the delay before the first retry of a call, doubled before each subsequent retry.
+	webhookInitialBackoff = 250 * time.Millisecond
+	// webhookErrorBodySize is the maximum size of the response body reported by errors.
+	webhookErrorBodySize = 512
+)
+
+var (
+	ErrWebhookClient = errors.New("calling webhook")
+
+	// Errors responded by webhooks, as documented by the webhook APIs.
+
+	ErrWebhookBadRequest          = errors.New("webhook responded bad request")
+	ErrWebhookUnauthorized        = errors.New("webhook responded unauthorized")
+	ErrWebhookForbidden           = errors.New("webhook responded forbidden")
+	ErrWebhookNotFound            = errors.New("webhook responded not found")
+	ErrWebhookInternalServerError = errors.New("webhook responded internal server error")
+	ErrWebhookServiceUnavailable  = errors.New("webhook responded service unavailable")
+
+	errWebhookResponseTooLarge = errors.New("webhook response exceeds the maximum body size")
+	errWebhookResponseNotJSON  = errors.New("webhook response is not json")
+	errWebhookRequestNotRetry  = errors.New("webhook request cannot be retried")
+	errBasicAuthAndBearerToken = errors.New("basic auth and bearer token are mutually exclusive")
+)
+
+// ---------------------------------------------------- INTERFACES -------------------------------------------------- //
+
+// WebhookClient calls the webhooks of resolvers and transformers.
+type WebhookClient interface {
+	// Doer returns the HTTPRequestDoer calling the webhook configured by cfg, which is meant to be used by the
+	// generated clients of the webhook APIs. Calls failing with a connection error or a 5xx status code are retried
+	// with an exponential backoff.
+	Doer(ctx context.Context, cfg types.WebhookConfig) (HTTPRequestDoer, error)
+}
+
+// HTTPRequestDoer performs HTTP requests, as expected by the generated clients.
+type HTTPRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// --------------------------------------------------- CONSTRUCTORS ------------------------------------------------- //
+
+// NewWebhookClient returns a WebhookClient authenticating to the webhooks with the credentials of the cache.
+func NewWebhookClient(credentials CredentialCache) WebhookClient {
+	return &webhookClient{credentials: credentials}
+}
+
+// ---------------------------------------------------- CLIENT ------------------------------------------------------ //
+
+type webhookClient struct {
+	credentials CredentialCache
+}
+
+func (c *webhookClient) Doer(ctx context.Context, cfg types.WebhookConfig) (HTTPRequestDoer, error) {
+	u, err := types.ParseWebhookURL(cfg.URL)
+	if err != nil {
+		return nil, errors.Join(err, ErrWebhookClient)
+	}
+
+	// the static query parameters take precedence over the query of the URL, but neither overrides the parameters of
+	// the request, e.g. the selectors of the machine.
+	query := u.Query()
+	for key, value := range cfg.QueryParams {
+		query.Set(key, value)
+	}
+
+	transport, err := c.credentials.Transport(ctx, cfg.MTLSObjectRef)
+	if err != nil {
+		return nil, errors.Join(err, ErrWebhookClient)
+	}
+
+	setAuthorization, err := c.authorization(ctx, cfg)
+	if err != nil {
+		return nil, errors.Join(err, ErrWebhookClient)
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = types.DefaultWebhookTimeout
+	}
+
+	maxBodySize := cfg.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = types.DefaultWebhookMaxBodySize
+	}
+
+	return &webhookDoer{
+		httpClient:       &http.Client{Transport: transport, Timeout: timeout},
+		setAuthorization: setAuthorization,
+		query:            query,
+		headers:          cfg.Headers,
+		retries:          cfg.Retries,
+		maxBodySize:      maxBodySize,
+	}, nil
+}
+
+// authorization returns a func setting the basic auth or the bearer token credentials of the webhook to a request.
+func (c *webhookClient) authorization(ctx context.Context, cfg types.WebhookConfig) (func(*http.Request), error) {
+	switch {
+	case cfg.BasicAuthObjectRef != nil && cfg.BearerTokenObjectRef != nil:
+		return nil, errBasicAuthAndBearerToken
+	case cfg.BasicAuthObjectRef != nil:
+		username, password, err := c.credentials.BasicAuth(ctx, cfg.BasicAuthObjectRef)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		return func(req *http.Request) { req.SetBasicAuth(username, password) }, nil
+	case cfg.BearerTokenObjectRef != nil:
+		token, err := c.credentials.BearerToken(ctx, cfg.BearerTokenObjectRef)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		return func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token) }, nil
+	default:
+		return func(*http.Request) {}, nil
+	}
+}
+
+// ---------------------------------------------------- DOER -------------------------------------------------------- //
+
+type webhookDoer struct {
+	httpClient       *http.Client
+	setAuthorization func(*http.Request)
+	query            url.Values
+	headers          map[string]string
+	retries          int
+	maxBodySize      int64
+}
+
+func (d *webhookDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	backoff := webhookInitialBackoff
+
+	d.setQueryAndHeaders(req)
+	d.setAuthorization(req)
+
+	for attempt := 1; ; attempt++ {
+		resp, err := d.do(req)
+
+		// connection errors, timeouts and 5xx status codes are retried, unless the caller gave up.
+		retryable := (err != nil && ctx.Err() == nil && !errors.Is(err, errWebhookResponseTooLarge)) ||
+			(err == nil && resp.StatusCode >= http.StatusInternalServerError)
+
+		if !retryable || attempt > d.retries {
+			if err != nil {
+				return nil, errors.Join(err, fmt.Errorf("after %d attempt(s)", attempt), ErrWebhookClient)
+			}
+
+			return resp, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Join(ctx.Err(), ErrWebhookClient)
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+
+		if req, err = rewind(req); err != nil {
+			return nil, errors.Join(err, ErrWebhookClient)
+		}
+	}
+}
+
+// setQueryAndHeaders adds the static query parameters and headers of the webhook to the request, unless the request
+// already sets them.
+func (d *webhookDoer) setQueryAndHeaders(req *http.Request) {
+	query := req.URL.Query()
+	for key, values := range d.query {
+		if !query.Has(key) {
+			query[key] = values
+		}
+	}
+
+	req.URL.RawQuery = query.Encode()
+
+	for key, value := range d.headers {
+		if req.Header.Get(key) == "" {
+			req.Header.Set(key, value)
+		}
+	}
+}
+
+// do calls the webhook once. The body of the response is read, so the connection is reused.
+func (d *webhookDoer) do(req *http.Request) (*http.Response, error) {
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, d.maxBodySize+1))
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if int64(len(body)) > d.maxBodySize {
+		return nil, errors.Join(fmt.Errorf("got: more than %d bytes", d.maxBodySize), errWebhookResponseTooLarge)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return resp, nil
+}
+
+// rewind returns a copy of the request whose body can be sent again.
+func rewind(req *http.Request) (*http.Request, error) {
+	out := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return out, nil
+	}
+
+	if req.GetBody == nil {
+		return nil, errWebhookRequestNotRetry
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, errors.Join(err, errWebhookRequestNotRetry)
+	}
+
+	out.Body = body
+
+	return out, nil
+}
+
+// ---------------------------------------------------- ERRORS ------------------------------------------------------ //
+
+// WebhookError is a non-2xx response of a webhook. It wraps the error of its status code, e.g. ErrWebhookNotFound.
+type WebhookError struct {
+	StatusCode int
+	// Message is the message of the documented error schema, or the beginning of the response body otherwise.
+	Message string
+}
+
+func (e *WebhookError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+func (e *WebhookError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		return ErrWebhookBadRequest
+	case http.StatusUnauthorized:
+		return ErrWebhookUnauthorized
+	case http.StatusForbidden:
+		return ErrWebhookForbidden
+	case http.StatusNotFound:
+		return ErrWebhookNotFound
+	case http.StatusInternalServerError:
+		return ErrWebhookInternalServerError
+	case http.StatusServiceUnavailable:
+		return ErrWebhookServiceUnavailable
+	default:
+		return errUnexpectedStatusCode
+	}
+}
+
+// newWebhookError returns the WebhookError of a response, whose body is expected to follow the documented error
+// schema, i.e. `{"code": 404, "message": "..."}`.
+func newWebhookError(statusCode int, body []byte) *WebhookError {
+	var schema struct {
+		Message string `json:"message"`
+	}
+
+	if err := json.Unmarshal(body, &schema); err == nil && schema.Message != "" {
+		return &WebhookError{StatusCode: statusCode, Message: schema.Message}
+	}
+
+	if len(body) > webhookErrorBodySize {
+		body = body[:webhookErrorBodySize]
+	}
+
+	return &WebhookError{StatusCode: statusCode, Message: strings.TrimSpace(string(body))}
+}
+
+// newWebhookAttributes returns the v1 Attributes shared by the webhook APIs. The facts of the machine take precedence
+// over its selectors, as they complement them with the forwarded params. Unknown attributes are nil, hence omitted.
+func newWebhookAttributes(attributes types.Attributes) *webhookattributes.Attributes {
+	optional := func(values ...string) *string {
+		for _, value := range values {
+			if value != "" {
+				return &value
+			}
+		}
+
+		return nil
+	}
+
+	mac := ""
+	if len(attributes.MAC) > 0 {
+		mac = attributes.MAC.String()
+	}
+
+	return &webhookattributes.Attributes{
+		Version:    webhookattributes.V1,
+		Uuid:       attributes.UUID.String(),
+		Buildarch:  webhookattributes.Buildarch(attributes.Buildarch),
+		Platform:   optional(attributes.Machine.Platform, attributes.Platform),
+		Mac:        optional(attributes.Machine.MAC, mac),
+		Serial:     optional(attributes.Machine.Serial, attributes.Serial),
+		Asset:      optional(attributes.Machine.Asset, attributes.Asset),
+		Hostname:   optional(attributes.Machine.Hostname, attributes.Hostname),
+		Ip:         optional(attributes.Machine.IP),
+		Assignment: optional(attributes.AssignmentName),
+		Profile:    optional(attributes.ProfileName),
+	}
+}
+
+// webhookEndpoint returns the server and the route of the webhook URL, as the generated clients call
+// `<server>/<route>`. The query of the URL is sent by the HTTPRequestDoer.
+func webhookEndpoint(webhookURL string) (string, string, error) {
+	u, err := types.ParseWebhookURL(webhookURL)
+	if err != nil {
+		return "", "", err //nolint:wrapcheck
+	}
+
+	i := strings.LastIndex(u.Path, "/")
+	route := u.Path[i+1:]
+
+	u.Path, u.RawPath, u.RawQuery, u.Fragment = u.Path[:max(i, 0)], "", "", ""
+
+	return u.String(), route, nil
+}
@@ -0,0 +1,272 @@
+//go:build unit
+
+package adapter_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
+	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/mocks/mockadapter"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookClient(t *testing.T) {
+	var (
+		ctx context.Context
+
+		cfg    types.WebhookConfig
+		calls  *atomic.Int32
+		server *httptest.Server
+
+		credentials *mockadapter.MockCredentialCache
+		client      adapter.WebhookClient
+	)
+
+	setup := func(t *testing.T, handler http.HandlerFunc) func() {
+		t.Helper()
+
+		ctx = context.Background()
+		calls = new(atomic.Int32)
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			handler(w, r)
+		}))
+
+		cfg = types.WebhookConfig{
+			URL:     server.URL + "/webhook",
+			Timeout: time.Second,
+			Retries: 2,
+		}
+
+		credentials = mockadapter.NewMockCredentialCache(t)
+		credentials.EXPECT().
+			Transport(mock.Anything, cfg.MTLSObjectRef).
+			Return(server.Client().Transport, nil).
+			Once()
+
+		client = adapter.NewWebhookClient(credentials)
+
+		return func() {
+			t.Helper()
+
+			server.Close()
+			credentials.AssertExpectations(t)
+		}
+	}
+
+	// do sends a request to the webhook with the doer of the client, as the generated clients do.
+	do := func(t *testing.T, method, body string) (*http.Response, error) {
+		t.Helper()
+
+		doer, err := client.Doer(ctx, cfg)
+		require.NoError(t, err)
+
+		var reader io.Reader
+		if body != "" {
+			reader = strings.NewReader(body)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, server.URL+"/webhook?uuid=abc", reader)
+		require.NoError(t, err)
+
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		return doer.Do(req) //nolint:bodyclose
+	}
+
+	readBody := func(t *testing.T, resp *http.Response) string {
+		t.Helper()
+
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return string(body)
+	}
+
+	t.Run("Doer", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			defer setup(t, func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+
+				if r.Method != http.MethodPost || r.URL.Query().Get("uuid") != "abc" || string(body) != `{}` {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				_, _ = w.Write([]byte("ok"))
+			})()
+
+			resp, err := do(t, http.MethodPost, `{}`)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "ok", readBody(t, resp))
+		})
+
+		t.Run("QueryAndHeaders", func(t *testing.T) {
+			defer setup(t, func(w http.ResponseWriter, r *http.Request) {
+				// static parameters do not override the parameters of the request.
+				if r.URL.Query().Encode() != "env=prod&region=eu&uuid=abc" || r.Header.Get("X-Tenant") != "tenant-a" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				_, _ = w.Write([]byte("ok"))
+			})()
+
+			cfg.URL += "?env=prod"
+			cfg.QueryParams = map[string]string{"region": "eu", "uuid": "ignored"}
+			cfg.Headers = map[string]string{"X-Tenant": "tenant-a"}
+
+			resp, err := do(t, http.MethodGet, "")
+			require.NoError(t, err)
+			assert.Equal(t, "ok", readBody(t, resp))
+		})
+
+		t.Run("BearerToken", func(t *testing.T) {
+			defer setup(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer t0k3n" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				_, _ = w.Write([]byte("ok"))
+			})()
+
+			cfg.BearerTokenObjectRef = &types.BearerTokenObjectRef{}
+			credentials.EXPECT().
+				BearerToken(mock.Anything, cfg.BearerTokenObjectRef).
+				Return("t0k3n", nil).
+				Once()
+
+			resp, err := do(t, http.MethodGet, "")
+			require.NoError(t, err)
+			assert.Equal(t, "ok", readBody(t, resp))
+		})
+
+		t.Run("RetryServerErrors", func(t *testing.T) {
+			defer setup(t, func(w http.ResponseWriter, r *http.Request) {
+				// the body is sent again on each attempt.
+				if body, _ := io.ReadAll(r.Body); string(body) != `{}` {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				if calls.Load() <= 2 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+
+				_, _ = w.Write([]byte("ok"))
+			})()
+
+			resp, err := do(t, http.MethodPost, `{}`)
+			require.NoError(t, err)
+			assert.Equal(t, "ok", readBody(t, resp))
+			assert.Equal(t, int32(3), calls.Load())
+		})
+
+		t.Run("RetriesExhausted", func(t *testing.T) {
+			defer setup(t, func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"code":500,"message":"oops"}`))
+			})()
+
+			// the last response is returned, so its error can be reported.
+			resp, err := do(t, http.MethodGet, "")
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			assert.Equal(t, `{"code":500,"message":"oops"}`, readBody(t, resp))
+			assert.Equal(t, int32(3), calls.Load())
+		})
+
+		t.Run("ClientErrorNotRetried", func(t *testing.T) {
+			defer setup(t, func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			})()
+
+			resp, err := do(t, http.MethodGet, "")
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			assert.Equal(t, int32(1), calls.Load())
+		})
+
+		t.Run("Failure", func(t *testing.T) {
+			t.Run("InvalidURL", func(t *testing.T) {
+				ctx = context.Background()
+				client = adapter.NewWebhookClient(mockadapter.NewMockCredentialCache(t))
+
+				_, err := client.Doer(ctx, types.WebhookConfig{URL: "ftp://example.com/webhook"})
+				assert.ErrorIs(t, err, types.ErrParsingWebhookURL)
+			})
+
+			t.Run("BodyTooLarge", func(t *testing.T) {
+				defer setup(t, func(w http.ResponseWriter, _ *http.Request) {
+					_, _ = w.Write([]byte("0123456789"))
+				})()
+
+				cfg.MaxBodySize = 4
+
+				_, err := do(t, http.MethodGet, "")
+				assert.ErrorIs(t, err, adapter.ErrWebhookClient)
+				assert.ErrorContains(t, err, "webhook response exceeds the maximum body size")
+				assert.Equal(t, int32(1), calls.Load())
+			})
+
+			t.Run("Timeout", func(t *testing.T) {
+				defer setup(t, func(_ http.ResponseWriter, r *http.Request) {
+					<-r.Context().Done()
+				})()
+
+				cfg.Timeout = 10 * time.Millisecond
+				cfg.Retries = 0
+
+				_, err := do(t, http.MethodGet, "")
+				assert.ErrorIs(t, err, adapter.ErrWebhookClient)
+				assert.ErrorContains(t, err, "Client.Timeout exceeded")
+			})
+		})
+	})
+
+	t.Run("Resolver", func(t *testing.T) {
+		t.Run("PlainHTTPWithPathAndQuery", func(t *testing.T) {
+			defer setup(t, func(http.ResponseWriter, *http.Request) {})()
+
+			// e.g. an in-cluster sidecar.
+			plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query := r.URL.Query()
+				if r.URL.Path != "/v1/configs" || query.Get("env") != "prod" || query.Get("buildarch") != "arm64" ||
+					query.Get("uuid") == "" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"data":"ok"}`))
+			}))
+			defer plain.Close()
+
+			cfg.URL = plain.URL + "/v1/configs?env=prod"
+
+			actual, err := adapter.NewWebhookResolver(client).Resolve(ctx, types.Content{WebhookConfig: &cfg},
+				types.Attributes{IPXESelectors: types.IPXESelectors{Buildarch: "arm64", UUID: uuid.New()}})
+			require.NoError(t, err)
+			assert.Equal(t, "ok", string(actual))
+		})
+	})
+}
@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
+	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
+)
+
+const (
+	// EmbeddedScriptPlaceholder must be embedded into bootloaders (i.e. iPXE must be built with
+	// `EMBED=embedded-script-placeholder.ipxe`) for ipxer to patch it at serve time. The placeholder must be followed by
+	// a sequence of EmbeddedScriptPadding bytes, which defines the maximum length of the patched script.
+	EmbeddedScriptPlaceholder = "#!ipxe\n#ipxer-embedded-script-placeholder"
+	EmbeddedScriptPadding     = '#'
+)
+
+var (
+	ErrBootloaderNotFound = errors.New("bootloader cannot be found")
+	ErrBootloaderGet      = errors.New("getting bootloader")
+
+	errBootloaderInvalidPath = errors.New("bootloader path must be formatted as \"<buildarch>/<filename>\"")
+	errBootloaderBuildarch   = errors.New("unsupported bootloader buildarch")
+
+	errEmbeddingScript           = errors.New("embedding script into bootloader")
+	errPlaceholderNotFound       = errors.New("embedded script placeholder not found in bootloader")
+	errEmbeddedScriptTooLong     = errors.New("embedded script is longer than the placeholder")
+	errPlaceholderFoundManyTimes = errors.New("embedded script placeholder found more than once in bootloader")
+)
+
+// ---------------------------------------------------- INTERFACE --------------------------------------------------- //
+
+type Bootloader interface {
+	// Get returns the bootloader located at `<buildarch>/<filename>`, e.g. `x86_64/ipxe.efi`.
+	Get(ctx context.Context, path string) ([]byte, error)
+}
+
+// --------------------------------------------------- CONSTRUCTORS ------------------------------------------------- //
+
+// NewBootloader returns a Bootloader controller. If ipxerBaseURL is not empty, the served bootloaders are patched to
+// embed a script chainloading to `<ipxerBaseURL>/ipxe`, forwarding the specified params or types.DefaultIPXEParams.
+func NewBootloader(adapterBootloader adapter.Bootloader, ipxerBaseURL string, params []types.IPXEParam) Bootloader {
+	var embeddedScript []byte
+	if ipxerBaseURL != "" {
+		embeddedScript = newEmbeddedScript(strings.TrimSuffix(ipxerBaseURL, "/"), params)
+	}
+
+	return &bootloader{
+		bootloader:     adapterBootloader,
+		embeddedScript: embeddedScript,
+	}
+}
+
+// ---------------------------------------------------- BOOTLOADER -------------------------------------------------- //
+
+type bootloader struct {
+	bootloader adapter.Bootloader
+
+	embeddedScript []byte
+}
+
+func (b *bootloader) Get(ctx context.Context, path string) ([]byte, error) {
+	buildarch, filename, err := parseBootloaderPath(path)
+	if err != nil {
+		return nil, errors.Join(err, ErrBootloaderGet)
+	}
+
+	out, err := b.bootloader.Get(ctx, buildarch.String(), filename)
+	if errors.Is(err, adapter.ErrBootloaderNotFound) {
+		return nil, errors.Join(err, ErrBootloaderNotFound, ErrBootloaderGet)
+	} else if err != nil {
+		return nil, errors.Join(err, ErrBootloaderGet)
+	}
+
+	if b.embeddedScript == nil {
+		return out, nil
+	}
+
+	out, err = embedScript(out, b.embeddedScript)
+	if err != nil {
+		return nil, errors.Join(err, fmt.Errorf("path %q", path), ErrBootloaderGet)
+	}
+
+	return out, nil
+}
+
+// embedScript overwrites the placeholder and its padding with the script. The remaining bytes are filled with padding:
+// as the script ends with a new line, they form a trailing comment.
+func embedScript(binary, script []byte) ([]byte, error) {
+	placeholder := []byte(EmbeddedScriptPlaceholder)
+
+	start := bytes.Index(binary, placeholder)
+	if start < 0 {
+		return nil, errors.Join(errPlaceholderNotFound, errEmbeddingScript)
+	}
+
+	if bytes.Contains(binary[start+len(placeholder):], placeholder) {
+		return nil, errors.Join(errPlaceholderFoundManyTimes, errEmbeddingScript)
+	}
+
+	end := start + len(placeholder)
+	for end < len(binary) && binary[end] == EmbeddedScriptPadding {
+		end++
+	}
+
+	if len(script) > end-start {
+		return nil, errors.Join(
+			fmt.Errorf("script length: %d; placeholder length: %d", len(script), end-start),
+			errEmbeddedScriptTooLong,
+			errEmbeddingScript,
+		)
+	}
+
+	out := bytes.Clone(binary)
+	copy(out[start:], script)
+
+	for i := start + len(script); i < end; i++ {
+		out[i] = EmbeddedScriptPadding
+	}
+
+	return out, nil
+}
+
+// parseBootloaderPath splits a path such as `/arm64/ipxe.efi` into its buildarch and filename.
+func parseBootloaderPath(path string) (v1alpha1.Buildarch, string, error) {
+	split := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)
+	if len(split) != 2 || split[1] == "" {
+		return "", "", errors.Join(fmt.Errorf("got %q", path), errBootloaderInvalidPath)
+	}
+
+	buildarch := v1alpha1.Buildarch(split[0])
+	if _, ok := v1alpha1.AllowedBuildarch[buildarch]; !ok {
+		return "", "", errors.Join(fmt.Errorf("got %q", split[0]), errBootloaderBuildarch)
+	}
+
+	return buildarch, split[1], nil
+}
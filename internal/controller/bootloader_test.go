@@ -0,0 +1,177 @@
+//go:build unit
+
+package controller_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
+	"github.com/alexandremahdhaoui/ipxer/internal/controller"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/mocks/mockadapter"
+)
+
+func TestBootloader(t *testing.T) {
+	var (
+		ctx context.Context
+
+		adapterBootloader *mockadapter.MockBootloader
+		bootloader        controller.Bootloader
+	)
+
+	setup := func(t *testing.T) func() {
+		t.Helper()
+
+		ctx = context.Background()
+
+		adapterBootloader = mockadapter.NewMockBootloader(t)
+		bootloader = controller.NewBootloader(adapterBootloader, "", nil)
+
+		return func() {
+			t.Helper()
+
+			adapterBootloader.AssertExpectations(t)
+		}
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			for _, path := range []string{"arm64/ipxe.efi", "/arm64/ipxe.efi"} {
+				t.Run(path, func(t *testing.T) {
+					defer setup(t)()
+
+					expected := []byte("binary")
+
+					adapterBootloader.EXPECT().
+						Get(ctx, "arm64", "ipxe.efi").
+						Return(expected, nil).
+						Once()
+
+					actual, err := bootloader.Get(ctx, path)
+					assert.NoError(t, err)
+					assert.Equal(t, expected, actual)
+				})
+			}
+		})
+
+		t.Run("Failure", func(t *testing.T) {
+			t.Run("InvalidPath", func(t *testing.T) {
+				for _, path := range []string{"ipxe.efi", "arm64/", "aarch64/ipxe.efi"} {
+					t.Run(path, func(t *testing.T) {
+						defer setup(t)()
+
+						_, err := bootloader.Get(ctx, path)
+						assert.ErrorIs(t, err, controller.ErrBootloaderGet)
+					})
+				}
+			})
+
+			t.Run("NotFound", func(t *testing.T) {
+				defer setup(t)()
+
+				adapterBootloader.EXPECT().
+					Get(ctx, "i386", "undionly.kpxe").
+					Return(nil, adapter.ErrBootloaderNotFound).
+					Once()
+
+				_, err := bootloader.Get(ctx, "i386/undionly.kpxe")
+				assert.ErrorIs(t, err, controller.ErrBootloaderNotFound)
+			})
+
+			t.Run("AdapterError", func(t *testing.T) {
+				defer setup(t)()
+
+				adapterBootloader.EXPECT().
+					Get(ctx, "i386", "undionly.kpxe").
+					Return(nil, assert.AnError).
+					Once()
+
+				_, err := bootloader.Get(ctx, "i386/undionly.kpxe")
+				assert.ErrorIs(t, err, assert.AnError)
+				assert.NotErrorIs(t, err, controller.ErrBootloaderNotFound)
+			})
+		})
+	})
+}
+
+func TestBootloader_EmbeddedScript(t *testing.T) {
+	var (
+		ctx context.Context
+
+		adapterBootloader *mockadapter.MockBootloader
+		bootloader        controller.Bootloader
+	)
+
+	const expectedScript = "#!ipxe\ndhcp\nchain http://ipxer.example.com/ipxe?" +
+		"uuid=${uuid}&buildarch=${buildarch:uristring}&platform=${platform:uristring}&mac=${netX/mac:hexhyp}" +
+		"&serial=${serial:uristring}&asset=${asset:uristring}&hostname=${hostname:uristring}&ip=${netX/ip}\n"
+
+	setup := func(t *testing.T) func() {
+		t.Helper()
+
+		ctx = context.Background()
+
+		adapterBootloader = mockadapter.NewMockBootloader(t)
+		bootloader = controller.NewBootloader(adapterBootloader, "http://ipxer.example.com/", nil)
+
+		return func() {
+			t.Helper()
+
+			adapterBootloader.AssertExpectations(t)
+		}
+	}
+
+	newBinary := func(paddingLength int) []byte {
+		return []byte("\x7fELF...prefix..." +
+			controller.EmbeddedScriptPlaceholder +
+			strings.Repeat(string(controller.EmbeddedScriptPadding), paddingLength) +
+			"\n...suffix...")
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		defer setup(t)()
+
+		input := newBinary(512)
+
+		adapterBootloader.EXPECT().
+			Get(ctx, "x86_64", "ipxe.efi").
+			Return(input, nil).
+			Once()
+
+		actual, err := bootloader.Get(ctx, "x86_64/ipxe.efi")
+		assert.NoError(t, err)
+		assert.Len(t, actual, len(input))
+		assert.True(t, bytes.HasPrefix(actual, []byte("\x7fELF...prefix..."+expectedScript+"###")))
+		assert.True(t, bytes.HasSuffix(actual, []byte("###\n...suffix...")))
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("PlaceholderNotFound", func(t *testing.T) {
+			defer setup(t)()
+
+			adapterBootloader.EXPECT().
+				Get(ctx, "x86_64", "ipxe.efi").
+				Return([]byte("stock ipxe binary"), nil).
+				Once()
+
+			_, err := bootloader.Get(ctx, "x86_64/ipxe.efi")
+			assert.ErrorIs(t, err, controller.ErrBootloaderGet)
+		})
+
+		t.Run("ScriptTooLong", func(t *testing.T) {
+			defer setup(t)()
+
+			adapterBootloader.EXPECT().
+				Get(ctx, "x86_64", "ipxe.efi").
+				Return(newBinary(8), nil).
+				Once()
+
+			_, err := bootloader.Get(ctx, "x86_64/ipxe.efi")
+			assert.ErrorIs(t, err, controller.ErrBootloaderGet)
+		})
+	})
+}
@@ -19,10 +19,13 @@ var (
 // ---------------------------------------------------- INTERFACE --------------------------------------------------- //
 
 type Content interface {
+	// GetByID resolves and transforms the exposed content. Templated contents are rendered with the facts about the
+	// machine described by the attributes and the params.
 	GetByID(
 		ctx context.Context,
 		contentID uuid.UUID,
 		attributes types.IPXESelectors,
+		params types.IpxeParams,
 	) ([]byte, error)
 }
 
@@ -46,6 +49,7 @@ func (c *content) GetByID(
 	ctx context.Context,
 	contentID uuid.UUID,
 	attributes types.IPXESelectors,
+	params types.IpxeParams,
 ) ([]byte, error) {
 	if contentID == uuid.Nil {
 		return nil, errors.Join(errUUIDCannotBeNil, ErrContentGetById)
@@ -57,16 +61,24 @@ func (c *content) GetByID(
 	}
 
 	contentName := list[0].ContentIDToNameMap[contentID]
-	cont := list[0].AdditionalContent[contentName]
-	// NB: mux.ResolveAndTransform will always render the content. Please call ResolveAndTransformBatch
-	// with the mux.ReturnExposedContentURL option to return a URL instead.
-	out, err := c.mux.ResolveAndTransform(ctx, cont, types.IPXESelectors{
-		UUID:      contentID, // the contentID takes precedence, thus should always overwrite the attribute uuid.
-		Buildarch: attributes.Buildarch,
-	})
+
+	selectors := attributes
+	selectors.UUID = contentID // the contentID takes precedence, thus should always overwrite the attribute uuid.
+
+	// NB: the batch is resolved in order for the template of the content to reference the other contents. The
+	// mux.ReturnExposedContentURL option must not be specified, as it would return the URL of the content.
+	out, err := c.mux.ResolveAndTransformBatch(
+		ctx,
+		list[0].AdditionalContent,
+		selectors,
+		WithContentNames(contentName),
+		WithMachine(types.NewMachine(attributes, params)),
+		// the assignment is unknown, as the content is requested by its ID.
+		WithAssignment(types.Assignment{ProfileName: list[0].Name}),
+	)
 	if err != nil {
 		return nil, errors.Join(err, ErrContentGetById)
 	}
 
-	return out, nil
+	return out[contentName], nil
 }
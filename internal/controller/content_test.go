@@ -73,8 +73,15 @@ func TestContent(t *testing.T) {
 
 	expectMux := func() {
 		mux.EXPECT().
-			ResolveAndTransform(mock.Anything, mock.Anything, mock.Anything).
-			Return(expectedMuxResult, expectedMuxErr).
+			ResolveAndTransformBatch(
+				ctx,
+				expectedProfileResult[0].AdditionalContent,
+				types.IPXESelectors{UUID: inputConfigID},                      // the contentID overwrites the attribute uuid.
+				mock.AnythingOfType("controller.ResolveTransformBatchOption"), // -> controller.WithContentNames
+				mock.AnythingOfType("controller.ResolveTransformBatchOption"), // -> controller.WithMachine
+				mock.AnythingOfType("controller.ResolveTransformBatchOption"), // -> controller.WithAssignment
+			).
+			Return(map[string][]byte{mustBeReturned: expectedMuxResult}, expectedMuxErr).
 			Once()
 	}
 
@@ -92,9 +99,11 @@ func TestContent(t *testing.T) {
 						},
 						mustBeReturned: {
 							Name:        mustBeReturned,
+							Exposed:     true,
 							ExposedUUID: inputConfigID,
 						},
 					},
+					ContentIDToNameMap: map[uuid.UUID]string{inputConfigID: mustBeReturned},
 				},
 			}
 
@@ -103,7 +112,7 @@ func TestContent(t *testing.T) {
 			expectProfile()
 			expectMux()
 
-			actual, err := content.GetByID(ctx, inputConfigID, types.IPXESelectors{})
+			actual, err := content.GetByID(ctx, inputConfigID, ipxeSelectors, types.IpxeParams{})
 			assert.NoError(t, err)
 			assert.Equal(t, expected, actual)
 		})
@@ -115,7 +124,7 @@ func TestContent(t *testing.T) {
 				expectedProfileResult = nil // no results
 				expectProfile()
 
-				_, err := content.GetByID(ctx, inputConfigID, ipxeSelectors)
+				_, err := content.GetByID(ctx, inputConfigID, ipxeSelectors, types.IpxeParams{})
 				assert.ErrorIs(t, err, controller.ErrContentNotFound)
 			})
 
@@ -125,7 +134,7 @@ func TestContent(t *testing.T) {
 				expectedProfileErr = assert.AnError
 				expectProfile()
 
-				_, err := content.GetByID(ctx, inputConfigID, ipxeSelectors)
+				_, err := content.GetByID(ctx, inputConfigID, ipxeSelectors, types.IpxeParams{})
 				assert.ErrorIs(t, err, expectedProfileErr)
 			})
 
@@ -141,9 +150,11 @@ func TestContent(t *testing.T) {
 							},
 							mustBeReturned: {
 								Name:        mustBeReturned,
+								Exposed:     true,
 								ExposedUUID: inputConfigID,
 							},
 						},
+						ContentIDToNameMap: map[uuid.UUID]string{inputConfigID: mustBeReturned},
 					},
 				}
 
@@ -152,7 +163,7 @@ func TestContent(t *testing.T) {
 				expectProfile()
 				expectMux()
 
-				_, err := content.GetByID(ctx, inputConfigID, ipxeSelectors)
+				_, err := content.GetByID(ctx, inputConfigID, ipxeSelectors, types.IpxeParams{})
 				assert.ErrorIs(t, err, expectedMuxErr)
 			})
 		})
@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	contentCacheSweepInterval = time.Minute
+
+	resolveCacheOperation   = "resolve"
+	transformCacheOperation = "transform"
+)
+
+var errRegisteringContentCacheMetrics = errors.New("registering content cache metrics")
+
+// ---------------------------------------------------- INTERFACES -------------------------------------------------- //
+
+// ContentCache caches the resolution and the transformations of contents.
+type ContentCache interface {
+	// Get returns the value cached under the key, unless it expired. The operation labels the hit and miss metrics.
+	Get(operation, key string) ([]byte, bool)
+	// Set caches the value under the key for the ttl.
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// --------------------------------------------------- CONSTRUCTORS ------------------------------------------------- //
+
+// NewContentCache returns an in-memory ContentCache, registering its metrics to the registerer:
+//   - `ipxer_content_cache_hits_total` and `ipxer_content_cache_misses_total`, labeled by operation, i.e. `resolve` or
+//     `transform`.
+//   - `ipxer_content_cache_entries`, the number of cached values including the expired ones not yet evicted.
+func NewContentCache(registerer prometheus.Registerer) (ContentCache, error) {
+	c := &contentCache{
+		entries:   make(map[string]contentCacheEntry),
+		lastSweep: time.Now(),
+		hits: prometheus.NewCounterVec(prometheus.CounterOpts{ //nolint:exhaustruct
+			Namespace: "ipxer",
+			Subsystem: "content_cache",
+			Name:      "hits_total",
+			Help:      "Number of resolutions and transformations served from the content cache.",
+		}, []string{"operation"}),
+		misses: prometheus.NewCounterVec(prometheus.CounterOpts{ //nolint:exhaustruct
+			Namespace: "ipxer",
+			Subsystem: "content_cache",
+			Name:      "misses_total",
+			Help:      "Number of resolutions and transformations missing from the content cache.",
+		}, []string{"operation"}),
+		size: prometheus.NewGauge(prometheus.GaugeOpts{ //nolint:exhaustruct
+			Namespace: "ipxer",
+			Subsystem: "content_cache",
+			Name:      "entries",
+			Help:      "Number of values in the content cache.",
+		}),
+	}
+
+	for _, collector := range []prometheus.Collector{c.hits, c.misses, c.size} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, errors.Join(err, errRegisteringContentCacheMetrics)
+		}
+	}
+
+	return c, nil
+}
+
+// ---------------------------------------------------- CACHE ------------------------------------------------------- //
+
+type contentCache struct {
+	mu        sync.Mutex
+	entries   map[string]contentCacheEntry
+	lastSweep time.Time
+
+	hits   *prometheus.CounterVec
+	misses *prometheus.CounterVec
+	size   prometheus.Gauge
+}
+
+type contentCacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func (c *contentCache) Get(operation, key string) ([]byte, bool) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if !ok || time.Now().After(entry.expiresAt) {
+		c.misses.WithLabelValues(operation).Inc()
+		return nil, false
+	}
+
+	c.hits.WithLabelValues(operation).Inc()
+
+	return entry.value, true
+}
+
+func (c *contentCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.entries[key] = contentCacheEntry{value: value, expiresAt: now.Add(ttl)}
+
+	// expired values are evicted periodically, e.g. the values of contents whose specification changed.
+	if now.Sub(c.lastSweep) > contentCacheSweepInterval {
+		for k, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+
+		c.lastSweep = now
+	}
+
+	c.size.Set(float64(len(c.entries)))
+}
+
+// ---------------------------------------------------- KEYS -------------------------------------------------------- //
+
+// resolveCacheKey keys the resolution of a content for the attributes, as webhooks may resolve distinct contents for
+// each of them.
+func resolveCacheKey(content types.Content, attributes types.Attributes) string {
+	return contentCacheKey(resolveCacheOperation, content.Hash, attributes)
+}
+
+// transformCacheKey keys the i-th transformation of a content for its input and the attributes. Keying by the input
+// ensures transformations are cached correctly even though the resolution or the rendering of the content changed.
+func transformCacheKey(content types.Content, i int, in []byte, attributes types.Attributes) string {
+	return contentCacheKey(transformCacheOperation, content.Hash, strconv.Itoa(i), in, attributes)
+}
+
+func contentCacheKey(operation, hash string, parts ...any) string {
+	h := sha256.New()
+	h.Write([]byte(operation))
+	h.Write([]byte(hash))
+
+	for _, part := range parts {
+		var b []byte
+
+		switch v := part.(type) {
+		case []byte:
+			b = v
+		case string:
+			b = []byte(v)
+		default:
+			// attributes are plain values, hence encoding them never fails.
+			b, _ = json.Marshal(v)
+		}
+
+		// parts are prefixed by their length, so their concatenation is unambiguous.
+		h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(b))))
+		h.Write(b)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,79 @@
+//go:build unit
+
+package controller_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/controller"
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentCache(t *testing.T) {
+	var (
+		registry *prometheus.Registry
+		cache    controller.ContentCache
+	)
+
+	setup := func(t *testing.T) func() {
+		t.Helper()
+
+		var err error
+
+		registry = prometheus.NewRegistry()
+		cache, err = controller.NewContentCache(registry)
+		require.NoError(t, err)
+
+		return func() {
+			t.Helper()
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		defer setup(t)()
+
+		_, ok := cache.Get("resolve", "key")
+		assert.False(t, ok)
+
+		cache.Set("key", []byte("value"), time.Minute)
+
+		out, ok := cache.Get("resolve", "key")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("value"), out)
+
+		assert.NoError(t, promtestutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP ipxer_content_cache_entries Number of values in the content cache.
+# TYPE ipxer_content_cache_entries gauge
+ipxer_content_cache_entries 1
+# HELP ipxer_content_cache_hits_total Number of resolutions and transformations served from the content cache.
+# TYPE ipxer_content_cache_hits_total counter
+ipxer_content_cache_hits_total{operation="resolve"} 1
+# HELP ipxer_content_cache_misses_total Number of resolutions and transformations missing from the content cache.
+# TYPE ipxer_content_cache_misses_total counter
+ipxer_content_cache_misses_total{operation="resolve"} 1
+`)))
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		defer setup(t)()
+
+		cache.Set("key", []byte("value"), time.Millisecond)
+		time.Sleep(5 * time.Millisecond)
+
+		_, ok := cache.Get("transform", "key")
+		assert.False(t, ok)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		defer setup(t)()
+
+		// metrics cannot be registered twice.
+		_, err := controller.NewContentCache(registry)
+		assert.Error(t, err)
+	})
+}
@@ -5,40 +5,52 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"text/template"
+	"text/template/parse"
 
 	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
 	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/templateutil"
 )
 
 var (
 	ErrIPXEFindProfileAndRender = errors.New("finding and rendering ipxe profile")
+	ErrInvalidIPXETemplate      = errors.New("invalid ipxe template")
 
 	errFallbackToDefaultAssignment = errors.New("fallback to default assignment")
 	errSelectingAssignment         = errors.New("selecting assignment")
 	errTemplatingIPXEProfile       = errors.New("templating ipxe profile")
 
-	fmtCannotSelectAssignmentWithSelectors = "cannot select assignment with selectors: uuid=%q & buildarch=%q"
+	fmtCannotSelectAssignmentWithSelectors = "cannot select assignment with selectors: uuid=%q & mac=%q & serial=%q & " +
+		"asset=%q & hostname=%q & buildarch=%q & platform=%q"
 )
 
 // ---------------------------------------------------- INTERFACES -------------------------------------------------- //
 
 type IPXE interface {
-	FindProfileAndRender(ctx context.Context, selectors types.IPXESelectors) ([]byte, error)
+	// FindProfileAndRender renders the profile assigned to the selectors. The facts about the machine and the params
+	// forwarded by the bootstrap script are available to the profile template as `{{ .Machine.<Field> }}` and
+	// `{{ .Params.<Field> }}`, e.g. `{{ .Machine.Hostname }}` or `{{ .Params.Memsize }}`.
+	FindProfileAndRender(ctx context.Context, selectors types.IPXESelectors, params types.IpxeParams) ([]byte, error)
 	Boostrap() []byte
 }
 
 // --------------------------------------------------- CONSTRUCTORS ------------------------------------------------- //
 
+// NewIPXE returns an IPXE controller. The bootstrap script forwards the specified params to the `/ipxe` endpoint, or
+// types.DefaultIPXEParams if none are specified.
 func NewIPXE(
 	assignment adapter.Assignment,
 	profile adapter.Profile,
 	mux ResolveTransformerMux,
+	params []types.IPXEParam,
 ) IPXE {
 	return &ipxe{
 		assignment: assignment,
 		profile:    profile,
 		mux:        mux,
+		bootstrap:  []byte(fmt.Sprintf(ipxeBootstrapFormat, bootstrapParams(params))),
 	}
 }
 
@@ -49,7 +61,7 @@ type ipxe struct {
 	profile    adapter.Profile
 	mux        ResolveTransformerMux
 
-	cachedBootstrap []byte
+	bootstrap []byte
 }
 
 // -------------------------------------------------------- FindProfileAndRender ------------------------------------ //
@@ -57,21 +69,24 @@ type ipxe struct {
 func (i *ipxe) FindProfileAndRender(
 	ctx context.Context,
 	selectors types.IPXESelectors,
+	params types.IpxeParams,
 ) ([]byte, error) {
 	assignment, err := i.assignment.FindBySelectors(ctx, selectors)
 	if errors.Is(err, adapter.ErrAssignmentNotFound) {
 		// fallback to default profile
-		defaultAssignment, defaultErr := i.assignment.FindDefaultByBuildarch(
-			ctx,
-			selectors.Buildarch,
-		)
+		defaultAssignment, defaultErr := i.assignment.FindDefault(ctx, selectors)
 		if defaultErr != nil {
 			return nil, errors.Join(
 				defaultErr,
 				fmt.Errorf(
 					fmtCannotSelectAssignmentWithSelectors,
 					selectors.UUID,
+					selectors.MAC,
+					selectors.Serial,
+					selectors.Asset,
+					selectors.Hostname,
 					selectors.Buildarch,
+					selectors.Platform,
 				),
 				errFallbackToDefaultAssignment,
 				errSelectingAssignment,
@@ -89,17 +104,21 @@ func (i *ipxe) FindProfileAndRender(
 		return nil, errors.Join(err, ErrIPXEFindProfileAndRender)
 	}
 
+	machine := types.NewMachine(selectors, params)
+
 	data, err := i.mux.ResolveAndTransformBatch(
 		ctx,
 		p.AdditionalContent,
 		selectors,
 		ReturnExposedContentURL,
+		WithMachine(machine),
+		WithAssignment(assignment),
 	)
 	if err != nil {
 		return nil, errors.Join(err, ErrIPXEFindProfileAndRender)
 	}
 
-	out, err := templateIPXEProfile(p.IPXETemplate, data)
+	out, err := templateIPXEProfile(p.IPXETemplate, data, machine, params)
 	if err != nil {
 		return nil, errors.Join(err, ErrIPXEFindProfileAndRender)
 	}
@@ -107,142 +126,217 @@ func (i *ipxe) FindProfileAndRender(
 	return out, nil
 }
 
-func templateIPXEProfile(ipxeTemplate string, data map[string][]byte) ([]byte, error) {
-	tpl, err := template.New("").Parse(ipxeTemplate)
+// Keys of the machine facts and of the forwarded params in the data of profile templates. They take precedence over
+// additional contents with the same names.
+const (
+	MachineTemplateKey = "Machine"
+	ParamsTemplateKey  = "Params"
+)
+
+func templateIPXEProfile(
+	ipxeTemplate string,
+	data map[string][]byte,
+	machine types.Machine,
+	params types.IpxeParams,
+) ([]byte, error) {
+	tpl, err := templateutil.New("").Parse(ipxeTemplate)
 	if err != nil {
 		return nil, errors.Join(err, errTemplatingIPXEProfile)
 	}
 
-	stringData := make(map[string]string)
+	return executeIPXETemplate(tpl, data, machine, params)
+}
+
+func executeIPXETemplate(
+	tpl *template.Template,
+	data map[string][]byte,
+	machine types.Machine,
+	params types.IpxeParams,
+) ([]byte, error) {
+	templateData := make(map[string]any)
 	for k, v := range data {
-		stringData[k] = string(v)
+		templateData[k] = string(v)
 	}
 
+	templateData[MachineTemplateKey] = machine
+	templateData[ParamsTemplateKey] = params
+
 	buf := bytes.NewBuffer(make([]byte, 0))
-	if err := tpl.Execute(buf, stringData); err != nil {
+	if err := tpl.Execute(buf, templateData); err != nil {
 		return nil, errors.Join(err, errTemplatingIPXEProfile)
 	}
 
 	return buf.Bytes(), nil
 }
 
-// -------------------------------------------------------- Bootstrap ----------------------------------------------- //
+// -------------------------------------------------------- ValidateIPXETemplate ------------------------------------ //
 
-func (i *ipxe) Boostrap() []byte {
-	if len(i.cachedBootstrap) > 0 {
-		return bytes.Clone(i.cachedBootstrap)
+const ipxeShebang = "#!ipxe"
+
+// ValidateIPXETemplate ensures an iPXE template starts with `#!ipxe`, only references the specified additional
+// contents, the machine facts and the params, and renders with placeholder contents.
+func ValidateIPXETemplate(ipxeTemplate string, contentNames []string) error {
+	if !strings.HasPrefix(ipxeTemplate, ipxeShebang) {
+		return errors.Join(fmt.Errorf("ipxe template must start with %q", ipxeShebang), ErrInvalidIPXETemplate)
+	}
+
+	tpl, err := templateutil.New("").Parse(ipxeTemplate)
+	if err != nil {
+		return errors.Join(err, ErrInvalidIPXETemplate)
+	}
+
+	known := map[string]struct{}{MachineTemplateKey: {}, ParamsTemplateKey: {}}
+	data := make(map[string][]byte, len(contentNames))
+
+	for _, name := range contentNames {
+		known[name] = struct{}{}
+		data[name] = []byte(fmt.Sprintf("placeholder-%s", name))
+	}
+
+	for _, ref := range templateReferences(tpl.Root, true) {
+		if _, ok := known[ref]; !ok {
+			return errors.Join(
+				fmt.Errorf("ipxe template references %q which is not an additional content", ref),
+				ErrInvalidIPXETemplate,
+			)
+		}
+	}
+
+	if _, err := executeIPXETemplate(tpl, data, types.Machine{}, types.IpxeParams{}); err != nil {
+		return errors.Join(err, ErrInvalidIPXETemplate)
 	}
 
-	// init boostrap
-	params := ""
-	for _, param := range orderedAllowedParamKeys {
-		paramType := allowedParamsWithType[param]
-		if params != "" {
-			params = fmt.Sprintf("%s&", params)
+	return nil
+}
+
+// templateReferences returns the keys of the template data referenced by a node, i.e. `.X` or `$.X`. Fields referenced
+// within `range` and `with` blocks are relative to their pipeline, thus only `$.X` are returned for these blocks.
+func templateReferences(node parse.Node, dotIsRoot bool) []string {
+	out := make([]string, 0)
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return out
+		}
+
+		for _, child := range n.Nodes {
+			out = append(out, templateReferences(child, dotIsRoot)...)
+		}
+	case *parse.ActionNode:
+		out = append(out, templateReferences(n.Pipe, dotIsRoot)...)
+	case *parse.PipeNode:
+		if n == nil {
+			return out
 		}
 
-		if paramType == none {
-			params = fmt.Sprintf("%s%s=${%s}", params, param, param)
-			continue
+		for _, cmd := range n.Cmds {
+			out = append(out, templateReferences(cmd, dotIsRoot)...)
+		}
+	case *parse.CommandNode:
+		if ref, ok := indexReference(n, dotIsRoot); ok {
+			out = append(out, ref)
 		}
 
-		params = fmt.Sprintf("%s%s=${%s:%s}", params, param, param, paramType)
+		for _, arg := range n.Args {
+			out = append(out, templateReferences(arg, dotIsRoot)...)
+		}
+	case *parse.ChainNode:
+		out = append(out, templateReferences(n.Node, dotIsRoot)...)
+	case *parse.FieldNode:
+		if dotIsRoot {
+			out = append(out, n.Ident[0])
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			out = append(out, n.Ident[1])
+		}
+	case *parse.IfNode:
+		out = append(out, templateReferences(n.Pipe, dotIsRoot)...)
+		out = append(out, templateReferences(n.List, dotIsRoot)...)
+		out = append(out, templateReferences(n.ElseList, dotIsRoot)...)
+	case *parse.RangeNode:
+		out = append(out, templateReferences(n.Pipe, dotIsRoot)...)
+		out = append(out, templateReferences(n.List, false)...)
+		out = append(out, templateReferences(n.ElseList, dotIsRoot)...)
+	case *parse.WithNode:
+		out = append(out, templateReferences(n.Pipe, dotIsRoot)...)
+		out = append(out, templateReferences(n.List, false)...)
+		out = append(out, templateReferences(n.ElseList, dotIsRoot)...)
+	case *parse.TemplateNode:
+		out = append(out, templateReferences(n.Pipe, dotIsRoot)...)
+	}
+
+	return out
+}
+
+// indexReference returns the key of the template data referenced by an `index . "x"` or `index $ "x"` command, as keys
+// such as content names containing hyphens cannot be referenced as fields.
+func indexReference(cmd *parse.CommandNode, dotIsRoot bool) (string, bool) {
+	if len(cmd.Args) < 3 { //nolint:mnd
+		return "", false
+	}
+
+	if ident, ok := cmd.Args[0].(*parse.IdentifierNode); !ok || ident.Ident != "index" {
+		return "", false
 	}
 
-	i.cachedBootstrap = []byte(fmt.Sprintf(ipxeBootstrapFormat, params))
+	switch data := cmd.Args[1].(type) {
+	case *parse.DotNode:
+		if !dotIsRoot {
+			return "", false
+		}
+	case *parse.VariableNode:
+		if len(data.Ident) != 1 || data.Ident[0] != "$" {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+
+	key, ok := cmd.Args[2].(*parse.StringNode)
+	if !ok {
+		return "", false
+	}
 
-	return bytes.Clone(i.cachedBootstrap)
+	return key.Text, true
 }
 
-// TODO: mac should be `NETWORK_IFACE/mac`.
+// -------------------------------------------------------- Bootstrap ----------------------------------------------- //
+
+func (i *ipxe) Boostrap() []byte {
+	return bytes.Clone(i.bootstrap)
+}
+
+// newEmbeddedScript returns the script embedded into bootloaders. As the embedded script runs before any network
+// interface is configured and is not fetched over HTTP, it must configure the network and chain to an absolute URL.
+func newEmbeddedScript(ipxerBaseURL string, params []types.IPXEParam) []byte {
+	return []byte(fmt.Sprintf(ipxeEmbeddedScriptFormat, ipxerBaseURL, bootstrapParams(params)))
+}
+
+// bootstrapParams returns the query parameters forwarded to the `/ipxe` endpoint, e.g. `uuid=${uuid}`. Default params
+// are returned if none are specified.
+func bootstrapParams(params []types.IPXEParam) string {
+	if len(params) == 0 {
+		params = types.DefaultIPXEParams
+	}
+
+	out := make([]string, 0, len(params))
+	for _, param := range params {
+		out = append(out, param.String())
+	}
+
+	return strings.Join(out, "&")
+}
 
 const (
 	// #!ipxe
-	// chain ipxe?uuid=${uuid}&mac=${mac:hexhyp}&domain=${domain}&hostname=${hostname}&serial=${serial}&arch=${buildarch:uristring}
+	// chain ipxe?uuid=${uuid}&buildarch=${buildarch:uristring}&platform=${platform:uristring}&mac=${netX/mac:hexhyp}...
 	ipxeBootstrapFormat = `#!ipxe
 chain ipxe?%s
 `
-	none      ipxeParamType = ""
-	uriString ipxeParamType = "uristring"
-)
-
-type ipxeParamType string
-
-var (
-	orderedAllowedParamKeys = []string{
-		types.Uuid,
-		types.Buildarch,
-	}
-
-	allowedParamsWithType = map[string]ipxeParamType{
-		// types.Mac,
-		// types.BusType,
-		// types.BusLoc,
-		// types.BusID,
-		// types.Chip,
-		// types.Ssid,
-		// types.ActiveScan,
-		// types.Key,
-
-		// IPv4 settings
-
-		// types.Ip,
-		// types.Netmask,
-		// types.Gateway,
-		// types.Dns,
-		// types.Domain,
-
-		// Boot settings
-
-		// types.Filename,
-		// types.NextServer,
-		// types.RootPath,
-		// types.SanFilename,
-		// types.InitiatorIqn,
-		// types.KeepSan,
-		// types.SkipSanBoot,
-
-		// Host settings
-
-		// types.Hostname,
-		types.Uuid: none,
-		// types.UserClass,
-		// types.Manufacturer,
-		// types.Product,
-		// types.Serial,
-		// types.Asset,
-
-		// Authentication settings
-
-		// types.Username,
-		// types.Password,
-		// types.ReverseUsername,
-		// types.ReversePassword,
-
-		// Cryptography settings
-
-		// types.Crosscert,
-		// types.Trust,
-		// types.Cert,
-		// types.Privkey,
-
-		// Miscellaneous settings
-
-		types.Buildarch: uriString,
-		// types.Cpumodel,
-		// types.Cpuvendor,
-		// types.DhcpServer,
-		// types.Keymap,
-		// types.Memsize,
-		// types.Platform,
-		// types.Priority,
-		// types.Scriptlet,
-		// types.Syslog,
-		// types.Syslogs,
-		// types.Sysmac,
-		// types.Unixtime,
-		// types.UseCached,
-		// types.Version,
-		// types.Vram,
-	}
+	ipxeEmbeddedScriptFormat = `#!ipxe
+dhcp
+chain %s/ipxe?%s
+`
 )
@@ -5,11 +5,13 @@ package controller_test
 import (
 	"context"
 	"fmt"
+	"net"
 	"testing"
 
 	"k8s.io/utils/ptr"
 
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
 	"github.com/alexandremahdhaoui/ipxer/internal/controller"
@@ -37,13 +39,13 @@ func TestIPXE_FindProfileAndRender(t *testing.T) {
 		t.Helper()
 
 		ctx = context.Background()
-		inputSelectors = types.IPXESelectors{UUID: uuid.New(), Buildarch: "arm64"}
+		inputSelectors = types.IPXESelectors{UUID: uuid.New(), Buildarch: "arm64", Platform: "efi"}
 
 		assignment = mockadapter.NewMockAssignment(t)
 		profile = mockadapter.NewMockProfile(t)
 		mux = mockcontroller.NewMockResolveTransformerMux(t)
 
-		ipxe = controller.NewIPXE(assignment, profile, mux)
+		ipxe = controller.NewIPXE(assignment, profile, mux, nil)
 
 		return func() {
 			t.Helper()
@@ -85,11 +87,13 @@ func TestIPXE_FindProfileAndRender(t *testing.T) {
 						expectedProfile.AdditionalContent,
 						inputSelectors,
 						mock.AnythingOfType("controller.ResolveTransformBatchOption"), // -> controller.ReturnExposedContentURL
+						mock.AnythingOfType("controller.ResolveTransformBatchOption"), // -> controller.WithMachine
+						mock.AnythingOfType("controller.ResolveTransformBatchOption"), // -> controller.WithAssignment
 					).
 					Return(expectedResolvedAndTransformedContent, nil).
 					Once()
 
-				actual, err := ipxe.FindProfileAndRender(ctx, inputSelectors)
+				actual, err := ipxe.FindProfileAndRender(ctx, inputSelectors, types.IpxeParams{})
 				assert.NoError(t, err)
 				assert.Equal(t, expected, actual)
 			})
@@ -180,11 +184,13 @@ func TestIPXE_FindProfileAndRender(t *testing.T) {
 								expectedProfile.AdditionalContent,
 								inputSelectors,
 								mock.AnythingOfType("controller.ResolveTransformBatchOption"), // -> controller.ReturnExposedContentURL
+								mock.AnythingOfType("controller.ResolveTransformBatchOption"), // -> controller.WithMachine
+								mock.AnythingOfType("controller.ResolveTransformBatchOption"), // -> controller.WithAssignment
 							).
 							Return(expectedResolvedAndTransformedContent, nil).
 							Once()
 
-						actual, err := ipxe.FindProfileAndRender(ctx, inputSelectors)
+						actual, err := ipxe.FindProfileAndRender(ctx, inputSelectors, types.IpxeParams{})
 						assert.NoError(t, err)
 						assert.Equal(t, expected, actual)
 					})
@@ -192,7 +198,7 @@ func TestIPXE_FindProfileAndRender(t *testing.T) {
 			})
 		})
 
-		t.Run("FindDefaultByBuildarch", func(t *testing.T) {
+		t.Run("FindDefault", func(t *testing.T) {
 			defer setup(t)()
 
 			expectedDefaultProfileName := "default-profile-arm64"
@@ -226,7 +232,7 @@ func TestIPXE_FindProfileAndRender(t *testing.T) {
 				Once()
 
 			assignment.EXPECT().
-				FindDefaultByBuildarch(ctx, inputSelectors.Buildarch).
+				FindDefault(ctx, inputSelectors).
 				Return(expectedDefaultAssignment, nil).
 				Once()
 
@@ -241,16 +247,47 @@ func TestIPXE_FindProfileAndRender(t *testing.T) {
 					expectedDefaultProfile.AdditionalContent,
 					inputSelectors,
 					mock.AnythingOfType("controller.ResolveTransformBatchOption"), // -> controller.ReturnExposedContentURL
+					mock.AnythingOfType("controller.ResolveTransformBatchOption"), // -> controller.WithMachine
+					mock.AnythingOfType("controller.ResolveTransformBatchOption"), // -> controller.WithAssignment
 				).
 				Return(expectedResolvedAndTransformedAdditionalBatch, nil).
 				Once()
 
-			actual, err := ipxe.FindProfileAndRender(ctx, inputSelectors)
+			actual, err := ipxe.FindProfileAndRender(ctx, inputSelectors, types.IpxeParams{})
 			assert.NoError(t, err)
 			assert.Equal(t, expected, actual)
 		})
 	})
 
+	t.Run("MachineAndParams", func(t *testing.T) {
+		defer setup(t)()
+
+		memsize := int32(4096)
+		ip := net.IPv4(10, 0, 0, 1)
+		expectedProfile := types.Profile{
+			IPXETemplate: "hostname={{ .Machine.Hostname }} ip={{ .Machine.IP }} memsize={{ .Params.Memsize }}",
+		}
+		inputSelectors.Hostname = "node-0"
+
+		assignment.EXPECT().
+			FindBySelectors(ctx, inputSelectors).
+			Return(types.Assignment{ProfileName: "profile"}, nil).
+			Once()
+
+		profile.EXPECT().Get(ctx, "profile").Return(expectedProfile, nil).Once()
+
+		mux.EXPECT().
+			ResolveAndTransformBatch(
+				ctx, expectedProfile.AdditionalContent, inputSelectors, mock.Anything, mock.Anything, mock.Anything,
+			).
+			Return(map[string][]byte{}, nil).
+			Once()
+
+		actual, err := ipxe.FindProfileAndRender(ctx, inputSelectors, types.IpxeParams{Memsize: &memsize, Ip: &ip})
+		assert.NoError(t, err)
+		assert.Equal(t, "hostname=node-0 ip=10.0.0.1 memsize=4096", string(actual))
+	})
+
 	t.Run("Failure", func(t *testing.T) {
 		defer setup(t)()
 
@@ -259,8 +296,21 @@ func TestIPXE_FindProfileAndRender(t *testing.T) {
 }
 
 func TestIpxe_Bootstrap(t *testing.T) {
-	expected := "#!ipxe\nchain ipxe?uuid=${uuid}&buildarch=${buildarch:uristring}\n"
-	actual := controller.NewIPXE(nil, nil, nil).Boostrap()
+	expected := "#!ipxe\nchain ipxe?uuid=${uuid}&buildarch=${buildarch:uristring}&platform=${platform:uristring}" +
+		"&mac=${netX/mac:hexhyp}&serial=${serial:uristring}&asset=${asset:uristring}&hostname=${hostname:uristring}" +
+		"&ip=${netX/ip}\n"
+	actual := controller.NewIPXE(nil, nil, nil, nil).Boostrap()
+
+	assert.Equal(t, expected, string(actual))
+}
+
+func TestIpxe_BootstrapWithParams(t *testing.T) {
+	params, err := types.ParseIPXEParams([]string{"mac:hexhyp", "memsize"})
+	require.NoError(t, err)
+
+	expected := "#!ipxe\nchain ipxe?uuid=${uuid}&buildarch=${buildarch:uristring}" +
+		"&mac=${netX/mac:hexhyp}&memsize=${memsize}\n"
+	actual := controller.NewIPXE(nil, nil, nil, params).Boostrap()
 
 	assert.Equal(t, expected, string(actual))
 }
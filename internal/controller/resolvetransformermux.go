@@ -1,12 +1,21 @@
 package controller
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
 
 	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
 	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/templateutil"
 )
 
 const (
@@ -16,9 +25,12 @@ const (
 var (
 	ErrResolveAndTransform      = errors.New("resolve and transform content")
 	ErrResolveAndTransformBatch = errors.New("resolve and transform batch")
+	ErrContentDependencyCycle   = errors.New("contents depend on each other")
 
 	ErrResolverUnknown    = errors.New("unknown resolver")
 	ErrTransformerUnknown = errors.New("unknown transformer")
+
+	errTemplatingContent = errors.New("templating content")
 )
 
 // ---------------------------------------------------- INTERFACES -------------------------------------------------- //
@@ -36,15 +48,24 @@ type ResolveTransformerMux interface {
 
 // --------------------------------------------------- CONSTRUCTORS ------------------------------------------------- //
 
+// NewResolveTransformerMux returns a ResolveTransformerMux. Contents specifying a cache TTL are cached in the cache.
+// Contents of a batch are resolved and transformed with at most maxConcurrency concurrent calls, unbounded if not
+// positive. Each resolution and transformation of a content times out after contentTimeout, unless it is not positive.
 func NewResolveTransformerMux(
 	ipxerBaseURL string,
 	resolvers map[types.ResolverKind]adapter.Resolver,
 	transformers map[types.TransformerKind]adapter.Transformer,
+	cache ContentCache,
+	maxConcurrency int,
+	contentTimeout time.Duration,
 ) ResolveTransformerMux {
 	return &resolveTransformerMux{
-		ipxerBaseURL: ipxerBaseURL,
-		resolvers:    resolvers,
-		transformers: transformers,
+		ipxerBaseURL:   ipxerBaseURL,
+		resolvers:      resolvers,
+		transformers:   transformers,
+		cache:          cache,
+		maxConcurrency: maxConcurrency,
+		contentTimeout: contentTimeout,
 	}
 }
 
@@ -53,48 +74,137 @@ func NewResolveTransformerMux(
 type resolveTransformerMux struct {
 	resolvers    map[types.ResolverKind]adapter.Resolver
 	transformers map[types.TransformerKind]adapter.Transformer
+	cache        ContentCache
 
-	ipxerBaseURL string
+	ipxerBaseURL   string
+	maxConcurrency int
+	contentTimeout time.Duration
 }
 
+// ResolveAndTransform resolves and transforms a content. Templated contents are rendered with the machine facts only:
+// please use ResolveAndTransformBatch to render them with the other contents of their profile.
 func (r *resolveTransformerMux) ResolveAndTransform(
 	ctx context.Context,
 	content types.Content,
 	selectors types.IPXESelectors,
+) ([]byte, error) {
+	ctx, cancel := r.contentContext(ctx)
+	defer cancel()
+
+	machine := types.NewMachine(selectors, types.IpxeParams{})
+	attributes := types.Attributes{IPXESelectors: selectors, Machine: machine}
+
+	out, err := r.resolve(ctx, content, attributes)
+	if err != nil {
+		return nil, errors.Join(err, ErrResolveAndTransform)
+	}
+
+	if content.Template {
+		tpl, err := templateutil.New(content.Name).Parse(string(out))
+		if err != nil {
+			return nil, errors.Join(err, errTemplatingContent, ErrResolveAndTransform)
+		}
+
+		data := map[string]any{MachineTemplateKey: machine}
+		if out, err = executeContentTemplate(tpl, data); err != nil {
+			return nil, errors.Join(err, ErrResolveAndTransform)
+		}
+	}
+
+	if out, err = r.transform(ctx, content, out, attributes); err != nil {
+		return nil, errors.Join(err, ErrResolveAndTransform)
+	}
+
+	return out, nil
+}
+
+// resolve resolves a content, or returns its cached resolution. Resolutions of object references are never cached, so
+// changes to the referenced objects are served as soon as they are observed.
+func (r *resolveTransformerMux) resolve(
+	ctx context.Context,
+	content types.Content,
+	attributes types.Attributes,
 ) ([]byte, error) {
 	resolver, ok := r.resolvers[content.ResolverKind]
 	if !ok {
-		return nil, errors.Join(ErrResolverUnknown, ErrResolveAndTransform)
+		return nil, ErrResolverUnknown
 	}
 
-	out, err := resolver.Resolve(ctx, content, selectors)
+	cached := content.CacheTTL > 0 && content.ResolverKind != types.ObjectRefResolverKind
+	key := ""
+
+	if cached {
+		key = resolveCacheKey(content, attributes)
+		if out, ok := r.cache.Get(resolveCacheOperation, key); ok {
+			return out, nil
+		}
+	}
+
+	out, err := resolver.Resolve(ctx, content, attributes)
 	if err != nil {
-		return nil, errors.Join(err, ErrResolveAndTransform)
+		return nil, err //nolint:wrapcheck
+	}
+
+	if cached {
+		r.cache.Set(key, out, content.CacheTTL)
 	}
 
-	for _, transformerConfig := range content.PostTransformers {
+	return out, nil
+}
+
+// transform applies the post transformers of a content, or returns their cached output.
+func (r *resolveTransformerMux) transform(
+	ctx context.Context,
+	content types.Content,
+	in []byte,
+	attributes types.Attributes,
+) ([]byte, error) {
+	out := in
+
+	for i, transformerConfig := range content.PostTransformers {
 		transformer, ok := r.transformers[transformerConfig.Kind]
 		if !ok {
-			return nil, errors.Join(ErrTransformerUnknown, ErrResolveAndTransform)
+			return nil, ErrTransformerUnknown
 		}
 
-		out, err = transformer.Transform(ctx, transformerConfig, out, selectors)
-		if err != nil {
-			return nil, errors.Join(err, ErrResolveAndTransform)
+		key := ""
+
+		if content.CacheTTL > 0 {
+			key = transformCacheKey(content, i, out, attributes)
+			if cachedOut, ok := r.cache.Get(transformCacheOperation, key); ok {
+				out = cachedOut
+				continue
+			}
+		}
+
+		var err error
+		if out, err = transformer.Transform(ctx, transformerConfig, out, attributes); err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		if content.CacheTTL > 0 {
+			r.cache.Set(key, out, content.CacheTTL)
 		}
 	}
 
 	return out, nil
 }
 
-// -------------------------------------------------- ResolveAndTransformBatch -------------------------------------- //
+func executeContentTemplate(tpl *template.Template, data map[string]any) ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0))
+	if err := tpl.Execute(buf, data); err != nil {
+		return nil, errors.Join(err, errTemplatingContent)
+	}
 
-// TODO: ResolveAndTransformBatch should return the URL corresponding to the ConfigID of the content if the content has
-//      ExposedConfigID set to true. (only in the case that the func is called by controller.IPXE)
-//      !!! Otherwise create a special func for controller.Content called ResolveAndTransform which only takes a
-//          types.Content as an argument and fully compute the Resolve/Transformation.
-//      !!! Then ResolveAndTransformBatch will only resolve and transform if types.Content.ExposedConfigID != true.
+	return buf.Bytes(), nil
+}
+
+// -------------------------------------------------- ResolveAndTransformBatch -------------------------------------- //
 
+// ResolveAndTransformBatch resolves and transforms the contents of a batch concurrently. Templated contents are
+// rendered with the machine facts and the other contents of the batch, exposed contents being templated as their URL.
+// Hence, the unexposed contents referenced by a template are rendered first, and contents cannot reference each other
+// in a cycle. The returned error names every content which failed.
 func (r *resolveTransformerMux) ResolveAndTransformBatch(
 	ctx context.Context,
 	batch map[string]types.Content,
@@ -103,29 +213,349 @@ func (r *resolveTransformerMux) ResolveAndTransformBatch(
 ) (map[string][]byte, error) {
 	opts := new(ResolveTransformBatchOptions).apply(options...)
 
-	output := make(map[string][]byte)
+	machine := types.NewMachine(selectors, types.IpxeParams{})
+	if opts.machine != nil {
+		machine = *opts.machine
+	}
+
+	names := opts.names
+	if len(names) == 0 {
+		names = slices.Sorted(maps.Keys(batch))
+	}
+
+	output := make(map[string][]byte, len(names))
+	render := make([]string, 0, len(names))
+
+	for _, name := range names {
+		cont, ok := batch[name]
+		if !ok {
+			return nil, errors.Join(fmt.Errorf("content %q", name), ErrContentNotFound, ErrResolveAndTransformBatch)
+		}
 
-	for name, cont := range batch {
 		if opts.returnURLInsteadOfResolveAndTransform && cont.Exposed {
-			output[name] = []byte(fmt.Sprintf(
-				"%s/%s/%s", r.ipxerBaseURL, ipxerAPIContentPath, cont.ExposedUUID.String()))
+			output[name] = []byte(r.exposedContentURL(cont, machine))
 			continue
 		}
 
-		result, err := r.ResolveAndTransform(ctx, cont, selectors)
-		if err != nil {
+		render = append(render, name)
+	}
+
+	b := &batchRenderer{
+		mux:   r,
+		batch: batch,
+		attributes: types.Attributes{
+			IPXESelectors:  selectors,
+			Machine:        machine,
+			AssignmentName: opts.assignment.Name,
+			ProfileName:    opts.assignment.ProfileName,
+		},
+		data:      map[string]any{MachineTemplateKey: machine},
+		resolved:  make(map[string][]byte),
+		templates: make(map[string]*template.Template),
+		deps:      make(map[string][]string),
+		rendered:  make(map[string][]byte),
+	}
+
+	for name, cont := range batch {
+		if cont.Exposed {
+			b.data[name] = r.exposedContentURL(cont, machine)
+		}
+	}
+
+	if err := b.resolve(ctx, render); err != nil {
+		return nil, errors.Join(err, ErrResolveAndTransformBatch)
+	}
+
+	levels, err := b.levels(render)
+	if err != nil {
+		return nil, errors.Join(err, ErrResolveAndTransformBatch)
+	}
+
+	for _, level := range levels {
+		if err := b.render(ctx, level); err != nil {
 			return nil, errors.Join(err, ErrResolveAndTransformBatch)
 		}
+	}
 
-		output[name] = result
+	for _, name := range render {
+		output[name] = b.rendered[name]
 	}
 
 	return output, nil
 }
 
+// batchRenderer renders the contents of a batch after the contents their templates depend on.
+type batchRenderer struct {
+	mux        *resolveTransformerMux
+	batch      map[string]types.Content
+	attributes types.Attributes
+
+	// data of the templates, i.e. the machine facts, the URLs of exposed contents and the rendered unexposed contents.
+	data map[string]any
+
+	mu        sync.Mutex
+	resolved  map[string][]byte
+	templates map[string]*template.Template
+	deps      map[string][]string
+	rendered  map[string][]byte
+}
+
+// resolve resolves the contents and the contents their templates depend on. As dependencies are only known once
+// templates are resolved, contents are resolved concurrently by waves of dependencies.
+func (b *batchRenderer) resolve(ctx context.Context, names []string) error {
+	for wave := names; len(wave) > 0; {
+		if err := b.mux.forEach(ctx, wave, b.resolveContent); err != nil {
+			return err
+		}
+
+		next := make([]string, 0)
+
+		for _, name := range wave {
+			for _, dep := range b.deps[name] {
+				if _, ok := b.resolved[dep]; !ok && !slices.Contains(next, dep) {
+					next = append(next, dep)
+				}
+			}
+		}
+
+		wave = next
+	}
+
+	return nil
+}
+
+func (b *batchRenderer) resolveContent(ctx context.Context, name string) error {
+	cont := b.batch[name]
+
+	out, err := b.mux.resolve(ctx, cont, b.attributes)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("resolving content %q", name))
+	}
+
+	var (
+		tpl  *template.Template
+		deps []string
+	)
+
+	if cont.Template {
+		if tpl, err = templateutil.New(name).Parse(string(out)); err != nil {
+			return errors.Join(err, errTemplatingContent, fmt.Errorf("templating content %q", name))
+		}
+
+		deps = b.dependencies(tpl)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.resolved[name] = out
+	b.templates[name] = tpl
+	b.deps[name] = deps
+
+	return nil
+}
+
+// dependencies returns the unexposed contents of the batch referenced by the template, in order. Exposed contents are
+// templated as their URL, hence they are not dependencies.
+func (b *batchRenderer) dependencies(tpl *template.Template) []string {
+	out := make([]string, 0)
+
+	for _, ref := range templateReferences(tpl.Root, true) {
+		if dep, ok := b.batch[ref]; ok && !dep.Exposed && !slices.Contains(out, ref) {
+			out = append(out, ref)
+		}
+	}
+
+	return out
+}
+
+// levels groups the resolved contents by depth, i.e. contents of a level only depend on contents of previous levels.
+// An error is returned if contents depend on each other in a cycle.
+func (b *batchRenderer) levels(names []string) ([][]string, error) {
+	depths := make(map[string]int)
+	visiting := make(map[string]bool)
+
+	var depth func(name string, path []string) (int, error)
+
+	depth = func(name string, path []string) (int, error) {
+		if d, ok := depths[name]; ok {
+			return d, nil
+		}
+
+		if visiting[name] {
+			return 0, errors.Join(
+				fmt.Errorf("content %q depends on itself: %s", name, strings.Join(append(path, name), " -> ")),
+				ErrContentDependencyCycle,
+			)
+		}
+
+		visiting[name] = true
+		defer delete(visiting, name)
+
+		out := 0
+
+		for _, dep := range b.deps[name] {
+			d, err := depth(dep, append(path, name))
+			if err != nil {
+				return 0, err
+			}
+
+			out = max(out, d+1)
+		}
+
+		depths[name] = out
+
+		return out, nil
+	}
+
+	for _, name := range names {
+		if _, err := depth(name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([][]string, 0)
+
+	for _, name := range slices.Sorted(maps.Keys(depths)) {
+		for len(out) <= depths[name] {
+			out = append(out, make([]string, 0))
+		}
+
+		out[depths[name]] = append(out[depths[name]], name)
+	}
+
+	return out, nil
+}
+
+// render renders and transforms the contents of a level concurrently. The rendered unexposed contents are then added to
+// the data of the templates of the next levels.
+func (b *batchRenderer) render(ctx context.Context, level []string) error {
+	if err := b.mux.forEach(ctx, level, b.renderContent); err != nil {
+		return err
+	}
+
+	for _, name := range level {
+		if !b.batch[name].Exposed {
+			b.data[name] = string(b.rendered[name])
+		}
+	}
+
+	return nil
+}
+
+func (b *batchRenderer) renderContent(ctx context.Context, name string) error {
+	cont := b.batch[name]
+	out := b.resolved[name]
+
+	if tpl := b.templates[name]; tpl != nil {
+		var err error
+		if out, err = executeContentTemplate(tpl, b.data); err != nil {
+			return errors.Join(err, fmt.Errorf("templating content %q", name))
+		}
+	}
+
+	out, err := b.mux.transform(ctx, cont, out, b.attributes)
+	if err != nil {
+		return errors.Join(err, fmt.Errorf("transforming content %q", name))
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rendered[name] = out
+
+	return nil
+}
+
+// forEach calls f for each content concurrently, with at most maxConcurrency concurrent calls. Each call is given a
+// context bounded by the content timeout. Errors of all calls are returned.
+func (r *resolveTransformerMux) forEach(
+	ctx context.Context,
+	names []string,
+	f func(ctx context.Context, name string) error,
+) error {
+	workers := r.maxConcurrency
+	if workers <= 0 {
+		workers = len(names)
+	}
+
+	sem := make(chan struct{}, workers)
+	errs := make([]error, len(names))
+	wg := &sync.WaitGroup{}
+
+	for i, name := range names {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = errors.Join(ctx.Err(), fmt.Errorf("content %q", name))
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			contentCtx, cancel := r.contentContext(ctx)
+			defer cancel()
+
+			errs[i] = f(contentCtx, name)
+		}()
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// contentContext returns a child context of the request context bounded by the content timeout, if any.
+func (r *resolveTransformerMux) contentContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.contentTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, r.contentTimeout)
+}
+
+// exposedContentURL returns the URL of an exposed content, e.g. `https://ipxer.example.com/content/<uuid>?...`.
+func (r *resolveTransformerMux) exposedContentURL(content types.Content, machine types.Machine) string {
+	return fmt.Sprintf("%s/%s/%s?%s",
+		r.ipxerBaseURL, ipxerAPIContentPath, content.ExposedUUID.String(), exposedContentQuery(machine))
+}
+
+// exposedContentQuery returns the query of exposed content URLs, which forwards the facts about the machine to the
+// `/content` endpoint, e.g. `buildarch=arm64&uuid=47c6da67-...`.
+func exposedContentQuery(machine types.Machine) string {
+	query := url.Values{}
+	query.Set(types.Uuid, machine.UUID)
+	query.Set(types.Buildarch, machine.Buildarch)
+
+	for _, optional := range []struct{ key, value string }{
+		{key: types.Platform, value: machine.Platform},
+		{key: types.Mac, value: machine.MAC},
+		{key: types.Serial, value: machine.Serial},
+		{key: types.Asset, value: machine.Asset},
+		{key: types.Hostname, value: machine.Hostname},
+		{key: types.Ip, value: machine.IP},
+	} {
+		if optional.value != "" {
+			query.Set(optional.key, optional.value)
+		}
+	}
+
+	return query.Encode()
+}
+
 type (
 	ResolveTransformBatchOptions struct {
 		returnURLInsteadOfResolveAndTransform bool
+
+		machine    *types.Machine
+		assignment types.Assignment
+		names      []string
 	}
 
 	ResolveTransformBatchOption func(options *ResolveTransformBatchOptions)
@@ -144,3 +574,27 @@ func (o *ResolveTransformBatchOptions) apply(options ...ResolveTransformBatchOpt
 func ReturnExposedContentURL(options *ResolveTransformBatchOptions) {
 	options.returnURLInsteadOfResolveAndTransform = true
 }
+
+// WithMachine specifies the facts about the machine templated into contents. They default to the facts of the
+// selectors.
+func WithMachine(machine types.Machine) ResolveTransformBatchOption {
+	return func(options *ResolveTransformBatchOptions) {
+		options.machine = &machine
+	}
+}
+
+// WithAssignment specifies the assignment, and hence the profile, selected for the machine. Their names are sent to
+// webhooks.
+func WithAssignment(assignment types.Assignment) ResolveTransformBatchOption {
+	return func(options *ResolveTransformBatchOptions) {
+		options.assignment = assignment
+	}
+}
+
+// WithContentNames restricts the output of resolvetransformermux.ResolveAndTransformBatch to the specified contents.
+// Other contents of the batch are only resolved if the templates of the specified contents depend on them.
+func WithContentNames(names ...string) ResolveTransformBatchOption {
+	return func(options *ResolveTransformBatchOptions) {
+		options.names = names
+	}
+}
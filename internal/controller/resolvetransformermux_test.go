@@ -5,7 +5,10 @@ package controller_test
 import (
 	"context"
 	"fmt"
+	"net"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"k8s.io/utils/ptr"
 
@@ -15,15 +18,18 @@ import (
 	"github.com/alexandremahdhaoui/ipxer/internal/util/mocks/mockadapter"
 	"github.com/alexandremahdhaoui/ipxer/internal/util/testutil"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestResolveTransformerMux(t *testing.T) {
 	var (
-		ctx            context.Context
-		inputSelectors types.IPXESelectors
-		inputBatch     map[string]types.Content
+		ctx             context.Context
+		inputSelectors  types.IPXESelectors
+		inputAttributes types.Attributes
+		inputBatch      map[string]types.Content
 
 		inlineResolver    *mockadapter.MockResolver
 		objectRefResolver *mockadapter.MockResolver
@@ -38,7 +44,11 @@ func TestResolveTransformerMux(t *testing.T) {
 		mux controller.ResolveTransformerMux
 	)
 
-	const baseURL = "https://example.com"
+	const (
+		baseURL        = "https://example.com"
+		maxConcurrency = 2
+		contentTimeout = 50 * time.Millisecond
+	)
 
 	setup := func(t *testing.T) func() {
 		t.Helper()
@@ -49,6 +59,11 @@ func TestResolveTransformerMux(t *testing.T) {
 			Buildarch: "arm64",
 		}
 
+		inputAttributes = types.Attributes{
+			IPXESelectors: inputSelectors,
+			Machine:       types.NewMachine(inputSelectors, types.IpxeParams{}),
+		}
+
 		inputBatch = make(map[string]types.Content)
 
 		inlineResolver = mockadapter.NewMockResolver(t)
@@ -69,7 +84,10 @@ func TestResolveTransformerMux(t *testing.T) {
 			types.WebhookTransformerKind: webhookTransformer,
 		}
 
-		mux = controller.NewResolveTransformerMux(baseURL, resolvers, transformers)
+		cache, err := controller.NewContentCache(prometheus.NewRegistry())
+		require.NoError(t, err)
+
+		mux = controller.NewResolveTransformerMux(baseURL, resolvers, transformers, cache, maxConcurrency, contentTimeout)
 
 		return func() {
 			t.Helper()
@@ -117,17 +135,17 @@ func TestResolveTransformerMux(t *testing.T) {
 						expected[inputContent.Name] = expectedTransformationResult1
 
 						resolvers[kind].(*mockadapter.MockResolver).EXPECT().
-							Resolve(ctx, inputContent, inputSelectors).
+							Resolve(mock.Anything, inputContent, inputAttributes).
 							Return(expectedResolverResult, nil).
 							Once()
 
 						butaneTransformer.EXPECT().
-							Transform(ctx, inputContent.PostTransformers[0], expectedResolverResult, inputSelectors).
+							Transform(mock.Anything, inputContent.PostTransformers[0], expectedResolverResult, inputAttributes).
 							Return(expectedTransformationResult0, nil).
 							Once()
 
 						webhookTransformer.EXPECT().
-							Transform(ctx, inputContent.PostTransformers[1], expectedTransformationResult0, inputSelectors).
+							Transform(mock.Anything, inputContent.PostTransformers[1], expectedTransformationResult0, inputAttributes).
 							Return(expectedTransformationResult1, nil).
 							Once()
 					}
@@ -139,7 +157,232 @@ func TestResolveTransformerMux(t *testing.T) {
 			})
 		}
 
+		t.Run("ReturnExposedContentURL", func(t *testing.T) {
+			defer setup(t)()
+
+			contentID := uuid.New()
+			inputSelectors.MAC = net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+			inputBatch["exposed"] = types.Content{Name: "exposed", Exposed: true, ExposedUUID: contentID}
+
+			actual, err := mux.ResolveAndTransformBatch(ctx, inputBatch, inputSelectors, controller.ReturnExposedContentURL)
+			assert.NoError(t, err)
+			assert.Equal(t, map[string][]byte{"exposed": []byte(fmt.Sprintf(
+				"%s/content/%s?buildarch=arm64&mac=aa%%3Abb%%3Acc%%3Add%%3Aee%%3Aff&uuid=%s",
+				baseURL, contentID, inputSelectors.UUID,
+			))}, actual)
+		})
+
+		t.Run("Template", func(t *testing.T) {
+			defer setup(t)()
+
+			exposedID := uuid.New()
+			inputBatch = map[string]types.Content{
+				"base":     {Name: "base", ResolverKind: types.InlineResolverKind, Inline: "hello"},
+				"exposed":  {Name: "exposed", Exposed: true, ExposedUUID: exposedID},
+				"unneeded": {Name: "unneeded", ResolverKind: types.InlineResolverKind, Inline: "unneeded"},
+				"middle": {
+					Name:         "middle",
+					Template:     true,
+					ResolverKind: types.InlineResolverKind,
+					Inline:       "{{ .base }} {{ .Machine.Hostname }}",
+				},
+				"config": {
+					Name:         "config",
+					Template:     true,
+					ResolverKind: types.InlineResolverKind,
+					Inline:       "{{ .middle }} {{ .exposed }}",
+				},
+			}
+
+			machine := types.Machine{UUID: inputSelectors.UUID.String(), Buildarch: "arm64", Hostname: "node-0"}
+			inputAttributes.Machine = machine
+
+			// only "config" and its dependencies must be resolved.
+			inlineResolver.EXPECT().Resolve(mock.Anything, mock.Anything, inputAttributes).
+				RunAndReturn(func(_ context.Context, c types.Content, _ types.Attributes) ([]byte, error) {
+					return []byte(c.Inline), nil
+				}).Times(3)
+
+			actual, err := mux.ResolveAndTransformBatch(ctx, inputBatch, inputSelectors,
+				controller.WithContentNames("config"),
+				controller.WithMachine(machine),
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, map[string][]byte{"config": []byte(fmt.Sprintf(
+				"hello node-0 %s/content/%s?buildarch=arm64&hostname=node-0&uuid=%s",
+				baseURL, exposedID, inputSelectors.UUID,
+			))}, actual)
+		})
+
+		t.Run("Assignment", func(t *testing.T) {
+			defer setup(t)()
+
+			inputBatch["config"] = types.Content{Name: "config", ResolverKind: types.InlineResolverKind, Inline: "config"}
+			inputAttributes.AssignmentName = "worker"
+			inputAttributes.ProfileName = "fcos"
+
+			inlineResolver.EXPECT().Resolve(mock.Anything, inputBatch["config"], inputAttributes).
+				Return([]byte("config"), nil).
+				Once()
+
+			actual, err := mux.ResolveAndTransformBatch(ctx, inputBatch, inputSelectors,
+				controller.WithAssignment(types.Assignment{Name: "worker", ProfileName: "fcos"}),
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, map[string][]byte{"config": []byte("config")}, actual)
+		})
+
+		t.Run("Cache", func(t *testing.T) {
+			defer setup(t)()
+
+			transformers := []types.TransformerConfig{{Kind: types.ButaneTransformerKind}}
+			inputBatch = map[string]types.Content{
+				"webhook": {
+					Name:             "webhook",
+					Hash:             "webhook-hash",
+					CacheTTL:         time.Minute,
+					ResolverKind:     types.WebhookResolverKind,
+					PostTransformers: transformers,
+				},
+				"objectRef": {
+					Name:             "objectRef",
+					Hash:             "objectRef-hash",
+					CacheTTL:         time.Minute,
+					ResolverKind:     types.ObjectRefResolverKind,
+					PostTransformers: transformers,
+				},
+				"uncached": {
+					Name:             "uncached",
+					Hash:             "uncached-hash",
+					ResolverKind:     types.InlineResolverKind,
+					PostTransformers: transformers,
+				},
+			}
+
+			// webhooks are resolved once, whereas object references are always resolved.
+			webhookResolver.EXPECT().Resolve(mock.Anything, inputBatch["webhook"], inputAttributes).
+				Return([]byte("webhook"), nil).Once()
+			objectRefResolver.EXPECT().Resolve(mock.Anything, inputBatch["objectRef"], inputAttributes).
+				Return([]byte("objectRef"), nil).Once()
+			objectRefResolver.EXPECT().Resolve(mock.Anything, inputBatch["objectRef"], inputAttributes).
+				Return([]byte("changed"), nil).Once()
+			inlineResolver.EXPECT().Resolve(mock.Anything, inputBatch["uncached"], inputAttributes).
+				Return([]byte("uncached"), nil).Times(2)
+
+			// transformations are cached by input: the changed object is transformed again.
+			for _, in := range []string{"webhook", "objectRef", "changed"} {
+				butaneTransformer.EXPECT().Transform(mock.Anything, transformers[0], []byte(in), inputAttributes).
+					Return([]byte(in+"-transformed"), nil).Once()
+			}
+
+			butaneTransformer.EXPECT().Transform(mock.Anything, transformers[0], []byte("uncached"), inputAttributes).
+				Return([]byte("uncached-transformed"), nil).Times(2)
+
+			first, err := mux.ResolveAndTransformBatch(ctx, inputBatch, inputSelectors)
+			assert.NoError(t, err)
+
+			second, err := mux.ResolveAndTransformBatch(ctx, inputBatch, inputSelectors)
+			assert.NoError(t, err)
+
+			assert.Equal(t, first["webhook"], second["webhook"])
+			assert.Equal(t, []byte("objectRef-transformed"), first["objectRef"])
+			assert.Equal(t, []byte("changed-transformed"), second["objectRef"])
+		})
+
+		t.Run("Concurrency", func(t *testing.T) {
+			defer setup(t)()
+
+			for i := range 5 {
+				name := fmt.Sprintf("content-%d", i)
+				inputBatch[name] = types.Content{Name: name, ResolverKind: types.InlineResolverKind}
+			}
+
+			var running, maxRunning atomic.Int32
+
+			inlineResolver.EXPECT().Resolve(mock.Anything, mock.Anything, inputAttributes).
+				RunAndReturn(func(_ context.Context, c types.Content, _ types.Attributes) ([]byte, error) {
+					n := running.Add(1)
+					defer running.Add(-1)
+
+					for m := maxRunning.Load(); n > m; m = maxRunning.Load() {
+						if maxRunning.CompareAndSwap(m, n) {
+							break
+						}
+					}
+
+					time.Sleep(10 * time.Millisecond)
+
+					return []byte(c.Name), nil
+				}).Times(5)
+
+			actual, err := mux.ResolveAndTransformBatch(ctx, inputBatch, inputSelectors)
+			assert.NoError(t, err)
+			assert.Len(t, actual, 5)
+			assert.Equal(t, []byte("content-3"), actual["content-3"])
+			assert.Equal(t, int32(maxConcurrency), maxRunning.Load())
+		})
+
 		t.Run("Failure", func(t *testing.T) {
+			t.Run("errors name contents", func(t *testing.T) {
+				defer setup(t)()
+
+				inputBatch = map[string]types.Content{
+					"a":  {Name: "a", ResolverKind: types.InlineResolverKind},
+					"b":  {Name: "b", ResolverKind: types.InlineResolverKind},
+					"ok": {Name: "ok", ResolverKind: types.InlineResolverKind},
+				}
+
+				inlineResolver.EXPECT().Resolve(mock.Anything, mock.Anything, inputAttributes).
+					RunAndReturn(func(_ context.Context, c types.Content, _ types.Attributes) ([]byte, error) {
+						if c.Name == "ok" {
+							return []byte("ok"), nil
+						}
+
+						return nil, assert.AnError
+					}).Times(3)
+
+				_, err := mux.ResolveAndTransformBatch(ctx, inputBatch, inputSelectors)
+				assert.ErrorIs(t, err, assert.AnError)
+				assert.ErrorContains(t, err, `resolving content "a"`)
+				assert.ErrorContains(t, err, `resolving content "b"`)
+				assert.NotContains(t, err.Error(), `content "ok"`)
+			})
+
+			t.Run("timeout", func(t *testing.T) {
+				defer setup(t)()
+
+				inputBatch["slow"] = types.Content{Name: "slow", ResolverKind: types.InlineResolverKind}
+
+				inlineResolver.EXPECT().Resolve(mock.Anything, mock.Anything, inputAttributes).
+					RunAndReturn(func(ctx context.Context, _ types.Content, _ types.Attributes) ([]byte, error) {
+						<-ctx.Done()
+
+						return nil, ctx.Err()
+					}).Once()
+
+				_, err := mux.ResolveAndTransformBatch(ctx, inputBatch, inputSelectors)
+				assert.ErrorIs(t, err, context.DeadlineExceeded)
+				assert.ErrorContains(t, err, `resolving content "slow"`)
+			})
+
+			t.Run("dependency cycle", func(t *testing.T) {
+				defer setup(t)()
+
+				inputBatch = map[string]types.Content{
+					"a": {Name: "a", Template: true, ResolverKind: types.InlineResolverKind, Inline: "{{ .b }}"},
+					"b": {Name: "b", Template: true, ResolverKind: types.InlineResolverKind, Inline: "{{ .a }}"},
+				}
+
+				inlineResolver.EXPECT().Resolve(mock.Anything, mock.Anything, inputAttributes).
+					RunAndReturn(func(_ context.Context, c types.Content, _ types.Attributes) ([]byte, error) {
+						return []byte(c.Inline), nil
+					}).Times(2)
+
+				_, err := mux.ResolveAndTransformBatch(ctx, inputBatch, inputSelectors)
+				assert.ErrorIs(t, err, controller.ErrContentDependencyCycle)
+				assert.ErrorContains(t, err, "a -> b -> a")
+			})
+
 			t.Run("unknown resolver", func(t *testing.T) {
 				defer setup(t)()
 
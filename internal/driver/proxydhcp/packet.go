@@ -0,0 +1,208 @@
+package proxydhcp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
+)
+
+// BOOTP operations and DHCP message types (RFC 2131 & RFC 2132).
+const (
+	opBootRequest byte = 1
+	opBootReply   byte = 2
+
+	msgDiscover byte = 1
+	msgOffer    byte = 2
+	msgRequest  byte = 3
+	msgAck      byte = 5
+	msgInform   byte = 8
+)
+
+// DHCP options used by PXE clients (RFC 2132, RFC 3004, RFC 4578).
+const (
+	optPad               byte = 0
+	optVendorSpecific    byte = 43
+	optMessageType       byte = 53
+	optServerIdentifier  byte = 54
+	optVendorClass       byte = 60
+	optBootfileName      byte = 67
+	optUserClass         byte = 77
+	optClientArch        byte = 93
+	optClientMachineGUID byte = 97
+	optEnd               byte = 255
+
+	// pxeDiscoveryControl is the PXE vendor sub-option controlling boot server discovery. The value 8 instructs the
+	// client to skip discovery and download the boot file specified in the offer.
+	pxeDiscoveryControl     byte = 6
+	pxeDiscoveryUseBootfile byte = 8
+
+	pxeClientVendorClass = "PXEClient"
+	ipxeUserClass        = "iPXE"
+)
+
+const (
+	headerLength  = 236
+	fileOffset    = 108
+	fileLength    = 128
+	minPacketSize = headerLength + 4
+	maxPacketSize = 1500
+)
+
+var (
+	magicCookie = []byte{99, 130, 83, 99}
+
+	errPacketTooShort     = errors.New("dhcp packet too short")
+	errInvalidMagicCookie = errors.New("invalid dhcp magic cookie")
+	errInvalidOption      = errors.New("invalid dhcp option")
+)
+
+// ---------------------------------------------------- PACKET ------------------------------------------------------ //
+
+type packet struct {
+	op     byte
+	htype  byte
+	hlen   byte
+	xid    [4]byte
+	flags  uint16
+	ciaddr net.IP
+	giaddr net.IP
+	chaddr [16]byte
+
+	options map[byte][]byte
+}
+
+func parsePacket(b []byte) (packet, error) {
+	if len(b) < minPacketSize {
+		return packet{}, errPacketTooShort
+	}
+
+	if !bytes.Equal(b[headerLength:headerLength+4], magicCookie) {
+		return packet{}, errInvalidMagicCookie
+	}
+
+	p := packet{
+		op:      b[0],
+		htype:   b[1],
+		hlen:    b[2],
+		flags:   binary.BigEndian.Uint16(b[10:12]),
+		ciaddr:  net.IP(bytes.Clone(b[12:16])),
+		giaddr:  net.IP(bytes.Clone(b[24:28])),
+		options: make(map[byte][]byte),
+	}
+
+	copy(p.xid[:], b[4:8])
+	copy(p.chaddr[:], b[28:44])
+
+	opts := b[minPacketSize:]
+	for i := 0; i < len(opts); {
+		code := opts[i]
+
+		switch code {
+		case optPad:
+			i++
+			continue
+		case optEnd:
+			return p, nil
+		}
+
+		if i+1 >= len(opts) || i+2+int(opts[i+1]) > len(opts) {
+			return packet{}, errors.Join(fmt.Errorf("option %d", code), errInvalidOption)
+		}
+
+		length := int(opts[i+1])
+		// RFC 3396: options may be split across multiple instances which must be concatenated.
+		p.options[code] = append(p.options[code], opts[i+2:i+2+length]...)
+		i += 2 + length
+	}
+
+	return p, nil
+}
+
+func (p packet) messageType() byte {
+	if v := p.options[optMessageType]; len(v) == 1 {
+		return v[0]
+	}
+
+	return 0
+}
+
+// isPXEClient reports whether the vendor class identifier (option 60) starts with "PXEClient".
+func (p packet) isPXEClient() bool {
+	return bytes.HasPrefix(p.options[optVendorClass], []byte(pxeClientVendorClass))
+}
+
+// isIPXE reports whether the client identifies itself as iPXE through the user class (option 77). iPXE sends the raw
+// "iPXE" string, while RFC 3004 compliant clients prefix each user class with its length.
+func (p packet) isIPXE() bool {
+	return bytes.Contains(p.options[optUserClass], []byte(ipxeUserClass))
+}
+
+// clientArch returns the client system architecture (option 93). If the option is missing, it falls back to the
+// architecture advertised in the vendor class identifier.
+func (p packet) clientArch() (v1alpha1.ClientArch, bool) {
+	if v := p.options[optClientArch]; len(v) >= 2 {
+		return v1alpha1.ClientArch(binary.BigEndian.Uint16(v[:2])), true
+	}
+
+	return v1alpha1.ClientArchFromVendorClass(string(p.options[optVendorClass]))
+}
+
+// ---------------------------------------------------- REPLY ------------------------------------------------------- //
+
+type reply struct {
+	messageType byte
+	serverIP    net.IP
+	nextServer  net.IP
+	bootfile    string
+}
+
+func (p packet) marshalReply(r reply) []byte {
+	out := make([]byte, minPacketSize, maxPacketSize)
+
+	out[0] = opBootReply
+	out[1] = p.htype
+	out[2] = p.hlen
+	copy(out[4:8], p.xid[:])
+	binary.BigEndian.PutUint16(out[10:12], p.flags)
+	copy(out[12:16], p.ciaddr.To4())
+	copy(out[20:24], r.nextServer.To4()) // siaddr
+	copy(out[24:28], p.giaddr.To4())
+	copy(out[28:44], p.chaddr[:])
+
+	if len(r.bootfile) < fileLength {
+		copy(out[fileOffset:fileOffset+fileLength], r.bootfile)
+	}
+
+	copy(out[headerLength:], magicCookie)
+
+	out = appendOption(out, optMessageType, []byte{r.messageType})
+	out = appendOption(out, optServerIdentifier, r.serverIP.To4())
+	out = appendOption(out, optVendorClass, []byte(pxeClientVendorClass))
+
+	if guid, ok := p.options[optClientMachineGUID]; ok {
+		out = appendOption(out, optClientMachineGUID, guid)
+	}
+
+	out = appendOption(out, optVendorSpecific, []byte{pxeDiscoveryControl, 1, pxeDiscoveryUseBootfile, optEnd})
+	out = appendOption(out, optBootfileName, []byte(r.bootfile))
+
+	return append(out, optEnd)
+}
+
+// appendOption appends an option, splitting its value into multiple instances if longer than 255 bytes (RFC 3396).
+func appendOption(b []byte, code byte, value []byte) []byte {
+	for {
+		chunk := value[:min(len(value), 255)]
+		b = append(b, code, byte(len(chunk)))
+		b = append(b, chunk...)
+
+		value = value[len(chunk):]
+		if len(value) == 0 {
+			return b
+		}
+	}
+}
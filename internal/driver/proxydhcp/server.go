@@ -0,0 +1,256 @@
+package proxydhcp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"path"
+	"sync"
+
+	"github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
+)
+
+const (
+	DefaultAddr    = ":67"
+	DefaultPXEAddr = ":4011"
+)
+
+var (
+	// ErrServerClosed is returned by the Server's Serve and ListenAndServe methods after a call to Shutdown.
+	ErrServerClosed = errors.New("proxydhcp: server closed")
+
+	// DefaultBootfiles maps firmware platforms to the filename of their bootloader.
+	DefaultBootfiles = map[v1alpha1.Platform]string{ //nolint:gochecknoglobals
+		v1alpha1.PCBIOS: "undionly.kpxe",
+		v1alpha1.EFI:    "ipxe.efi",
+	}
+
+	broadcastAddr = &net.UDPAddr{IP: net.IPv4bcast, Port: 68} //nolint:gochecknoglobals
+)
+
+// Server is a ProxyDHCP server (PXE specification v2.1). It never leases addresses: it complements the existing DHCP
+// server by answering PXE clients with the boot file matching their architecture, and iPXE clients with IPXEBootURL.
+//
+// It listens for broadcast DHCPDISCOVER on Addr and answers with a DHCPOFFER. Clients may then send a DHCPREQUEST to
+// the PXE boot server on PXEAddr, which is answered with a DHCPACK.
+type Server struct {
+	// Addr is the UDP address receiving broadcast DHCP requests, e.g. ":67".
+	Addr string
+	// PXEAddr is the UDP address receiving PXE boot server requests, e.g. ":4011".
+	PXEAddr string
+	// ServerIP is the IPv4 address of this server, sent as the DHCP server identifier (option 54).
+	ServerIP net.IP
+	// NextServer is the IPv4 address of the TFTP server serving the boot files. Defaults to ServerIP.
+	NextServer net.IP
+	// IPXEBootURL is the boot file handed to iPXE clients, e.g. `http://ipxer.example.com/boot.ipxe`. iPXE clients are
+	// ignored if empty, as handing them a bootloader would result in a boot loop.
+	IPXEBootURL string
+	// Bootfiles maps firmware platforms to the filename of their bootloader. Clients are handed the boot file
+	// `<buildarch>/<filename>`, as served by ipxer-tftp. Clients with an unsupported architecture are ignored.
+	Bootfiles map[v1alpha1.Platform]string
+
+	mu     sync.Mutex
+	conns  []net.PacketConn
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func New(serverIP net.IP, ipxeBootURL string) *Server {
+	return &Server{
+		Addr:        DefaultAddr,
+		PXEAddr:     DefaultPXEAddr,
+		ServerIP:    serverIP,
+		IPXEBootURL: ipxeBootURL,
+		Bootfiles:   DefaultBootfiles,
+	}
+}
+
+// ---------------------------------------------------- LIFECYCLE --------------------------------------------------- //
+
+func (s *Server) ListenAndServe() error {
+	dhcpConn, err := net.ListenPacket("udp4", s.Addr)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	pxeConn, err := net.ListenPacket("udp4", s.PXEAddr)
+	if err != nil {
+		_ = dhcpConn.Close()
+		return err //nolint:wrapcheck
+	}
+
+	return s.Serve(dhcpConn, pxeConn)
+}
+
+// Serve answers DHCPDISCOVER received on dhcpConn and DHCPREQUEST or DHCPINFORM received on pxeConn. It returns when
+// either connection fails, after closing both of them.
+func (s *Server) Serve(dhcpConn, pxeConn net.PacketConn) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+
+		return ErrServerClosed
+	}
+
+	s.conns = append(s.conns, dhcpConn, pxeConn)
+	s.mu.Unlock()
+
+	errCh := make(chan error, 2)
+
+	for _, conn := range []net.PacketConn{dhcpConn, pxeConn} {
+		s.wg.Add(1)
+
+		go func() {
+			defer s.wg.Done()
+
+			errCh <- s.serve(conn, conn == pxeConn)
+		}()
+	}
+
+	err := <-errCh
+
+	_ = dhcpConn.Close()
+	_ = pxeConn.Close()
+
+	if s.isClosed() {
+		return ErrServerClosed
+	}
+
+	return err
+}
+
+// Shutdown stops the server and waits for the ongoing requests to be answered or for ctx to be done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+
+	var err error
+	for _, conn := range s.conns {
+		err = errors.Join(err, ignoreClosed(conn.Close()))
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Server) serve(conn net.PacketConn, pxe bool) error {
+	buf := make([]byte, maxPacketSize)
+
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		s.handle(conn, bytes.Clone(buf[:n]), addr, pxe)
+	}
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closed
+}
+
+func ignoreClosed(err error) error {
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+
+	return err
+}
+
+// ---------------------------------------------------- HANDLER ----------------------------------------------------- //
+
+func (s *Server) handle(conn net.PacketConn, b []byte, addr net.Addr, pxe bool) {
+	p, err := parsePacket(b)
+	if err != nil || p.op != opBootRequest || !p.isPXEClient() {
+		return // not a PXE client: the request is left to the DHCP server.
+	}
+
+	var (
+		messageType byte
+		dst         net.Addr
+	)
+
+	switch mt := p.messageType(); {
+	case !pxe && mt == msgDiscover:
+		messageType, dst = msgOffer, broadcastAddr
+
+		// Replies to relayed requests are sent to the relay agent.
+		if !p.giaddr.IsUnspecified() {
+			dst = &net.UDPAddr{IP: p.giaddr, Port: 67}
+		}
+	case pxe && (mt == msgRequest || mt == msgInform):
+		messageType, dst = msgAck, addr
+	default:
+		return
+	}
+
+	bootfile, ok := s.bootfile(p)
+	if !ok {
+		return
+	}
+
+	nextServer := s.NextServer
+	if nextServer == nil {
+		nextServer = s.ServerIP
+	}
+
+	out := p.marshalReply(reply{
+		messageType: messageType,
+		serverIP:    s.ServerIP,
+		nextServer:  nextServer,
+		bootfile:    bootfile,
+	})
+
+	mac := net.HardwareAddr(p.chaddr[:min(int(p.hlen), len(p.chaddr))]).String()
+
+	if _, err := conn.WriteTo(out, dst); err != nil {
+		slog.Error("❌ sending proxydhcp reply", "error", err, "mac", mac, "remote", dst.String())
+		return
+	}
+
+	slog.Info("✅ answered pxe client", "mac", mac, "bootfile", bootfile, "remote", dst.String())
+}
+
+// bootfile returns the boot file for the client, and false if the client must be ignored.
+func (s *Server) bootfile(p packet) (string, bool) {
+	if p.isIPXE() {
+		return s.IPXEBootURL, s.IPXEBootURL != ""
+	}
+
+	arch, ok := p.clientArch()
+	if !ok {
+		return "", false
+	}
+
+	buildarch, platform, ok := arch.BuildarchAndPlatform()
+	if !ok {
+		slog.Debug("ignoring pxe client with unsupported architecture", "clientArch", arch)
+		return "", false
+	}
+
+	filename, ok := s.Bootfiles[platform]
+	if !ok {
+		slog.Debug("ignoring pxe client with unsupported platform", "platform", platform)
+		return "", false
+	}
+
+	return path.Join(buildarch.String(), filename), true
+}
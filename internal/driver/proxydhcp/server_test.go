@@ -0,0 +1,221 @@
+//go:build unit
+
+package proxydhcp_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/driver/proxydhcp"
+)
+
+const (
+	ipxeBootURL = "http://ipxer.example.com/boot.ipxe"
+
+	msgDiscover = 1
+	msgRequest  = 3
+	msgAck      = 5
+)
+
+var (
+	serverIP   = net.IPv4(10, 0, 0, 1)
+	nextServer = net.IPv4(10, 0, 0, 2)
+	xid        = []byte{0xde, 0xad, 0xbe, 0xef}
+	guid       = append([]byte{0}, bytes.Repeat([]byte{0x42}, 16)...)
+)
+
+func TestServer(t *testing.T) {
+	var (
+		server  *proxydhcp.Server
+		pxeAddr net.Addr
+		client  net.PacketConn
+	)
+
+	setup := func(t *testing.T) func() {
+		t.Helper()
+
+		server = proxydhcp.New(serverIP, ipxeBootURL)
+		server.NextServer = nextServer
+
+		dhcpConn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		pxeConn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		pxeAddr = pxeConn.LocalAddr()
+
+		go func() {
+			if err := server.Serve(dhcpConn, pxeConn); !errors.Is(err, proxydhcp.ErrServerClosed) {
+				assert.NoError(t, err)
+			}
+		}()
+
+		client, err = net.ListenPacket("udp4", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		return func() {
+			t.Helper()
+
+			require.NoError(t, client.Close())
+			require.NoError(t, server.Shutdown(context.Background()))
+		}
+	}
+
+	t.Run("Ack", func(t *testing.T) {
+		for _, tt := range []struct {
+			name     string
+			options  map[byte][]byte
+			expected string
+		}{
+			{
+				name:     "BIOS",
+				options:  map[byte][]byte{93: {0, 0}},
+				expected: "i386/undionly.kpxe",
+			},
+			{
+				name:     "X8664EFI",
+				options:  map[byte][]byte{93: {0, 7}},
+				expected: "x86_64/ipxe.efi",
+			},
+			{
+				name:     "Arm64EFIFromVendorClass",
+				options:  map[byte][]byte{60: []byte("PXEClient:Arch:00011:UNDI:003016")},
+				expected: "arm64/ipxe.efi",
+			},
+			{
+				name:     "IPXE",
+				options:  map[byte][]byte{93: {0, 7}, 77: []byte("iPXE")},
+				expected: ipxeBootURL,
+			},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				defer setup(t)()
+
+				send(t, client, pxeAddr, newPacket(msgRequest, tt.options))
+
+				b := receive(t, client)
+				require.NotNil(t, b)
+
+				assert.Equal(t, byte(2), b[0])
+				assert.Equal(t, xid, b[4:8])
+				assert.Equal(t, nextServer.To4(), net.IP(b[20:24]))
+				assert.Equal(t, tt.expected, string(bytes.TrimRight(b[108:236], "\x00")))
+
+				options := parseOptions(b[240:])
+				assert.Equal(t, []byte{msgAck}, options[53])
+				assert.Equal(t, []byte(serverIP.To4()), options[54])
+				assert.Equal(t, []byte("PXEClient"), options[60])
+				assert.Equal(t, guid, options[97])
+				assert.Equal(t, tt.expected, string(options[67]))
+			})
+		}
+	})
+
+	t.Run("Ignored", func(t *testing.T) {
+		for _, tt := range []struct {
+			name        string
+			messageType byte
+			options     map[byte][]byte
+		}{
+			{
+				name:        "NotAPXEClient",
+				messageType: msgRequest,
+				options:     map[byte][]byte{60: []byte("MSFT 5.0"), 93: {0, 7}},
+			},
+			{
+				name:        "UnsupportedArch",
+				messageType: msgRequest,
+				options:     map[byte][]byte{93: {0, 2}},
+			},
+			{
+				name:        "X8664EFIHTTP",
+				messageType: msgRequest,
+				options:     map[byte][]byte{93: {0, 16}},
+			},
+			{
+				name:        "DiscoverOnPXEPort",
+				messageType: msgDiscover,
+				options:     map[byte][]byte{93: {0, 7}},
+			},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				defer setup(t)()
+
+				send(t, client, pxeAddr, newPacket(tt.messageType, tt.options))
+				assert.Nil(t, receive(t, client))
+			})
+		}
+	})
+}
+
+// newPacket returns a BOOTREQUEST sent by a PXE client. The vendor class and client machine identifier options are set
+// unless overridden.
+func newPacket(messageType byte, options map[byte][]byte) []byte {
+	b := make([]byte, 240)
+	b[0], b[1], b[2] = 1, 1, 6
+	copy(b[4:8], xid)
+	copy(b[28:34], []byte{0x52, 0x54, 0x00, 0x12, 0x34, 0x56})
+	copy(b[236:240], []byte{99, 130, 83, 99})
+
+	opts := map[byte][]byte{
+		53: {messageType},
+		60: []byte("PXEClient:Arch:00000:UNDI:002001"),
+		97: guid,
+	}
+
+	for k, v := range options {
+		opts[k] = v
+	}
+
+	for k, v := range opts {
+		b = append(b, k, byte(len(v)))
+		b = append(b, v...)
+	}
+
+	return append(b, 255)
+}
+
+func parseOptions(b []byte) map[byte][]byte {
+	out := make(map[byte][]byte)
+
+	for i := 0; i+1 < len(b) && b[i] != 255; i += 2 + int(b[i+1]) {
+		out[b[i]] = append(out[b[i]], b[i+2:i+2+int(b[i+1])]...)
+	}
+
+	return out
+}
+
+func send(t *testing.T, conn net.PacketConn, addr net.Addr, b []byte) {
+	t.Helper()
+
+	_, err := conn.WriteTo(b, addr)
+	require.NoError(t, err)
+}
+
+// receive returns the next packet received by conn, or nil if none is received before the deadline.
+func receive(t *testing.T, conn net.PacketConn) []byte {
+	t.Helper()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(200*time.Millisecond)))
+
+	buf := make([]byte, 1500)
+
+	n, _, err := conn.ReadFrom(buf)
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return nil
+	}
+
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, n, 240)
+
+	return buf[:n]
+}
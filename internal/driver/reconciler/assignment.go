@@ -0,0 +1,251 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
+	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var (
+	_ reconcile.Reconciler = &Assignment{}
+
+	errReconcilingAssignment = errors.New("reconciling assignment")
+	errUpdatingStatus        = errors.New("updating status")
+)
+
+func NewAssignment(c client.Client, assignment adapter.Assignment, profile adapter.Profile) *Assignment {
+	return &Assignment{
+		client:     c,
+		assignment: assignment,
+		profile:    profile,
+	}
+}
+
+// Assignment reconciles the status of Assignments. An Assignment is Ready if its profile exists and its subject
+// selectors do not conflict with the ones of other assignments.
+type Assignment struct {
+	client     client.Client
+	assignment adapter.Assignment
+	profile    adapter.Profile
+}
+
+// SetupWithManager registers the reconciler. As the status of an assignment depends on its profile and on the
+// assignments whose selectors overlap with its own, changes to their spec trigger its reconciliation. Status updates,
+// e.g. the resolved revisions of profiles, are ignored.
+func (a *Assignment) SetupWithManager(mgr ctrl.Manager) error {
+	specChanged := builder.WithPredicates(predicate.GenerationChangedPredicate{})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("assignment").
+		For(&v1alpha1.Assignment{}, specChanged).
+		Watches(&v1alpha1.Assignment{}, handler.EnqueueRequestsFromMapFunc(a.MapOverlappingAssignments), specChanged).
+		Watches(&v1alpha1.Profile{}, handler.EnqueueRequestsFromMapFunc(a.MapReferencingAssignments), specChanged).
+		Complete(a) //nolint:wrapcheck
+}
+
+func (a *Assignment) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	obj := new(v1alpha1.Assignment)
+	if err := a.client.Get(ctx, req.NamespacedName, obj); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err) //nolint:wrapcheck
+	}
+
+	c, err := a.check(ctx, obj)
+	if err != nil {
+		return reconcile.Result{}, errors.Join(err, errReconcilingAssignment)
+	}
+
+	if !setConditions(&obj.Status.Conditions, obj.Generation, c) {
+		return reconcile.Result{}, nil
+	}
+
+	if err := a.client.Status().Update(ctx, obj); err != nil {
+		return reconcile.Result{}, errors.Join(err, errUpdatingStatus, errReconcilingAssignment)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// check returns the condition of the assignment. An error is returned if the condition cannot be determined.
+func (a *Assignment) check(ctx context.Context, obj *v1alpha1.Assignment) (condition, error) {
+	for _, f := range []func(context.Context, *v1alpha1.Assignment) (condition, error){
+		a.checkProfile,
+		a.checkDefaultIsUnique,
+		a.checkSelectorsAreUnique,
+	} {
+		c, err := f(ctx, obj)
+		if err != nil || !c.isReady() {
+			return c, err
+		}
+	}
+
+	return ready(), nil
+}
+
+func (a *Assignment) checkProfile(ctx context.Context, obj *v1alpha1.Assignment) (condition, error) {
+	_, err := a.profile.Get(ctx, obj.Spec.ProfileName)
+
+	switch {
+	case errors.Is(err, adapter.ErrProfileNotFound):
+		return degraded(v1alpha1.ReasonProfileNotFound, "profile %q does not exist", obj.Spec.ProfileName), nil
+	case errors.Is(err, adapter.ErrConvertingProfile):
+		// the profile exists but is invalid: its own status reports why it is not ready.
+		return ready(), nil
+	case err != nil:
+		return condition{}, err //nolint:wrapcheck
+	}
+
+	return ready(), nil
+}
+
+func (a *Assignment) checkDefaultIsUnique(ctx context.Context, obj *v1alpha1.Assignment) (condition, error) {
+	if !obj.Spec.IsDefault {
+		return ready(), nil
+	}
+
+	for _, b := range obj.GetBuildarchList() {
+		for _, p := range obj.GetPlatformList() {
+			list, err := a.assignment.ListDefault(ctx, types.IPXESelectors{
+				Buildarch: b.String(),
+				Platform:  p.String(),
+			})
+			if err != nil {
+				return condition{}, err //nolint:wrapcheck
+			}
+
+			if others := otherNames(list, obj.Name); len(others) > 0 {
+				return degraded(v1alpha1.ReasonDefaultConflict,
+					"default assignment for buildarch %q and platform %q conflicts with %s",
+					b.String(), p.String(), others,
+				), nil
+			}
+		}
+	}
+
+	return ready(), nil
+}
+
+func (a *Assignment) checkSelectorsAreUnique(ctx context.Context, obj *v1alpha1.Assignment) (condition, error) {
+	if obj.Spec.IsDefault {
+		return ready(), nil
+	}
+
+	subjects, err := adapter.SubjectSelectors(obj)
+	if errors.Is(err, adapter.ErrInvalidSubjectSelectors) {
+		return degraded(v1alpha1.ReasonInvalidSpec, "%s", err.Error()), nil
+	} else if err != nil {
+		return condition{}, err //nolint:wrapcheck
+	}
+
+	for _, subject := range subjects {
+		for _, b := range obj.GetBuildarchList() {
+			for _, p := range obj.GetPlatformList() {
+				selectors := subject
+				selectors.Buildarch = b.String()
+				selectors.Platform = p.String()
+
+				list, err := a.assignment.ListBySelectors(ctx, selectors)
+				if err != nil {
+					return condition{}, err //nolint:wrapcheck
+				}
+
+				if others := otherNames(list, obj.Name); len(others) > 0 {
+					return degraded(v1alpha1.ReasonSelectorConflict,
+						"subject %s with buildarch %q and platform %q is also selected by %s",
+						subject.Subject(), b.String(), p.String(), others,
+					), nil
+				}
+			}
+		}
+	}
+
+	return ready(), nil
+}
+
+// MapOverlappingAssignments maps an assignment to the other assignments whose selectors overlap with its own, i.e. the
+// assignments selecting one of its subjects, or the other default assignments. Buildarch and platforms are ignored,
+// as enqueuing a few more assignments is cheaper than listing them for each combination.
+func (a *Assignment) MapOverlappingAssignments(ctx context.Context, obj client.Object) []reconcile.Request {
+	assignment, ok := obj.(*v1alpha1.Assignment)
+	if !ok {
+		return nil
+	}
+
+	list := make([]types.Assignment, 0)
+
+	if assignment.Spec.IsDefault {
+		defaults, err := a.assignment.ListDefault(ctx, types.IPXESelectors{})
+		if err != nil {
+			slog.ErrorContext(ctx, "listing default assignments", "error", err.Error())
+			return nil
+		}
+
+		list = defaults
+	} else {
+		// invalid selectors cannot overlap with the ones of other assignments.
+		subjects, _ := adapter.SubjectSelectors(assignment)
+		for _, subject := range subjects {
+			selected, err := a.assignment.ListBySelectors(ctx, subject)
+			if err != nil {
+				slog.ErrorContext(ctx, "listing assignments by selectors", "error", err.Error())
+				return nil
+			}
+
+			list = append(list, selected...)
+		}
+	}
+
+	out := make([]reconcile.Request, 0, len(list))
+	for _, item := range list {
+		req := reconcile.Request{NamespacedName: k8stypes.NamespacedName{Namespace: obj.GetNamespace(), Name: item.Name}}
+		if item.Name != obj.GetName() && !slices.Contains(out, req) {
+			out = append(out, req)
+		}
+	}
+
+	return out
+}
+
+// MapReferencingAssignments maps a profile to the assignments referencing it.
+func (a *Assignment) MapReferencingAssignments(ctx context.Context, obj client.Object) []reconcile.Request {
+	list := new(v1alpha1.AssignmentList)
+	if err := a.client.List(ctx, list, client.MatchingFields{adapter.AssignmentProfileIndex: obj.GetName()}); err != nil {
+		slog.ErrorContext(ctx, "listing assignments", "error", err.Error())
+		return nil
+	}
+
+	out := make([]reconcile.Request, 0, len(list.Items))
+	for i := range list.Items {
+		out = append(out, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&list.Items[i])})
+	}
+
+	return out
+}
+
+// otherNames returns the quoted names of the assignments other than the one named `name`.
+func otherNames(list []types.Assignment, name string) string {
+	out := make([]string, 0, len(list))
+
+	for _, item := range list {
+		if item.Name != name {
+			out = append(out, fmt.Sprintf("assignment %q", item.Name))
+		}
+	}
+
+	slices.Sort(out)
+
+	return strings.Join(out, ", ")
+}
@@ -0,0 +1,316 @@
+//go:build unit
+
+package reconciler_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
+	"github.com/alexandremahdhaoui/ipxer/internal/driver/reconciler"
+	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/mocks/mockadapter"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/mocks/mockclient"
+	"github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	namespace   = "test-namespace"
+	profileName = "test-profile"
+)
+
+func TestAssignment(t *testing.T) {
+	var (
+		ctx context.Context
+		req reconcile.Request
+
+		subjectID uuid.UUID
+		obj       *v1alpha1.Assignment
+		updated   *v1alpha1.Assignment
+
+		cl           *mockclient.MockClient
+		statusWriter *mockclient.MockSubResourceWriter
+		assignment   *mockadapter.MockAssignment
+		profile      *mockadapter.MockProfile
+		r            *reconciler.Assignment
+	)
+
+	setup := func(t *testing.T) func() {
+		t.Helper()
+
+		ctx = context.Background()
+		req = reconcile.Request{NamespacedName: k8stypes.NamespacedName{Namespace: namespace, Name: "test-assignment"}}
+
+		subjectID = uuid.New()
+		obj = &v1alpha1.Assignment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       req.Name,
+				Namespace:  req.Namespace,
+				Generation: 2,
+				Labels: map[string]string{
+					v1alpha1.NewUUIDLabelSelector(subjectID): "",
+					v1alpha1.Arm64BuildarchLabelSelector:     "",
+					v1alpha1.EFIPlatformLabelSelector:        "",
+				},
+			},
+			Spec: v1alpha1.AssignmentSpec{
+				ProfileName:      profileName,
+				SubjectSelectors: v1alpha1.SubjectSelectors{UUIDList: []string{subjectID.String()}},
+			},
+		}
+		updated = nil
+
+		cl = mockclient.NewMockClient(t)
+		statusWriter = mockclient.NewMockSubResourceWriter(t)
+		assignment = mockadapter.NewMockAssignment(t)
+		profile = mockadapter.NewMockProfile(t)
+		r = reconciler.NewAssignment(cl, assignment, profile)
+
+		return func() {
+			t.Helper()
+
+			cl.AssertExpectations(t)
+			statusWriter.AssertExpectations(t)
+			assignment.AssertExpectations(t)
+			profile.AssertExpectations(t)
+		}
+	}
+
+	expectGet := func() {
+		cl.EXPECT().Get(ctx, req.NamespacedName, mock.Anything).
+			RunAndReturn(func(_ context.Context, _ client.ObjectKey, o client.Object, _ ...client.GetOption) error {
+				obj.DeepCopyInto(o.(*v1alpha1.Assignment))
+				return nil
+			}).Once()
+	}
+
+	expectStatusUpdate := func() {
+		cl.EXPECT().Status().Return(statusWriter).Once()
+		statusWriter.EXPECT().Update(ctx, mock.Anything).
+			RunAndReturn(func(_ context.Context, o client.Object, _ ...client.SubResourceUpdateOption) error {
+				updated = o.(*v1alpha1.Assignment)
+				return nil
+			}).Once()
+	}
+
+	expectSelectors := func(result ...types.Assignment) {
+		assignment.EXPECT().ListBySelectors(ctx, types.IPXESelectors{
+			Buildarch: v1alpha1.Arm64.String(),
+			Platform:  v1alpha1.EFI.String(),
+			UUID:      subjectID,
+		}).Return(result, nil).Once()
+	}
+
+	assertReady := func(t *testing.T, reason string) {
+		t.Helper()
+
+		require.NotNil(t, updated)
+
+		ready := meta.FindStatusCondition(updated.Status.Conditions, v1alpha1.ConditionReady)
+		require.NotNil(t, ready)
+		assert.Equal(t, reason == v1alpha1.ReasonReconciled, ready.Status == metav1.ConditionTrue)
+		assert.Equal(t, reason, ready.Reason)
+		assert.Equal(t, obj.Generation, ready.ObservedGeneration)
+
+		degraded := meta.FindStatusCondition(updated.Status.Conditions, v1alpha1.ConditionDegraded)
+		require.NotNil(t, degraded)
+		assert.Equal(t, reason != v1alpha1.ReasonReconciled, degraded.Status == metav1.ConditionTrue)
+		assert.Equal(t, reason, degraded.Reason)
+	}
+
+	t.Run("Ready", func(t *testing.T) {
+		defer setup(t)()
+
+		expectGet()
+		profile.EXPECT().Get(ctx, profileName).Return(types.Profile{}, nil).Once()
+		expectSelectors(types.Assignment{Name: obj.Name, ProfileName: profileName})
+		expectStatusUpdate()
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assertReady(t, v1alpha1.ReasonReconciled)
+	})
+
+	t.Run("Unchanged", func(t *testing.T) {
+		defer setup(t)()
+
+		obj.Status.Conditions = []metav1.Condition{
+			{Type: v1alpha1.ConditionReady, Status: metav1.ConditionTrue, ObservedGeneration: 2, Reason: v1alpha1.ReasonReconciled},
+			{Type: v1alpha1.ConditionDegraded, Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: v1alpha1.ReasonReconciled},
+		}
+
+		expectGet()
+		profile.EXPECT().Get(ctx, profileName).Return(types.Profile{}, nil).Once()
+		expectSelectors(types.Assignment{Name: obj.Name, ProfileName: profileName})
+
+		// the status must not be updated.
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("ProfileNotFound", func(t *testing.T) {
+		defer setup(t)()
+
+		expectGet()
+		profile.EXPECT().Get(ctx, profileName).Return(types.Profile{}, adapter.ErrProfileNotFound).Once()
+		expectStatusUpdate()
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assertReady(t, v1alpha1.ReasonProfileNotFound)
+	})
+
+	t.Run("SelectorConflict", func(t *testing.T) {
+		defer setup(t)()
+
+		expectGet()
+		profile.EXPECT().Get(ctx, profileName).Return(types.Profile{}, nil).Once()
+		expectSelectors(
+			types.Assignment{Name: obj.Name, ProfileName: profileName},
+			types.Assignment{Name: "other", ProfileName: profileName},
+		)
+		expectStatusUpdate()
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assertReady(t, v1alpha1.ReasonSelectorConflict)
+		assert.Contains(t, updated.Status.Conditions[0].Message, `assignment "other"`)
+	})
+
+	t.Run("MACConflict", func(t *testing.T) {
+		defer setup(t)()
+
+		obj.Spec.SubjectSelectors.UUIDList = nil
+		obj.Spec.SubjectSelectors.MACList = []string{"AA:BB:CC:DD:EE:FF"}
+
+		expectGet()
+		profile.EXPECT().Get(ctx, profileName).Return(types.Profile{}, nil).Once()
+		assignment.EXPECT().ListBySelectors(ctx, types.IPXESelectors{
+			Buildarch: v1alpha1.Arm64.String(),
+			Platform:  v1alpha1.EFI.String(),
+			MAC:       net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff},
+		}).Return([]types.Assignment{{Name: "other"}}, nil).Once()
+		expectStatusUpdate()
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assertReady(t, v1alpha1.ReasonSelectorConflict)
+		assert.Contains(t, updated.Status.Conditions[0].Message, `subject mac "aa:bb:cc:dd:ee:ff"`)
+	})
+
+	t.Run("InvalidSpec", func(t *testing.T) {
+		defer setup(t)()
+
+		obj.Spec.SubjectSelectors.MACList = []string{"not-a-mac"}
+
+		expectGet()
+		profile.EXPECT().Get(ctx, profileName).Return(types.Profile{}, nil).Once()
+		expectStatusUpdate()
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assertReady(t, v1alpha1.ReasonInvalidSpec)
+	})
+
+	t.Run("DefaultConflict", func(t *testing.T) {
+		defer setup(t)()
+
+		obj.Spec.IsDefault = true
+		delete(obj.Labels, v1alpha1.NewUUIDLabelSelector(subjectID))
+
+		expectGet()
+		profile.EXPECT().Get(ctx, profileName).Return(types.Profile{}, nil).Once()
+		assignment.EXPECT().ListDefault(ctx, types.IPXESelectors{
+			Buildarch: v1alpha1.Arm64.String(),
+			Platform:  v1alpha1.EFI.String(),
+		}).Return([]types.Assignment{{Name: "other"}, {Name: obj.Name}}, nil).Once()
+		expectStatusUpdate()
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assertReady(t, v1alpha1.ReasonDefaultConflict)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("ProfileError", func(t *testing.T) {
+			defer setup(t)()
+
+			expectGet()
+			profile.EXPECT().Get(ctx, profileName).Return(types.Profile{}, assert.AnError).Once()
+
+			_, err := r.Reconcile(ctx, req)
+			assert.ErrorIs(t, err, assert.AnError)
+		})
+
+		t.Run("ObjectNotFound", func(t *testing.T) {
+			defer setup(t)()
+
+			cl.EXPECT().Get(ctx, req.NamespacedName, mock.Anything).
+				Return(apierrors.NewNotFound(schema.GroupResource{}, req.Name)).Once()
+
+			_, err := r.Reconcile(ctx, req)
+			assert.NoError(t, err)
+		})
+	})
+
+	t.Run("MapOverlappingAssignments", func(t *testing.T) {
+		request := func(name string) reconcile.Request {
+			return reconcile.Request{NamespacedName: k8stypes.NamespacedName{Namespace: namespace, Name: name}}
+		}
+
+		t.Run("Selectors", func(t *testing.T) {
+			defer setup(t)()
+
+			// buildarch and platforms are ignored.
+			assignment.EXPECT().ListBySelectors(ctx, types.IPXESelectors{UUID: subjectID}).
+				Return([]types.Assignment{{Name: req.Name}, {Name: "other"}}, nil).Once()
+
+			actual := r.MapOverlappingAssignments(ctx, obj)
+			assert.Equal(t, []reconcile.Request{request("other")}, actual)
+		})
+
+		t.Run("Default", func(t *testing.T) {
+			defer setup(t)()
+
+			obj.Spec.IsDefault = true
+			assignment.EXPECT().ListDefault(ctx, types.IPXESelectors{}).
+				Return([]types.Assignment{{Name: "other-default"}, {Name: req.Name}}, nil).Once()
+
+			actual := r.MapOverlappingAssignments(ctx, obj)
+			assert.Equal(t, []reconcile.Request{request("other-default")}, actual)
+		})
+
+		t.Run("InvalidSpec", func(t *testing.T) {
+			defer setup(t)()
+
+			obj.Spec.SubjectSelectors.UUIDList = []string{"not-a-uuid"}
+
+			assert.Empty(t, r.MapOverlappingAssignments(ctx, obj))
+		})
+	})
+
+	t.Run("MapReferencingAssignments", func(t *testing.T) {
+		defer setup(t)()
+
+		cl.EXPECT().List(ctx, mock.Anything, client.MatchingFields{adapter.AssignmentProfileIndex: profileName}).
+			RunAndReturn(func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+				list.(*v1alpha1.AssignmentList).Items = []v1alpha1.Assignment{*obj}
+				return nil
+			}).Once()
+
+		actual := r.MapReferencingAssignments(ctx, &v1alpha1.Profile{ObjectMeta: metav1.ObjectMeta{Name: profileName}})
+		assert.Equal(t, []reconcile.Request{req}, actual)
+	})
+}
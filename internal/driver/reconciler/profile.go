@@ -0,0 +1,238 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
+	"github.com/alexandremahdhaoui/ipxer/internal/controller"
+	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
+	"k8s.io/client-go/util/jsonpath"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var (
+	_ reconcile.Reconciler = &Profile{}
+
+	errReconcilingProfile = errors.New("reconciling profile")
+)
+
+// NewProfile returns a Profile reconciler. As the sources of additional content are not watched, degraded profiles and
+// profiles with git contents, whose refs may move, are reconciled again after requeueAfter.
+func NewProfile(
+	c client.Client,
+	profile adapter.Profile,
+	objectRefResolver adapter.ObjectRefResolver,
+	gitResolver adapter.GitResolver,
+	requeueAfter time.Duration,
+) *Profile {
+	return &Profile{
+		client:            c,
+		profile:           profile,
+		objectRefResolver: objectRefResolver,
+		gitResolver:       gitResolver,
+		requeueAfter:      requeueAfter,
+	}
+}
+
+// Profile reconciles the status of Profiles. A Profile is Ready if its template parses and the source of every
+// additional content can be resolved. The commits git contents resolve to are reported in the status.
+type Profile struct {
+	client            client.Client
+	profile           adapter.Profile
+	objectRefResolver adapter.ObjectRefResolver
+	gitResolver       adapter.GitResolver
+	requeueAfter      time.Duration
+}
+
+func (p *Profile) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.Profile{}).
+		Complete(p) //nolint:wrapcheck
+}
+
+func (p *Profile) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	obj := new(v1alpha1.Profile)
+	if err := p.client.Get(ctx, req.NamespacedName, obj); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err) //nolint:wrapcheck
+	}
+
+	c, revisions, err := p.check(ctx, obj)
+	if err != nil {
+		return reconcile.Result{}, errors.Join(err, errReconcilingProfile)
+	}
+
+	result := reconcile.Result{}
+	if !c.isReady() || len(revisions) > 0 {
+		result.RequeueAfter = p.requeueAfter
+	}
+
+	revisionsChanged := !maps.Equal(obj.Status.ResolvedRevisions, revisions)
+	obj.Status.ResolvedRevisions = revisions
+
+	if !setConditions(&obj.Status.Conditions, obj.Generation, c) && !revisionsChanged {
+		return result, nil
+	}
+
+	if err := p.client.Status().Update(ctx, obj); err != nil {
+		return reconcile.Result{}, errors.Join(err, errUpdatingStatus, errReconcilingProfile)
+	}
+
+	return result, nil
+}
+
+// check returns the condition of the profile and the commits its git contents resolve to, keyed by content name. An
+// error is returned if the condition cannot be determined.
+func (p *Profile) check(ctx context.Context, obj *v1alpha1.Profile) (condition, map[string]string, error) {
+	profile, err := p.profile.Get(ctx, obj.Name)
+	if errors.Is(err, adapter.ErrConvertingProfile) {
+		return degraded(v1alpha1.ReasonInvalidSpec, "%s", err.Error()), nil, nil
+	} else if err != nil {
+		return condition{}, nil, err //nolint:wrapcheck
+	}
+
+	// check contents in order for the message to be deterministic.
+	names := make([]string, 0, len(profile.AdditionalContent))
+	for name := range profile.AdditionalContent {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	if err := controller.ValidateIPXETemplate(profile.IPXETemplate, names); err != nil {
+		return degraded(v1alpha1.ReasonTemplateInvalid, "%s", err.Error()), nil, nil
+	}
+
+	var revisions map[string]string
+
+	for _, name := range names {
+		revision, err := p.checkContent(ctx, profile.AdditionalContent[name])
+		if err != nil {
+			return degraded(v1alpha1.ReasonContentUnresolvable,
+				"additional content %q cannot be resolved: %s", name, err.Error(),
+			), revisions, nil
+		}
+
+		if revision != "" {
+			if revisions == nil {
+				revisions = make(map[string]string)
+			}
+
+			revisions[name] = revision
+		}
+	}
+
+	return ready(), revisions, nil
+}
+
+// checkContent ensures the source of the content and the credentials of its webhooks can be resolved, and returns the
+// commit git contents resolve to. Webhooks are not called, as their response depends on the attributes of the booting
+// machine.
+func (p *Profile) checkContent(ctx context.Context, content types.Content) (string, error) {
+	var revision string
+
+	switch content.ResolverKind {
+	case types.InlineResolverKind:
+	case types.ObjectRefResolverKind:
+		if _, err := p.objectRefResolver.Resolve(ctx, content, types.Attributes{}); err != nil {
+			return "", err //nolint:wrapcheck
+		}
+	case types.WebhookResolverKind:
+		if err := p.checkWebhookConfig(ctx, content.WebhookConfig); err != nil {
+			return "", err
+		}
+	case types.HTTPResolverKind:
+		if cfg := content.HTTPConfig; cfg != nil {
+			if err := p.checkCredentials(ctx, cfg.MTLSObjectRef, cfg.BasicAuthObjectRef); err != nil {
+				return "", err
+			}
+		}
+	case types.S3ResolverKind:
+		if cfg := content.S3Config; cfg != nil && cfg.CredentialsRef != nil {
+			ref := cfg.CredentialsRef
+			if _, err := p.objectRefResolver.ResolvePaths(ctx, []*jsonpath.JSONPath{
+				ref.AccessKeyIDJSONPath,
+				ref.SecretAccessKeyJSONPath,
+			}, ref.ObjectRef); err != nil {
+				return "", fmt.Errorf("resolving s3 credentials ref: %w", err)
+			}
+		}
+	case types.OCIResolverKind:
+		if cfg := content.OCIConfig; cfg != nil {
+			if err := p.checkCredentials(ctx, nil, cfg.CredentialsRef); err != nil {
+				return "", err
+			}
+		}
+	case types.GitResolverKind:
+		var err error
+		if revision, err = p.gitResolver.Revision(ctx, content); err != nil {
+			return "", err //nolint:wrapcheck
+		}
+	}
+
+	for i, transformer := range content.PostTransformers {
+		if transformer.Kind != types.WebhookTransformerKind {
+			continue
+		}
+
+		if err := p.checkWebhookConfig(ctx, transformer.Webhook); err != nil {
+			return "", fmt.Errorf("post transformation %d: %w", i, err)
+		}
+	}
+
+	return revision, nil
+}
+
+func (p *Profile) checkWebhookConfig(ctx context.Context, cfg *types.WebhookConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if err := p.checkCredentials(ctx, cfg.MTLSObjectRef, cfg.BasicAuthObjectRef); err != nil {
+		return err
+	}
+
+	if ref := cfg.BearerTokenObjectRef; ref != nil {
+		paths := []*jsonpath.JSONPath{ref.TokenJSONPath}
+		if _, err := p.objectRefResolver.ResolvePaths(ctx, paths, ref.ObjectRef); err != nil {
+			return fmt.Errorf("resolving bearer token object ref: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// checkCredentials ensures the mTLS and basic auth credentials can be resolved, if any.
+func (p *Profile) checkCredentials(
+	ctx context.Context,
+	mtlsRef *types.MTLSObjectRef,
+	basicAuthRef *types.BasicAuthObjectRef,
+) error {
+	if ref := mtlsRef; ref != nil {
+		if _, err := p.objectRefResolver.ResolvePaths(ctx, []*jsonpath.JSONPath{
+			ref.ClientKeyJSONPath,
+			ref.ClientCertJSONPath,
+			ref.CaBundleJSONPath,
+		}, ref.ObjectRef); err != nil {
+			return fmt.Errorf("resolving mtls object ref: %w", err)
+		}
+	}
+
+	if ref := basicAuthRef; ref != nil {
+		if _, err := p.objectRefResolver.ResolvePaths(ctx, []*jsonpath.JSONPath{
+			ref.UsernameJSONPath,
+			ref.PasswordJSONPath,
+		}, ref.ObjectRef); err != nil {
+			return fmt.Errorf("resolving basic auth object ref: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+//go:build unit
+
+package reconciler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
+	"github.com/alexandremahdhaoui/ipxer/internal/driver/reconciler"
+	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/mocks/mockadapter"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/mocks/mockclient"
+	"github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestProfile(t *testing.T) {
+	const requeueAfter = time.Minute
+
+	var (
+		ctx context.Context
+		req reconcile.Request
+
+		obj     *v1alpha1.Profile
+		updated *v1alpha1.Profile
+
+		cl                *mockclient.MockClient
+		statusWriter      *mockclient.MockSubResourceWriter
+		profile           *mockadapter.MockProfile
+		objectRefResolver *mockadapter.MockObjectRefResolver
+		gitResolver       *mockadapter.MockGitResolver
+		r                 *reconciler.Profile
+	)
+
+	setup := func(t *testing.T) func() {
+		t.Helper()
+
+		ctx = context.Background()
+		req = reconcile.Request{NamespacedName: k8stypes.NamespacedName{Namespace: namespace, Name: profileName}}
+
+		obj = &v1alpha1.Profile{
+			ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace, Generation: 1},
+		}
+		updated = nil
+
+		cl = mockclient.NewMockClient(t)
+		statusWriter = mockclient.NewMockSubResourceWriter(t)
+		profile = mockadapter.NewMockProfile(t)
+		objectRefResolver = mockadapter.NewMockObjectRefResolver(t)
+		gitResolver = mockadapter.NewMockGitResolver(t)
+		r = reconciler.NewProfile(cl, profile, objectRefResolver, gitResolver, requeueAfter)
+
+		cl.EXPECT().Get(ctx, req.NamespacedName, mock.Anything).
+			RunAndReturn(func(_ context.Context, _ client.ObjectKey, o client.Object, _ ...client.GetOption) error {
+				obj.DeepCopyInto(o.(*v1alpha1.Profile))
+				return nil
+			}).Once()
+
+		cl.EXPECT().Status().Return(statusWriter).Once()
+		statusWriter.EXPECT().Update(ctx, mock.Anything).
+			RunAndReturn(func(_ context.Context, o client.Object, _ ...client.SubResourceUpdateOption) error {
+				updated = o.(*v1alpha1.Profile)
+				return nil
+			}).Once()
+
+		return func() {
+			t.Helper()
+
+			cl.AssertExpectations(t)
+			statusWriter.AssertExpectations(t)
+			profile.AssertExpectations(t)
+			objectRefResolver.AssertExpectations(t)
+			gitResolver.AssertExpectations(t)
+		}
+	}
+
+	assertReason := func(t *testing.T, reason string) {
+		t.Helper()
+
+		require.NotNil(t, updated)
+
+		ready := meta.FindStatusCondition(updated.Status.Conditions, v1alpha1.ConditionReady)
+		require.NotNil(t, ready)
+		assert.Equal(t, reason, ready.Reason)
+		assert.Equal(t, reason == v1alpha1.ReasonReconciled, ready.Status == metav1.ConditionTrue)
+		assert.Equal(t, reason != v1alpha1.ReasonReconciled,
+			meta.IsStatusConditionTrue(updated.Status.Conditions, v1alpha1.ConditionDegraded))
+	}
+
+	t.Run("Ready", func(t *testing.T) {
+		defer setup(t)()
+
+		content := types.Content{Name: "ref", ResolverKind: types.ObjectRefResolverKind, ObjectRef: &types.ObjectRef{}}
+
+		profile.EXPECT().Get(ctx, profileName).Return(types.Profile{
+			IPXETemplate: "#!ipxe\necho {{ .inline }} {{ .ref }}",
+			AdditionalContent: map[string]types.Content{
+				"inline": {Name: "inline", ResolverKind: types.InlineResolverKind, Inline: "hello"},
+				"ref":    content,
+			},
+		}, nil).Once()
+
+		objectRefResolver.EXPECT().Resolve(ctx, content, types.Attributes{}).Return([]byte("world"), nil).Once()
+
+		result, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Zero(t, result.RequeueAfter)
+		assertReason(t, v1alpha1.ReasonReconciled)
+	})
+
+	t.Run("GitRevision", func(t *testing.T) {
+		defer setup(t)()
+
+		const commit = "f7f5cacce5a1561bda923ea586c0368ae6d6bc3d"
+
+		content := types.Content{
+			Name:         "git",
+			ResolverKind: types.GitResolverKind,
+			GitConfig:    &types.GitConfig{URL: "https://example.com/configs.git", Path: "worker.bu"},
+		}
+
+		profile.EXPECT().Get(ctx, profileName).Return(types.Profile{
+			IPXETemplate:      "#!ipxe\necho {{ .git }}",
+			AdditionalContent: map[string]types.Content{"git": content},
+		}, nil).Once()
+
+		gitResolver.EXPECT().Revision(ctx, content).Return(commit, nil).Once()
+
+		result, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assertReason(t, v1alpha1.ReasonReconciled)
+		assert.Equal(t, map[string]string{"git": commit}, updated.Status.ResolvedRevisions)
+
+		// ready profiles with git contents are requeued, as their refs may move.
+		assert.Equal(t, requeueAfter, result.RequeueAfter)
+	})
+
+	t.Run("InvalidSpec", func(t *testing.T) {
+		defer setup(t)()
+
+		profile.EXPECT().Get(ctx, profileName).Return(types.Profile{}, adapter.ErrConvertingProfile).Once()
+
+		result, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, requeueAfter, result.RequeueAfter)
+		assertReason(t, v1alpha1.ReasonInvalidSpec)
+	})
+
+	t.Run("TemplateInvalid", func(t *testing.T) {
+		defer setup(t)()
+
+		profile.EXPECT().Get(ctx, profileName).Return(types.Profile{IPXETemplate: "#!ipxe\n{{ .unclosed"}, nil).Once()
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assertReason(t, v1alpha1.ReasonTemplateInvalid)
+	})
+
+	t.Run("ContentUnresolvable", func(t *testing.T) {
+		defer setup(t)()
+
+		ref := types.ObjectRef{Name: "secret"}
+
+		profile.EXPECT().Get(ctx, profileName).Return(types.Profile{
+			IPXETemplate: "#!ipxe",
+			AdditionalContent: map[string]types.Content{
+				"webhook": {
+					Name:         "webhook",
+					ResolverKind: types.WebhookResolverKind,
+					WebhookConfig: &types.WebhookConfig{
+						URL:                "https://example.com",
+						BasicAuthObjectRef: &types.BasicAuthObjectRef{ObjectRef: ref},
+					},
+				},
+			},
+		}, nil).Once()
+
+		objectRefResolver.EXPECT().ResolvePaths(ctx, mock.Anything, ref).Return(nil, assert.AnError).Once()
+
+		result, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, requeueAfter, result.RequeueAfter)
+		assertReason(t, v1alpha1.ReasonContentUnresolvable)
+		assert.Contains(t, updated.Status.Conditions[0].Message, `additional content "webhook"`)
+		assert.NotContains(t, updated.Status.Conditions[0].Message, "\n")
+	})
+}
@@ -0,0 +1,54 @@
+package reconciler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// condition is the outcome of the checks performed by a reconciler. A condition with the reason
+// v1alpha1.ReasonReconciled is Ready, any other reason is Degraded.
+type condition struct {
+	reason  string
+	message string
+}
+
+func ready() condition {
+	return condition{reason: v1alpha1.ReasonReconciled}
+}
+
+// degraded returns a Degraded condition. Joined errors are flattened into a single line message.
+func degraded(reason, format string, args ...any) condition {
+	return condition{reason: reason, message: strings.ReplaceAll(fmt.Sprintf(format, args...), "\n", ": ")}
+}
+
+func (c condition) isReady() bool {
+	return c.reason == v1alpha1.ReasonReconciled
+}
+
+// setConditions sets the Ready and Degraded conditions, and reports whether the conditions changed.
+func setConditions(conditions *[]metav1.Condition, generation int64, c condition) bool {
+	readyStatus, degradedStatus := metav1.ConditionFalse, metav1.ConditionTrue
+	if c.isReady() {
+		readyStatus, degradedStatus = metav1.ConditionTrue, metav1.ConditionFalse
+	}
+
+	changed := meta.SetStatusCondition(conditions, metav1.Condition{ //nolint:exhaustruct
+		Type:               v1alpha1.ConditionReady,
+		Status:             readyStatus,
+		ObservedGeneration: generation,
+		Reason:             c.reason,
+		Message:            c.message,
+	})
+
+	return meta.SetStatusCondition(conditions, metav1.Condition{ //nolint:exhaustruct
+		Type:               v1alpha1.ConditionDegraded,
+		Status:             degradedStatus,
+		ObservedGeneration: generation,
+		Reason:             c.reason,
+		Message:            c.message,
+	}) || changed
+}
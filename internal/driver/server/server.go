@@ -3,17 +3,32 @@ package server
 import (
 	"context"
 	"errors"
+	"net"
+	"net/http"
+	"net/url"
 
 	"github.com/alexandremahdhaoui/ipxer/internal/controller"
 	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/alexandremahdhaoui/ipxer/pkg/constants"
 	"github.com/alexandremahdhaoui/ipxer/pkg/generated/ipxerserver"
+	"github.com/google/uuid"
+	"k8s.io/utils/ptr"
 )
 
 var (
 	ErrGetConfigByID      = errors.New("getting config by id")
 	ErrGetIPXEBySelectors = errors.New("getting ipxe by labels")
+	ErrParsingMAC         = errors.New("parsing mac address")
 )
 
+// QueryMiddleware stores the query parameters of requests into their context, as the iPXE params forwarded by the
+// bootstrap script are configurable, thus not declared in the API specification.
+func QueryMiddleware(f ipxerserver.StrictHandlerFunc, _ string) ipxerserver.StrictHandlerFunc {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request any) (any, error) {
+		return f(context.WithValue(ctx, constants.QueryContextKey, r.URL.Query()), w, r, request)
+	}
+}
+
 func New(ipxe controller.IPXE, config controller.Content) ipxerserver.StrictServerInterface {
 	return &server{
 		ipxe:   ipxe,
@@ -42,13 +57,39 @@ func (s *server) GetContentByID(
 ) (ipxerserver.GetContentByIDResponseObject, error) {
 	// TODO: instantiate child context with correlation ID.
 
-	attributes := types.IPXESelectors{
-		Buildarch: string(request.Params.Buildarch),
-		UUID:      request.Params.Uuid,
+	attributes, err := newSelectors(
+		string(request.Params.Buildarch),
+		string(ptr.Deref(request.Params.Platform, "")),
+		request.Params.Uuid,
+		ptr.Deref(request.Params.Mac, ""),
+		ptr.Deref(request.Params.Serial, ""),
+		ptr.Deref(request.Params.Asset, ""),
+		ptr.Deref(request.Params.Hostname, ""),
+	)
+	if err != nil {
+		return ipxerserver.GetContentByID400JSONResponse{
+			N400JSONResponse: ipxerserver.N400JSONResponse{
+				Code:    400,
+				Message: errors.Join(err, ErrGetConfigByID).Error(),
+			},
+		}, nil
+	}
+
+	// parse the iPXE params forwarded by the URL of the content.
+	query, _ := ctx.Value(constants.QueryContextKey).(url.Values)
+
+	params, err := types.ParseIpxeParams(query)
+	if err != nil {
+		return ipxerserver.GetContentByID400JSONResponse{
+			N400JSONResponse: ipxerserver.N400JSONResponse{
+				Code:    400,
+				Message: errors.Join(err, ErrGetConfigByID).Error(),
+			},
+		}, nil
 	}
 
 	// call controller
-	b, err := s.config.GetByID(ctx, request.ContentID, attributes)
+	b, err := s.config.GetByID(ctx, request.ContentID, attributes, params)
 	if err != nil {
 		return ipxerserver.GetContentByID500JSONResponse{
 			N500JSONResponse: ipxerserver.N500JSONResponse{
@@ -69,13 +110,39 @@ func (s *server) GetIPXEBySelectors(
 
 	// convert into type
 	// TODO: use params instead of converting the echo context?
-	selectors := types.IPXESelectors{
-		Buildarch: string(request.Params.Buildarch),
-		UUID:      request.Params.Uuid,
+	selectors, err := newSelectors(
+		string(request.Params.Buildarch),
+		string(ptr.Deref(request.Params.Platform, "")),
+		request.Params.Uuid,
+		ptr.Deref(request.Params.Mac, ""),
+		ptr.Deref(request.Params.Serial, ""),
+		ptr.Deref(request.Params.Asset, ""),
+		ptr.Deref(request.Params.Hostname, ""),
+	)
+	if err != nil {
+		return ipxerserver.GetIPXEBySelectors400JSONResponse{
+			N400JSONResponse: ipxerserver.N400JSONResponse{
+				Code:    400,
+				Message: errors.Join(err, ErrGetIPXEBySelectors).Error(),
+			},
+		}, nil
+	}
+
+	// parse the iPXE params forwarded by the bootstrap script.
+	query, _ := ctx.Value(constants.QueryContextKey).(url.Values)
+
+	params, err := types.ParseIpxeParams(query)
+	if err != nil {
+		return ipxerserver.GetIPXEBySelectors400JSONResponse{
+			N400JSONResponse: ipxerserver.N400JSONResponse{
+				Code:    400,
+				Message: errors.Join(err, ErrGetIPXEBySelectors).Error(),
+			},
+		}, nil
 	}
 
 	// call controller
-	b, err := s.ipxe.FindProfileAndRender(ctx, selectors)
+	b, err := s.ipxe.FindProfileAndRender(ctx, selectors, params)
 	if err != nil {
 		return ipxerserver.GetIPXEBySelectors500JSONResponse{
 			N500JSONResponse: ipxerserver.N500JSONResponse{
@@ -87,3 +154,31 @@ func (s *server) GetIPXEBySelectors(
 
 	return ipxerserver.GetIPXEBySelectors200TextResponse(b), nil
 }
+
+// newSelectors converts the selectors of a request. iPXE expands unset settings to empty strings, thus an empty MAC
+// address is ignored.
+func newSelectors(
+	buildarch, platform string,
+	id uuid.UUID,
+	mac, serial, asset, hostname string,
+) (types.IPXESelectors, error) {
+	out := types.IPXESelectors{
+		Buildarch: buildarch,
+		Platform:  platform,
+		UUID:      id,
+		Serial:    serial,
+		Asset:     asset,
+		Hostname:  hostname,
+	}
+
+	if mac != "" {
+		hwAddr, err := net.ParseMAC(mac)
+		if err != nil {
+			return types.IPXESelectors{}, errors.Join(err, ErrParsingMAC)
+		}
+
+		out.MAC = hwAddr
+	}
+
+	return out, nil
+}
@@ -0,0 +1,182 @@
+package tftp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RFC 1350 opcodes and the RFC 2347 OACK opcode.
+const (
+	opRRQ   uint16 = 1
+	opWRQ   uint16 = 2
+	opDATA  uint16 = 3
+	opACK   uint16 = 4
+	opERROR uint16 = 5
+	opOACK  uint16 = 6
+)
+
+// RFC 1350 & RFC 2347 error codes.
+const (
+	errCodeNotDefined        uint16 = 0
+	errCodeFileNotFound      uint16 = 1
+	errCodeAccessViolation   uint16 = 2
+	errCodeIllegalOperation  uint16 = 4
+	errCodeUnknownTransferID uint16 = 5
+)
+
+// Options defined by RFC 2348 (blksize), RFC 2349 (timeout & tsize) and RFC 7440 (windowsize).
+const (
+	optBlockSize  = "blksize"
+	optTimeout    = "timeout"
+	optTSize      = "tsize"
+	optWindowSize = "windowsize"
+
+	modeOctet    = "octet"
+	modeNetASCII = "netascii"
+
+	defaultBlockSize  = 512
+	minBlockSize      = 8
+	maxBlockSize      = 65464
+	minWindowSize     = 1
+	maxWindowSize     = 65535
+	minTimeoutSeconds = 1
+	maxTimeoutSeconds = 255
+
+	// maxPacketSize is large enough to hold any packet: a 4 bytes header followed by at most maxBlockSize bytes.
+	maxPacketSize = maxBlockSize + 4
+)
+
+var (
+	errPacketTooShort  = errors.New("tftp packet too short")
+	errPacketMalformed = errors.New("malformed tftp packet")
+)
+
+// ---------------------------------------------------- REQUEST ----------------------------------------------------- //
+
+type request struct {
+	opcode   uint16
+	filename string
+	mode     string
+	// options holds the lower-cased options requested by the client in the order they were received.
+	options     map[string]string
+	optionOrder []string
+}
+
+func parseRequest(b []byte) (request, error) {
+	if len(b) < 2 {
+		return request{}, errPacketTooShort
+	}
+
+	req := request{
+		opcode:  binary.BigEndian.Uint16(b[:2]),
+		options: make(map[string]string),
+	}
+
+	if req.opcode != opRRQ && req.opcode != opWRQ {
+		return req, nil
+	}
+
+	fields := bytes.Split(bytes.TrimSuffix(b[2:], []byte{0}), []byte{0})
+	if len(fields) < 2 || len(fields)%2 != 0 {
+		return request{}, errors.Join(fmt.Errorf("got %d fields", len(fields)), errPacketMalformed)
+	}
+
+	req.filename = string(fields[0])
+	req.mode = strings.ToLower(string(fields[1]))
+
+	for i := 2; i < len(fields); i += 2 {
+		key := strings.ToLower(string(fields[i]))
+		if _, ok := req.options[key]; !ok {
+			req.optionOrder = append(req.optionOrder, key)
+		}
+
+		req.options[key] = string(fields[i+1])
+	}
+
+	return req, nil
+}
+
+// ---------------------------------------------------- PACKETS ----------------------------------------------------- //
+
+func newDataPacket(block uint16, data []byte) []byte {
+	out := make([]byte, 4, 4+len(data))
+	binary.BigEndian.PutUint16(out[0:2], opDATA)
+	binary.BigEndian.PutUint16(out[2:4], block)
+
+	return append(out, data...)
+}
+
+func newErrorPacket(code uint16, msg string) []byte {
+	out := make([]byte, 4, 5+len(msg))
+	binary.BigEndian.PutUint16(out[0:2], opERROR)
+	binary.BigEndian.PutUint16(out[2:4], code)
+	out = append(out, msg...)
+
+	return append(out, 0)
+}
+
+func newOACKPacket(keys []string, options map[string]string) []byte {
+	out := make([]byte, 2)
+	binary.BigEndian.PutUint16(out[0:2], opOACK)
+
+	for _, k := range keys {
+		out = append(out, k...)
+		out = append(out, 0)
+		out = append(out, options[k]...)
+		out = append(out, 0)
+	}
+
+	return out
+}
+
+// parseReply parses an ACK or an ERROR packet sent by a client during a transfer.
+func parseReply(b []byte) (opcode uint16, value uint16, msg string, err error) {
+	if len(b) < 4 {
+		return 0, 0, "", errPacketTooShort
+	}
+
+	opcode = binary.BigEndian.Uint16(b[0:2])
+	value = binary.BigEndian.Uint16(b[2:4])
+
+	if opcode == opERROR {
+		msg = string(bytes.TrimRight(b[4:], "\x00"))
+	}
+
+	return opcode, value, msg, nil
+}
+
+// --------------------------------------------------- NEGOTIATION -------------------------------------------------- //
+
+// parseIntOption parses an option value and reports whether it lies in [lower; upper].
+func parseIntOption(value string, lower, upper int) (int, bool) {
+	i, err := strconv.Atoi(value)
+	if err != nil || i < lower {
+		return 0, false
+	}
+
+	return min(i, upper), true
+}
+
+// ---------------------------------------------------- NETASCII ---------------------------------------------------- //
+
+// toNetASCII converts data to netascii (RFC 1350, RFC 764): LF is sent as CR LF and CR as CR NUL.
+func toNetASCII(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+
+	for _, c := range data {
+		switch c {
+		case '\n':
+			out = append(out, '\r', '\n')
+		case '\r':
+			out = append(out, '\r', 0)
+		default:
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
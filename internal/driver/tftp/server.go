@@ -0,0 +1,450 @@
+package tftp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/controller"
+)
+
+const (
+	DefaultTimeout = 3 * time.Second
+	DefaultRetries = 5
+)
+
+var (
+	// ErrServerClosed is returned by the Server's Serve and ListenAndServe methods after a call to Shutdown.
+	ErrServerClosed = errors.New("tftp: server closed")
+
+	errTransferAborted  = errors.New("transfer aborted by client")
+	errTransferTimedOut = errors.New("transfer timed out")
+)
+
+// Server is a read-only TFTP server (RFC 1350) serving iPXE bootloaders. It supports the blksize, tsize, timeout
+// (RFC 2347, RFC 2348, RFC 2349) and windowsize (RFC 7440) options.
+type Server struct {
+	// Addr optionally specifies the UDP address for the server to listen on, e.g. ":69".
+	Addr string
+	// Timeout is the default duration to wait for an acknowledgement before retransmitting. Clients may override it
+	// with the timeout option.
+	Timeout time.Duration
+	// Retries is the number of retransmissions before a transfer is aborted.
+	Retries int
+	// MaxWindowSize caps the windowsize a client may negotiate.
+	MaxWindowSize int
+
+	bootloader controller.Bootloader
+
+	mu     sync.Mutex
+	conn   net.PacketConn
+	closed bool
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New(addr string, bootloader controller.Bootloader) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Server{
+		Addr:          addr,
+		Timeout:       DefaultTimeout,
+		Retries:       DefaultRetries,
+		MaxWindowSize: 64,
+
+		bootloader: bootloader,
+
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// ---------------------------------------------------- LIFECYCLE --------------------------------------------------- //
+
+func (s *Server) ListenAndServe() error {
+	conn, err := net.ListenPacket("udp", s.Addr)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return s.Serve(conn)
+}
+
+// Serve accepts read requests on conn and spawns a goroutine with its own transfer ID (i.e. UDP port) for each of them.
+func (s *Server) Serve(conn net.PacketConn) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+
+		return ErrServerClosed
+	}
+
+	s.conn = conn
+	s.mu.Unlock()
+
+	buf := make([]byte, maxPacketSize)
+
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+
+			return err //nolint:wrapcheck
+		}
+
+		req := bytes.Clone(buf[:n])
+
+		s.wg.Add(1)
+
+		go func() {
+			defer s.wg.Done()
+
+			s.handle(req, addr)
+		}()
+	}
+}
+
+// Shutdown stops accepting new requests, aborts ongoing transfers and waits for them to return or for ctx to be done.
+// Subsequent calls only wait for the transfers to return.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	var err error
+	if s.conn != nil && !s.closed {
+		err = s.conn.Close()
+	}
+
+	s.closed = true
+	s.cancel()
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Ready reports whether the server is serving requests, i.e. it is bound and has not been shut down.
+func (s *Server) Ready() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.conn != nil && !s.closed
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closed
+}
+
+// ---------------------------------------------------- HANDLER ----------------------------------------------------- //
+
+func (s *Server) handle(b []byte, addr net.Addr) {
+	// Each transfer is performed on a new socket, i.e. with a new transfer ID.
+	conn, err := net.ListenPacket("udp", transferAddr(s.Addr))
+	if err != nil {
+		slog.ErrorContext(s.ctx, "❌ opening tftp transfer socket", "error", err, "remote", addr.String())
+		return
+	}
+
+	defer conn.Close()
+
+	req, err := parseRequest(b)
+	if err != nil {
+		_, _ = conn.WriteTo(newErrorPacket(errCodeIllegalOperation, err.Error()), addr)
+		return
+	}
+
+	switch {
+	case req.opcode == opWRQ:
+		_, _ = conn.WriteTo(newErrorPacket(errCodeAccessViolation, "server is read-only"), addr)
+		return
+	case req.opcode != opRRQ:
+		_, _ = conn.WriteTo(newErrorPacket(errCodeIllegalOperation, "expected a read request"), addr)
+		return
+	case req.mode != modeOctet && req.mode != modeNetASCII:
+		_, _ = conn.WriteTo(newErrorPacket(errCodeIllegalOperation, "unsupported mode "+req.mode), addr)
+		return
+	}
+
+	data, err := s.bootloader.Get(s.ctx, req.filename)
+	if errors.Is(err, controller.ErrBootloaderNotFound) {
+		_, _ = conn.WriteTo(newErrorPacket(errCodeFileNotFound, "file not found"), addr)
+		return
+	} else if err != nil {
+		slog.ErrorContext(s.ctx, "❌ getting bootloader", "error", err, "filename", req.filename)
+		_, _ = conn.WriteTo(newErrorPacket(errCodeNotDefined, "cannot read file"), addr)
+
+		return
+	}
+
+	if req.mode == modeNetASCII {
+		data = toNetASCII(data)
+	}
+
+	t := &transfer{
+		conn:       conn,
+		remote:     addr,
+		data:       data,
+		blockSize:  defaultBlockSize,
+		windowSize: 1,
+		timeout:    s.Timeout,
+		retries:    s.Retries,
+	}
+
+	if err := t.run(s.ctx, req, s.MaxWindowSize); err != nil {
+		slog.ErrorContext(s.ctx, "❌ tftp transfer failed",
+			"error", err,
+			"filename", req.filename,
+			"remote", addr.String(),
+		)
+
+		return
+	}
+
+	slog.InfoContext(s.ctx, "✅ tftp transfer succeeded", "filename", req.filename, "remote", addr.String())
+}
+
+// transferAddr binds transfer sockets to the same host as the server, on a random port.
+func transferAddr(serverAddr string) string {
+	host, _, err := net.SplitHostPort(serverAddr)
+	if err != nil {
+		return ":0"
+	}
+
+	return net.JoinHostPort(host, "0")
+}
+
+// ---------------------------------------------------- TRANSFER ---------------------------------------------------- //
+
+type transfer struct {
+	conn   net.PacketConn
+	remote net.Addr
+	data   []byte
+
+	blockSize  int
+	windowSize int
+	timeout    time.Duration
+	retries    int
+}
+
+func (t *transfer) run(ctx context.Context, req request, maxWindow int) error {
+	// Stop the transfer if the server is shutting down.
+	stop := context.AfterFunc(ctx, func() { _ = t.conn.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	if oack := t.negotiate(req, maxWindow); oack != nil {
+		// The client must acknowledge the OACK with an ACK of block 0.
+		if err := t.sendAndAwait(ctx, [][]byte{oack}, 0); err != nil {
+			return err
+		}
+	}
+
+	return t.sendData(ctx)
+}
+
+// negotiate applies the options requested by the client and returns the OACK packet to send, if any.
+func (t *transfer) negotiate(req request, maxWindow int) []byte {
+	accepted := make(map[string]string)
+	keys := make([]string, 0)
+
+	for _, key := range req.optionOrder {
+		value := req.options[key]
+
+		switch key {
+		case optBlockSize:
+			if v, ok := parseIntOption(value, minBlockSize, maxBlockSize); ok {
+				t.blockSize = v
+				accepted[key] = strconv.Itoa(v)
+			}
+		case optWindowSize:
+			if v, ok := parseIntOption(value, minWindowSize, min(maxWindow, maxWindowSize)); ok {
+				t.windowSize = v
+				accepted[key] = strconv.Itoa(v)
+			}
+		case optTimeout:
+			if v, ok := parseIntOption(value, minTimeoutSeconds, maxTimeoutSeconds); ok {
+				t.timeout = time.Duration(v) * time.Second
+				accepted[key] = strconv.Itoa(v)
+			}
+		case optTSize:
+			accepted[key] = strconv.Itoa(len(t.data))
+		default:
+			continue // unknown options are ignored.
+		}
+
+		if _, ok := accepted[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return newOACKPacket(keys, accepted)
+}
+
+// sendData sends the data in windows of windowSize blocks. The last block is always shorter than blockSize, hence an
+// empty block is sent if the data length is a multiple of blockSize.
+func (t *transfer) sendData(ctx context.Context) error {
+	nBlocks := len(t.data)/t.blockSize + 1
+	acked := 0
+
+	for acked < nBlocks {
+		end := min(acked+t.windowSize, nBlocks)
+
+		window := make([][]byte, 0, end-acked)
+		for i := acked; i < end; i++ {
+			lower := i * t.blockSize
+			upper := min(lower+t.blockSize, len(t.data))
+			window = append(window, newDataPacket(uint16(i+1), t.data[lower:upper])) //nolint:gosec // rollover.
+		}
+
+		n, err := t.sendWindow(ctx, window, acked)
+		if err != nil {
+			return err
+		}
+
+		acked = n
+	}
+
+	return nil
+}
+
+// sendAndAwait sends packets until the client acknowledges the expected block.
+func (t *transfer) sendAndAwait(ctx context.Context, packets [][]byte, block uint16) error {
+	for attempt := 0; attempt <= t.retries; attempt++ {
+		if err := t.write(packets); err != nil {
+			return err
+		}
+
+		for {
+			opcode, value, err := t.read(ctx)
+			if isTimeout(err) {
+				break
+			} else if err != nil {
+				return err
+			}
+
+			if opcode == opACK && value == block {
+				return nil
+			}
+		}
+	}
+
+	return errTransferTimedOut
+}
+
+// sendWindow sends a window of data packets starting after the `acked` block and returns the total number of
+// acknowledged blocks.
+func (t *transfer) sendWindow(ctx context.Context, window [][]byte, acked int) (int, error) {
+	for attempt := 0; attempt <= t.retries; attempt++ {
+		if err := t.write(window); err != nil {
+			return 0, err
+		}
+
+		for {
+			opcode, value, err := t.read(ctx)
+			if isTimeout(err) {
+				break
+			} else if err != nil {
+				return 0, err
+			}
+
+			if opcode != opACK {
+				continue
+			}
+
+			// Find which block of the window has been acknowledged, taking block number rollover into account.
+			for i := len(window); i > 0; i-- {
+				if uint16(acked+i) == value { //nolint:gosec // rollover.
+					return acked + i, nil
+				}
+			}
+
+			// With windowsize > 1, a duplicate ACK of the last acknowledged block means the client missed the first
+			// block of the window: retransmit immediately. With windowsize = 1, duplicate ACKs are ignored to avoid the
+			// Sorcerer's Apprentice Syndrome.
+			if uint16(acked) == value && len(window) > 1 { //nolint:gosec // rollover.
+				break
+			}
+		}
+	}
+
+	return 0, errTransferTimedOut
+}
+
+func (t *transfer) write(packets [][]byte) error {
+	for _, p := range packets {
+		if _, err := t.conn.WriteTo(p, t.remote); err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	return nil
+}
+
+// read returns the next ACK or ERROR packet sent by the client. Packets coming from another transfer ID are answered
+// with an error and ignored. An ERROR packet sent by the client aborts the transfer.
+func (t *transfer) read(ctx context.Context) (uint16, uint16, error) {
+	buf := make([]byte, maxPacketSize)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, 0, err
+		}
+
+		if err := t.conn.SetReadDeadline(time.Now().Add(t.timeout)); err != nil {
+			return 0, 0, err //nolint:wrapcheck
+		}
+
+		n, addr, err := t.conn.ReadFrom(buf)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return 0, 0, ctxErr
+			}
+
+			return 0, 0, err //nolint:wrapcheck
+		}
+
+		if addr.String() != t.remote.String() {
+			_, _ = t.conn.WriteTo(newErrorPacket(errCodeUnknownTransferID, "unknown transfer id"), addr)
+			continue
+		}
+
+		opcode, value, msg, err := parseReply(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		if opcode == opERROR {
+			return 0, 0, errors.Join(errTransferAborted, errors.New(msg))
+		}
+
+		return opcode, value, nil
+	}
+}
+
+func isTimeout(err error) bool {
+	return errors.Is(err, os.ErrDeadlineExceeded)
+}
@@ -0,0 +1,246 @@
+//go:build unit
+
+package tftp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/controller"
+	"github.com/alexandremahdhaoui/ipxer/internal/driver/tftp"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/mocks/mockcontroller"
+)
+
+func TestServer(t *testing.T) {
+	var (
+		bootloader *mockcontroller.MockBootloader
+		server     *tftp.Server
+		serverAddr net.Addr
+		client     net.PacketConn
+	)
+
+	setup := func(t *testing.T) func() {
+		t.Helper()
+
+		bootloader = mockcontroller.NewMockBootloader(t)
+		server = tftp.New("127.0.0.1:0", bootloader)
+		server.Timeout = 200 * time.Millisecond
+
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		serverAddr = conn.LocalAddr()
+
+		go func() {
+			if err := server.Serve(conn); !errors.Is(err, tftp.ErrServerClosed) {
+				assert.NoError(t, err)
+			}
+		}()
+
+		client, err = net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		return func() {
+			t.Helper()
+
+			require.NoError(t, client.Close())
+			require.NoError(t, server.Shutdown(context.Background()))
+			bootloader.AssertExpectations(t)
+		}
+	}
+
+	t.Run("RRQ", func(t *testing.T) {
+		for _, tt := range []struct {
+			name    string
+			size    int
+			options map[string]string
+		}{
+			{name: "NoOptions", size: 1500},
+			{name: "ExactMultipleOfBlockSize", size: 1024},
+			{name: "Empty", size: 0},
+			{name: "BlockSize", size: 5000, options: map[string]string{"blksize": "1024", "tsize": "0"}},
+			{name: "WindowSize", size: 70000, options: map[string]string{"blksize": "512", "windowsize": "8"}},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				defer setup(t)()
+
+				expected := make([]byte, tt.size)
+				for i := range expected {
+					expected[i] = byte(i)
+				}
+
+				bootloader.EXPECT().
+					Get(mock.Anything, "x86_64/ipxe.efi").
+					Return(expected, nil).
+					Once()
+
+				sendRRQ(t, client, serverAddr, "x86_64/ipxe.efi", "octet", tt.options)
+
+				actual := receive(t, client, tt.options)
+				assert.Equal(t, expected, actual)
+			})
+		}
+	})
+
+	t.Run("NetASCII", func(t *testing.T) {
+		defer setup(t)()
+
+		bootloader.EXPECT().
+			Get(mock.Anything, "boot.ipxe").
+			Return([]byte("#!ipxe\r\nchain boot\n"), nil).
+			Once()
+
+		sendRRQ(t, client, serverAddr, "boot.ipxe", "netascii", nil)
+
+		actual := receive(t, client, nil)
+		assert.Equal(t, []byte("#!ipxe\r\x00\r\nchain boot\r\n"), actual)
+	})
+
+	t.Run("Ready", func(t *testing.T) {
+		defer setup(t)()
+
+		assert.Eventually(t, server.Ready, time.Second, 10*time.Millisecond)
+
+		require.NoError(t, server.Shutdown(context.Background()))
+		assert.False(t, server.Ready())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("NotFound", func(t *testing.T) {
+			defer setup(t)()
+
+			bootloader.EXPECT().
+				Get(mock.Anything, "arm64/ipxe.efi").
+				Return(nil, controller.ErrBootloaderNotFound).
+				Once()
+
+			sendRRQ(t, client, serverAddr, "arm64/ipxe.efi", "octet", nil)
+
+			opcode, code, _ := readPacket(t, client)
+			assert.Equal(t, uint16(5), opcode)
+			assert.Equal(t, uint16(1), code)
+		})
+
+		t.Run("WRQ", func(t *testing.T) {
+			defer setup(t)()
+
+			packet := append([]byte{0, 2}, []byte("arm64/ipxe.efi\x00octet\x00")...)
+			_, err := client.WriteTo(packet, serverAddr)
+			require.NoError(t, err)
+
+			opcode, code, _ := readPacket(t, client)
+			assert.Equal(t, uint16(5), opcode)
+			assert.Equal(t, uint16(2), code)
+		})
+	})
+}
+
+// ---------------------------------------------------- HELPERS ----------------------------------------------------- //
+
+func sendRRQ(t *testing.T, client net.PacketConn, addr net.Addr, filename, mode string, options map[string]string) {
+	t.Helper()
+
+	packet := []byte{0, 1}
+	packet = append(packet, filename...)
+	packet = append(packet, 0)
+	packet = append(packet, mode...)
+	packet = append(packet, 0)
+
+	for k, v := range options {
+		packet = append(packet, k...)
+		packet = append(packet, 0)
+		packet = append(packet, v...)
+		packet = append(packet, 0)
+	}
+
+	_, err := client.WriteTo(packet, addr)
+	require.NoError(t, err)
+}
+
+// receive acts as a TFTP client and returns the received data.
+func receive(t *testing.T, client net.PacketConn, options map[string]string) []byte {
+	t.Helper()
+
+	blockSize, windowSize := 512, 1
+	if v, ok := options["blksize"]; ok {
+		blockSize, _ = strconv.Atoi(v)
+	}
+
+	if v, ok := options["windowsize"]; ok {
+		windowSize, _ = strconv.Atoi(v)
+	}
+
+	buf := make([]byte, 65536)
+	out := bytes.NewBuffer(make([]byte, 0))
+
+	var (
+		transferAddr net.Addr
+		block        uint16
+		inWindow     int
+	)
+
+	ack := func(b uint16) {
+		packet := []byte{0, 4, 0, 0}
+		binary.BigEndian.PutUint16(packet[2:], b)
+		_, err := client.WriteTo(packet, transferAddr)
+		require.NoError(t, err)
+	}
+
+	for {
+		require.NoError(t, client.SetReadDeadline(time.Now().Add(2*time.Second)))
+		n, addr, err := client.ReadFrom(buf)
+		require.NoError(t, err)
+
+		transferAddr = addr
+		opcode := binary.BigEndian.Uint16(buf[0:2])
+
+		switch opcode {
+		case 6: // OACK
+			if _, ok := options["tsize"]; ok {
+				assert.Contains(t, string(buf[2:n]), "tsize\x00")
+			}
+
+			ack(0)
+		case 3: // DATA
+			require.Equal(t, block+1, binary.BigEndian.Uint16(buf[2:4]))
+			block++
+			inWindow++
+
+			out.Write(buf[4:n])
+
+			last := n-4 < blockSize
+			if last || inWindow == windowSize {
+				ack(block)
+				inWindow = 0
+			}
+
+			if last {
+				return out.Bytes()
+			}
+		default:
+			t.Fatalf("unexpected opcode %d", opcode)
+		}
+	}
+}
+
+func readPacket(t *testing.T, client net.PacketConn) (uint16, uint16, []byte) {
+	t.Helper()
+
+	buf := make([]byte, 65536)
+
+	require.NoError(t, client.SetReadDeadline(time.Now().Add(2*time.Second)))
+	n, _, err := client.ReadFrom(buf)
+	require.NoError(t, err)
+
+	return binary.BigEndian.Uint16(buf[0:2]), binary.BigEndian.Uint16(buf[2:4]), buf[4:n]
+}
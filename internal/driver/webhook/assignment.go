@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"slices"
+	"strings"
 
 	"github.com/alexandremahdhaoui/ipxer/internal/adapter"
 	"github.com/alexandremahdhaoui/ipxer/internal/types"
@@ -42,9 +44,13 @@ func (a *Assignment) Default(ctx context.Context, obj runtime.Object) error {
 		return err // TODO: wrap err
 	}
 
+	if assignment.Labels == nil {
+		assignment.Labels = make(map[string]string)
+	}
+
 	// 1. Remove all "internal" labels. (remove ones created by users && clean up old ones)
 	for k := range assignment.Labels {
-		if !v1alpha1.IsInternalLabel(k) {
+		if v1alpha1.IsInternalLabel(k) {
 			delete(assignment.Labels, k)
 		}
 	}
@@ -59,7 +65,30 @@ func (a *Assignment) Default(ctx context.Context, obj runtime.Object) error {
 		v1alpha1.SetUUIDLabelSelector(assignment, id, "")
 	}
 
-	// 3. Add buildarch labels etc...
+	// 3. Add mac, serial, asset and hostname subject selectors
+	selectors := assignment.Spec.SubjectSelectors
+	for _, s := range selectors.MACList {
+		mac, err := net.ParseMAC(s)
+		if err != nil {
+			return err // TODO: wrap err
+		}
+
+		assignment.Labels[v1alpha1.NewMACLabelSelector(mac)] = ""
+	}
+
+	for _, s := range selectors.SerialList {
+		assignment.Labels[v1alpha1.NewSerialLabelSelector(s)] = ""
+	}
+
+	for _, s := range selectors.AssetList {
+		assignment.Labels[v1alpha1.NewAssetLabelSelector(s)] = ""
+	}
+
+	for _, s := range selectors.HostnameList {
+		assignment.Labels[v1alpha1.NewHostnameLabelSelector(s)] = ""
+	}
+
+	// 4. Add buildarch labels etc...
 	buildarchList := assignment.Spec.SubjectSelectors.BuildarchList
 	if len(buildarchList) == 0 {
 		// unspecified implies any buildarch.
@@ -70,6 +99,22 @@ func (a *Assignment) Default(ctx context.Context, obj runtime.Object) error {
 		assignment.SetBuildarch(b)
 	}
 
+	// 5. Add platform labels.
+	platformList := assignment.Spec.SubjectSelectors.PlatformList
+	if len(platformList) == 0 {
+		// unspecified implies any platform.
+		platformList = slices.Clone(v1alpha1.AllowedPlatformList)
+	}
+
+	for _, p := range platformList {
+		assignment.SetPlatform(p)
+	}
+
+	// 6. Add the default assignment label.
+	if assignment.Spec.IsDefault {
+		assignment.Labels[v1alpha1.DefaultAssignmentLabel] = ""
+	}
+
 	return nil
 }
 
@@ -117,7 +162,10 @@ func (a *Assignment) ValidateDelete(
 func (a *Assignment) validateAssignmentStatic(ctx context.Context, obj runtime.Object) error {
 	for _, f := range []validatingFunc{
 		validateUUIDList,
+		validateMACList,
+		validateSubjectValues,
 		validateBuildarchList,
+		validatePlatformList,
 		validateIsDefault,
 	} {
 		if err := f(ctx, obj); err != nil {
@@ -131,8 +179,8 @@ func (a *Assignment) validateAssignmentStatic(ctx context.Context, obj runtime.O
 func (a *Assignment) validateAssignmentDynamic(ctx context.Context, obj runtime.Object) error {
 	for _, f := range []validatingFunc{
 		a.validateProfileName,
-		a.validateDefaultAssignmentForBuildarchIsUnique,
-		a.validateUUIDAssignmentIsUnique,
+		a.validateDefaultAssignmentForBuildarchAndPlatformIsUnique,
+		a.validateSubjectSelectorsAreUnique,
 	} {
 		if err := f(ctx, obj); err != nil {
 			return err // TODO: wrap err
@@ -157,6 +205,21 @@ func validateBuildarchList(_ context.Context, obj runtime.Object) error {
 	return nil
 }
 
+func validatePlatformList(_ context.Context, obj runtime.Object) error {
+	assignment := obj.(*v1alpha1.Assignment)
+
+	for _, p := range assignment.Spec.SubjectSelectors.PlatformList {
+		if _, ok := v1alpha1.AllowedPlatform[p]; !ok {
+			return errors.Join(
+				errors.New("specified platform is not supported"),
+				fmt.Errorf("expected one of 'efi', 'pcbios'; received %q", p.String()),
+			) // TODO: err + wrap err
+		}
+	}
+
+	return nil
+}
+
 func validateUUIDList(_ context.Context, obj runtime.Object) error {
 	assignment := obj.(*v1alpha1.Assignment)
 
@@ -170,6 +233,41 @@ func validateUUIDList(_ context.Context, obj runtime.Object) error {
 	return nil
 }
 
+func validateMACList(_ context.Context, obj runtime.Object) error {
+	assignment := obj.(*v1alpha1.Assignment)
+
+	for _, mac := range assignment.Spec.SubjectSelectors.MACList {
+		if _, err := net.ParseMAC(mac); err != nil {
+			return err // TODO: wrap err
+		}
+	}
+
+	return nil
+}
+
+// validateSubjectValues ensures serial numbers, asset tags and hostnames are not empty, as iPXE expands unset settings
+// to empty strings.
+func validateSubjectValues(_ context.Context, obj runtime.Object) error {
+	selectors := obj.(*v1alpha1.Assignment).Spec.SubjectSelectors
+
+	for _, subjects := range []struct {
+		kind string
+		list []string
+	}{
+		{kind: types.Serial, list: selectors.SerialList},
+		{kind: types.Asset, list: selectors.AssetList},
+		{kind: types.Hostname, list: selectors.HostnameList},
+	} {
+		for _, v := range subjects.list {
+			if strings.TrimSpace(v) == "" {
+				return fmt.Errorf("subject selectors of type %s must not be empty", subjects.kind) // TODO: err + wrap err
+			}
+		}
+	}
+
+	return nil
+}
+
 func validateIsDefault(_ context.Context, obj runtime.Object) error {
 	assignment := obj.(*v1alpha1.Assignment)
 
@@ -177,8 +275,22 @@ func validateIsDefault(_ context.Context, obj runtime.Object) error {
 		return nil
 	}
 
-	if len(assignment.Spec.SubjectSelectors.UUIDList) > 0 {
-		return errors.New("a default assignment must not specify subject selectors of type UUID") // TODO: err + wrap err
+	selectors := assignment.Spec.SubjectSelectors
+	for _, subjects := range []struct {
+		kind string
+		list []string
+	}{
+		{kind: "UUID", list: selectors.UUIDList},
+		{kind: "MAC", list: selectors.MACList},
+		{kind: "serial number", list: selectors.SerialList},
+		{kind: "asset tag", list: selectors.AssetList},
+		{kind: "hostname", list: selectors.HostnameList},
+	} {
+		if len(subjects.list) > 0 {
+			return fmt.Errorf(
+				"a default assignment must not specify subject selectors of type %s", subjects.kind,
+			) // TODO: err + wrap err
+		}
 	}
 
 	return nil
@@ -198,72 +310,82 @@ func (a *Assignment) validateProfileName(ctx context.Context, obj runtime.Object
 	return nil
 }
 
-func (a *Assignment) validateDefaultAssignmentForBuildarchIsUnique(ctx context.Context, obj runtime.Object) error {
-	//  A default assignment should be unique for a given list of buildarch.
+func (a *Assignment) validateDefaultAssignmentForBuildarchAndPlatformIsUnique(
+	ctx context.Context,
+	obj runtime.Object,
+) error {
+	//  A default assignment should be unique for a given pair of buildarch and platform.
 	assignment := obj.(*v1alpha1.Assignment)
 	if !assignment.Spec.IsDefault {
 		return nil
 	}
 
 	for _, b := range assignment.GetBuildarchList() {
-		assign, err := a.assignment.FindDefaultByBuildarch(ctx, b.String())
-		if errors.Is(err, adapter.ErrAssignmentNotFound) {
-			// this is the good scenario
-			continue
-		} else if err != nil {
-			return err // TODO: wrap err
-		}
-
-		if assign.Name == assignment.Name {
-			// update scenario should pass
-			continue
+		for _, p := range assignment.GetPlatformList() {
+			assign, err := a.assignment.FindDefault(ctx, types.IPXESelectors{
+				Buildarch: b.String(),
+				Platform:  p.String(),
+			})
+			if errors.Is(err, adapter.ErrAssignmentNotFound) {
+				// this is the good scenario
+				continue
+			} else if err != nil {
+				return err // TODO: wrap err
+			}
+
+			if assign.Name == assignment.Name {
+				// update scenario should pass
+				continue
+			}
+
+			return fmt.Errorf(
+				"a default assignment already exists for buildarch %q and platform %q",
+				b.String(), p.String(),
+			) // TODO: err + wrap err
 		}
-
-		return errors.New("TODO") // TODO: err + wrap err
 	}
 
 	return nil
 }
 
-func (a *Assignment) validateUUIDAssignmentIsUnique(ctx context.Context, obj runtime.Object) error {
+func (a *Assignment) validateSubjectSelectorsAreUnique(ctx context.Context, obj runtime.Object) error {
 	assignment := obj.(*v1alpha1.Assignment)
 
 	if assignment.Spec.IsDefault {
 		return nil
 	}
 
-	// 1. Assignment should be mutually exclusive.
-	// 1.a. Build list of selectors
-	selectorsList := make([]types.IPXESelectors, 0)
-	// this is extremely inefficient
-	for _, id := range assignment.Spec.SubjectSelectors.UUIDList {
-		parsed, _ := uuid.Parse(id) // safely ignoring err because it has already been validated.
-
-		selectorsList = append(selectorsList, types.IPXESelectors{
-			UUID: parsed,
-		})
-	}
-
-	// 1.b. check if we find any match.
-	// TODO: looping over selectors is a very poor operation; use a better solution, such as listing all then filtering.
-	for _, selectors := range selectorsList {
-		// Verify no other Assignment exist for the specified selector.
-		matchedAssignment, err := a.assignment.FindBySelectors(ctx, selectors)
-		if errors.Is(err, adapter.ErrAssignmentNotFound) {
-			// ignore if assignment is not found.
-			continue
-		} else if err != nil {
-			return err // TODO: wrap error
-		}
+	subjects, err := adapter.SubjectSelectors(assignment)
+	if err != nil {
+		return err // TODO: wrap err
+	}
 
-		if matchedAssignment.Name == assignment.GetName() {
-			// UPDATE CASE: matchedAssignment matches the assignment being modified: we can safely ignore.
-			continue
+	// A subject identifier must be selected by at most one assignment for a given pair of buildarch and platform.
+	for _, subject := range subjects {
+		for _, b := range assignment.GetBuildarchList() {
+			for _, p := range assignment.GetPlatformList() {
+				selectors := subject
+				selectors.Buildarch = b.String()
+				selectors.Platform = p.String()
+
+				list, err := a.assignment.ListBySelectors(ctx, selectors)
+				if err != nil {
+					return err // TODO: wrap error
+				}
+
+				for _, matched := range list {
+					if matched.Name == assignment.GetName() {
+						// UPDATE CASE: the matched assignment is the assignment being modified: we can safely ignore.
+						continue
+					}
+
+					return fmt.Errorf(
+						"subject %s with buildarch %q and platform %q is already selected by assignment %q",
+						subject.Subject(), b.String(), p.String(), matched.Name,
+					) // TODO: err + wrap err
+				}
+			}
 		}
-
-		return errors.Join(
-			errors.New("assignment cannot reference a subject selector referenced "),
-		) // TODO: wrap err
 	}
 
 	return nil
@@ -1,3 +1,187 @@
 //go:build unit
 
 package webhook_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/driver/webhook"
+	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/mocks/mockadapter"
+	"github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAssignment(t *testing.T) {
+	var (
+		ctx context.Context
+		obj *v1alpha1.Assignment
+
+		assignmentAdapter *mockadapter.MockAssignment
+		profileAdapter    *mockadapter.MockProfile
+		assignment        *webhook.Assignment
+	)
+
+	setup := func(t *testing.T) func() {
+		t.Helper()
+
+		ctx = context.Background()
+		obj = &v1alpha1.Assignment{
+			ObjectMeta: metav1.ObjectMeta{Name: "assignment"},
+			Spec: v1alpha1.AssignmentSpec{
+				ProfileName: "profile",
+				SubjectSelectors: v1alpha1.SubjectSelectors{
+					BuildarchList: []v1alpha1.Buildarch{v1alpha1.Arm64},
+				},
+			},
+		}
+
+		assignmentAdapter = mockadapter.NewMockAssignment(t)
+		profileAdapter = mockadapter.NewMockProfile(t)
+		assignment = webhook.NewAssignment(assignmentAdapter, profileAdapter)
+
+		return func() {
+			t.Helper()
+
+			assignmentAdapter.AssertExpectations(t)
+			profileAdapter.AssertExpectations(t)
+		}
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			defer setup(t)()
+
+			id := uuid.New()
+			obj.Spec.SubjectSelectors.UUIDList = []string{id.String()}
+			obj.Labels = map[string]string{
+				"user-label":                         "value",
+				v1alpha1.X8664BuildarchLabelSelector: "",
+			}
+
+			require.NoError(t, assignment.Default(ctx, obj))
+
+			assert.Equal(t, map[string]string{
+				"user-label":                         "value",
+				v1alpha1.NewUUIDLabelSelector(id):    "",
+				v1alpha1.Arm64BuildarchLabelSelector: "",
+				v1alpha1.EFIPlatformLabelSelector:    "",
+				v1alpha1.PCBIOSPlatformLabelSelector: "",
+			}, obj.Labels)
+		})
+
+		t.Run("SubjectIdentifiers", func(t *testing.T) {
+			defer setup(t)()
+
+			obj.Spec.SubjectSelectors.PlatformList = []v1alpha1.Platform{v1alpha1.EFI}
+			obj.Spec.SubjectSelectors.MACList = []string{"AA:BB:CC:DD:EE:FF"}
+			obj.Spec.SubjectSelectors.SerialList = []string{"CZ2D2507KF"}
+			obj.Spec.SubjectSelectors.AssetList = []string{"ASSET-0042"}
+			obj.Spec.SubjectSelectors.HostnameList = []string{"Node-0.example.com"}
+
+			require.NoError(t, assignment.Default(ctx, obj))
+
+			assert.Equal(t, map[string]string{
+				v1alpha1.LabelSelector("aa-bb-cc-dd-ee-ff", v1alpha1.MACPrefix): "",
+				v1alpha1.NewSerialLabelSelector("CZ2D2507KF"):                   "",
+				v1alpha1.NewAssetLabelSelector("ASSET-0042"):                    "",
+				v1alpha1.NewHostnameLabelSelector("node-0.example.com"):         "",
+				v1alpha1.Arm64BuildarchLabelSelector:                            "",
+				v1alpha1.EFIPlatformLabelSelector:                               "",
+			}, obj.Labels)
+		})
+
+		t.Run("InvalidMAC", func(t *testing.T) {
+			defer setup(t)()
+
+			obj.Spec.SubjectSelectors.MACList = []string{"not-a-mac"}
+
+			assert.Error(t, assignment.Default(ctx, obj))
+		})
+
+		t.Run("IsDefault", func(t *testing.T) {
+			defer setup(t)()
+
+			obj.Spec.IsDefault = true
+			obj.Spec.SubjectSelectors.PlatformList = []v1alpha1.Platform{v1alpha1.EFI}
+
+			require.NoError(t, assignment.Default(ctx, obj))
+
+			assert.Equal(t, map[string]string{
+				v1alpha1.DefaultAssignmentLabel:      "",
+				v1alpha1.Arm64BuildarchLabelSelector: "",
+				v1alpha1.EFIPlatformLabelSelector:    "",
+			}, obj.Labels)
+		})
+
+		t.Run("InvalidBuildarch", func(t *testing.T) {
+			defer setup(t)()
+
+			obj.Spec.SubjectSelectors.BuildarchList = []v1alpha1.Buildarch{"mips"}
+
+			assert.Error(t, assignment.Default(ctx, obj))
+		})
+	})
+
+	t.Run("ValidateCreate", func(t *testing.T) {
+		t.Run("Success", func(t *testing.T) {
+			defer setup(t)()
+
+			obj.Spec.SubjectSelectors.PlatformList = []v1alpha1.Platform{v1alpha1.EFI}
+			obj.Spec.SubjectSelectors.SerialList = []string{"CZ2D2507KF"}
+			require.NoError(t, assignment.Default(ctx, obj))
+
+			profileAdapter.EXPECT().Get(ctx, "profile").Return(types.Profile{}, nil).Once()
+			assignmentAdapter.EXPECT().ListBySelectors(ctx, types.IPXESelectors{
+				Buildarch: v1alpha1.Arm64.String(),
+				Platform:  v1alpha1.EFI.String(),
+				Serial:    "CZ2D2507KF",
+			}).Return([]types.Assignment{{Name: obj.Name}}, nil).Once()
+
+			_, err := assignment.ValidateCreate(ctx, obj)
+			assert.NoError(t, err)
+		})
+
+		t.Run("SubjectConflict", func(t *testing.T) {
+			defer setup(t)()
+
+			obj.Spec.SubjectSelectors.PlatformList = []v1alpha1.Platform{v1alpha1.EFI}
+			obj.Spec.SubjectSelectors.MACList = []string{"aa:bb:cc:dd:ee:ff"}
+			require.NoError(t, assignment.Default(ctx, obj))
+
+			profileAdapter.EXPECT().Get(ctx, "profile").Return(types.Profile{}, nil).Once()
+			assignmentAdapter.EXPECT().ListBySelectors(ctx, types.IPXESelectors{
+				Buildarch: v1alpha1.Arm64.String(),
+				Platform:  v1alpha1.EFI.String(),
+				MAC:       net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff},
+			}).Return([]types.Assignment{{Name: "other"}}, nil).Once()
+
+			_, err := assignment.ValidateCreate(ctx, obj)
+			assert.ErrorContains(t, err, `subject mac "aa:bb:cc:dd:ee:ff"`)
+		})
+
+		t.Run("DefaultWithSubjectIdentifiers", func(t *testing.T) {
+			defer setup(t)()
+
+			obj.Spec.IsDefault = true
+			obj.Spec.SubjectSelectors.HostnameList = []string{"node-0"}
+
+			_, err := assignment.ValidateCreate(ctx, obj)
+			assert.ErrorContains(t, err, "hostname")
+		})
+
+		t.Run("EmptySerial", func(t *testing.T) {
+			defer setup(t)()
+
+			obj.Spec.SubjectSelectors.SerialList = []string{"  "}
+
+			_, err := assignment.ValidateCreate(ctx, obj)
+			assert.Error(t, err)
+		})
+	})
+}
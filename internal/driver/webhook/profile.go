@@ -3,8 +3,14 @@ package webhook
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/url"
 	"regexp"
+	"strings"
 
+	"github.com/alexandremahdhaoui/ipxer/internal/controller"
+	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/alexandremahdhaoui/ipxer/internal/util/templateutil"
 	"github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
 	"github.com/google/uuid"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -21,6 +27,11 @@ var (
 	// Regexes
 
 	contentNameRegex = regexp.MustCompile("")
+	checksumRegex    = regexp.MustCompile(`^(sha256:[0-9a-fA-F]{64}|sha512:[0-9a-fA-F]{128})$`)
+	// gitSCPLikeURLRegex matches the scp-like syntax of ssh repositories, e.g. `git@github.com:example/configs.git`.
+	gitSCPLikeURLRegex = regexp.MustCompile(`^[\w.-]+@[\w.-]+:[\w./~-]+$`)
+	// headerNameRegex matches the tokens allowed as header names by RFC 9110.
+	headerNameRegex = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
 )
 
 func NewProfile() *Profile {
@@ -39,6 +50,10 @@ func (p *Profile) Default(ctx context.Context, obj runtime.Object) error {
 		return err // TODO: wrap err
 	}
 
+	if profile.Labels == nil {
+		profile.Labels = make(map[string]string)
+	}
+
 	// 1. get config UUIDs
 	reverseIDMap := make(map[string]string)
 	for k, value := range profile.Labels {
@@ -49,7 +64,7 @@ func (p *Profile) Default(ctx context.Context, obj runtime.Object) error {
 
 	// 2. Remove all "internal" labels.
 	for k := range profile.Labels {
-		if !v1alpha1.IsInternalLabel(k) {
+		if v1alpha1.IsInternalLabel(k) {
 			delete(profile.Labels, k)
 		}
 	}
@@ -60,7 +75,7 @@ func (p *Profile) Default(ctx context.Context, obj runtime.Object) error {
 			if id, ok := reverseIDMap[content.Name]; ok {
 				profile.Labels[id] = content.Name
 			} else {
-				profile.Labels[uuid.New().String()] = content.Name
+				profile.Labels[v1alpha1.NewUUIDLabelSelector(uuid.New())] = content.Name
 			}
 		}
 	}
@@ -127,8 +142,17 @@ func (p *Profile) validateProfileDynamic(ctx context.Context, obj runtime.Object
 	return nil
 }
 
-func validateIPXETemplate(_ context.Context, _ runtime.Object) error {
-	return nil
+// validateIPXETemplate ensures the iPXE template is valid and renders, hence rejecting broken profiles at admission
+// instead of when machines boot.
+func validateIPXETemplate(_ context.Context, obj runtime.Object) error {
+	profile := obj.(*v1alpha1.Profile)
+
+	names := make([]string, 0, len(profile.Spec.AdditionalContent))
+	for _, content := range profile.Spec.AdditionalContent {
+		names = append(names, content.Name)
+	}
+
+	return controller.ValidateIPXETemplate(profile.Spec.IPXETemplate, names) //nolint:wrapcheck
 }
 
 func validateAdditionalContent(ctx context.Context, obj runtime.Object) error {
@@ -146,23 +170,55 @@ func validateAdditionalContent(ctx context.Context, obj runtime.Object) error {
 		}
 
 		var i uint
-		for _, ptr := range []any{
-			content.Inline,
-			content.ObjectRef,
-			content.Webhook,
-		} {
-			if ptr != nil {
-				i += 1
+		if content.Inline != nil {
+			i++
+		}
+
+		if content.ObjectRef != nil {
+			i++
+		}
+
+		if content.Webhook != nil {
+			i++
+		}
+
+		if content.HTTP != nil {
+			i++
+		}
+
+		if content.S3 != nil {
+			i++
+		}
+
+		if content.OCI != nil {
+			i++
+		}
+
+		if content.Git != nil {
+			i++
+		}
+
+		// templates of inline contents are known at admission, whereas other sources are only resolved at boot time.
+		if content.Template && content.Inline != nil {
+			if _, err := templateutil.New(content.Name).Parse(*content.Inline); err != nil {
+				return errors.Join(err, fmt.Errorf("invalid template of additionalContent %q", content.Name))
 			}
 		}
 
+		if content.CacheTTL != nil && content.CacheTTL.Duration < 0 {
+			return fmt.Errorf("cacheTTL of additionalContent %q must not be negative", content.Name)
+		}
+
+		// only webhooks document a 404 Not Found response.
+		if content.NotFoundAsEmpty && content.Webhook == nil {
+			return fmt.Errorf("notFoundAsEmpty of additionalContent %q requires a webhook", content.Name)
+		}
+
 		switch {
-		case i == 0 || i > 1:
+		case i != 1:
 			return errors.New(
 				"additionalContent MUST contain exactly 1 content configuration",
 			) // TODO: wrap err
-		case content.Inline != nil:
-			return nil
 		case content.ObjectRef != nil:
 			if err := validateObjectRef(content.ObjectRef); err != nil {
 				return err // TODO: wrap err
@@ -171,12 +227,26 @@ func validateAdditionalContent(ctx context.Context, obj runtime.Object) error {
 			if err := validateWebhookConfig(content.Webhook); err != nil {
 				return err // TODO: wrap err
 			}
+		case content.HTTP != nil:
+			if err := validateHTTPConfig(content.HTTP); err != nil {
+				return errors.Join(err, fmt.Errorf("invalid http config of additionalContent %q", content.Name))
+			}
+		case content.S3 != nil:
+			if err := validateS3Config(content.S3); err != nil {
+				return errors.Join(err, fmt.Errorf("invalid s3 config of additionalContent %q", content.Name))
+			}
+		case content.OCI != nil:
+			if err := validateOCIConfig(content.OCI); err != nil {
+				return errors.Join(err, fmt.Errorf("invalid oci config of additionalContent %q", content.Name))
+			}
+		case content.Git != nil:
+			if err := validateGitConfig(content.Git); err != nil {
+				return errors.Join(err, fmt.Errorf("invalid git config of additionalContent %q", content.Name))
+			}
 		}
 	}
 
-	panic(
-		"open an issue on github",
-	) // this branch does not exist, open an issue if you manage to pass the above s/c.
+	return nil
 }
 
 func validateObjectRef(ref *v1alpha1.ObjectRef) error {
@@ -188,16 +258,54 @@ func validateObjectRef(ref *v1alpha1.ObjectRef) error {
 		return err // TODO: wrap err
 	}
 
+	switch types.ObjectRefEncoding(ref.Encoding) {
+	case types.ObjectRefDefaultEncoding, types.ObjectRefRawEncoding, types.ObjectRefBase64Encoding:
+	default:
+		return fmt.Errorf(
+			"encoding %q must be %q or %q", ref.Encoding, types.ObjectRefRawEncoding, types.ObjectRefBase64Encoding,
+		)
+	}
+
 	return nil
 }
 
 func validateWebhookConfig(cfg *v1alpha1.WebhookConfig) error {
+	if _, err := types.ParseWebhookURL(cfg.URL); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	for name := range cfg.Headers {
+		if !headerNameRegex.MatchString(name) {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+	}
+
+	for key := range cfg.QueryParams {
+		if key == "" {
+			return errors.New("query parameters must not be empty")
+		}
+	}
+
+	if cfg.BasicAuthObjectRef != nil && cfg.BearerTokenObjectRef != nil {
+		return errors.New("basicAuthRef and bearerTokenRef are mutually exclusive")
+	}
+
 	if cfg.BasicAuthObjectRef != nil {
 		if err := validateBasicAuthObjectRef(cfg.BasicAuthObjectRef); err != nil {
 			return err // TODO: wrap err
 		}
 	}
 
+	if ref := cfg.BearerTokenObjectRef; ref != nil {
+		if err := validateResourceRef(ref.ResourceRef); err != nil {
+			return err
+		}
+
+		if err := validateJSONPath(ref.TokenJSONPath); err != nil {
+			return err
+		}
+	}
+
 	if cfg.MTLSObjectRef != nil {
 		if err := validateMTLSObjectRef(cfg.MTLSObjectRef); err != nil {
 			return err // TODO: wrap err
@@ -207,6 +315,139 @@ func validateWebhookConfig(cfg *v1alpha1.WebhookConfig) error {
 	return nil
 }
 
+func validateHTTPConfig(cfg *v1alpha1.HTTPConfig) error {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url %q must be an absolute http or https url", cfg.URL)
+	}
+
+	if cfg.Checksum != "" && !checksumRegex.MatchString(cfg.Checksum) {
+		return fmt.Errorf("checksum %q must be formatted as sha256:<hex digest> or sha512:<hex digest>", cfg.Checksum)
+	}
+
+	if cfg.BasicAuthObjectRef != nil {
+		if err := validateBasicAuthObjectRef(cfg.BasicAuthObjectRef); err != nil {
+			return err
+		}
+	}
+
+	if cfg.MTLSObjectRef != nil {
+		if err := validateMTLSObjectRef(cfg.MTLSObjectRef); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateS3Config(cfg *v1alpha1.S3Config) error {
+	if cfg.Bucket == "" || cfg.Key == "" {
+		return errors.New("bucket and key must be specified")
+	}
+
+	if _, err := templateutil.New("key").Parse(cfg.Key); err != nil {
+		return errors.Join(err, errors.New("invalid key template"))
+	}
+
+	if cfg.Endpoint != "" {
+		if u, err := url.Parse(cfg.Endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("endpoint %q must be an absolute url", cfg.Endpoint)
+		}
+	}
+
+	if ref := cfg.CredentialsRef; ref != nil {
+		if err := validateResourceRef(ref.ResourceRef); err != nil {
+			return err
+		}
+
+		for _, s := range []string{ref.AccessKeyIDJSONPath, ref.SecretAccessKeyJSONPath, ref.SessionTokenJSONPath} {
+			if err := validateJSONPath(s); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateOCIConfig(cfg *v1alpha1.OCIConfig) error {
+	if _, err := types.ParseOCIReference(cfg.Reference); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if cfg.MediaType == "" {
+		return errors.New("mediaType must be specified")
+	}
+
+	if cfg.CredentialsRef != nil {
+		if err := validateBasicAuthObjectRef(cfg.CredentialsRef); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateGitConfig(cfg *v1alpha1.GitConfig) error {
+	isSSH := gitSCPLikeURLRegex.MatchString(cfg.URL)
+	if !isSSH {
+		u, err := url.Parse(cfg.URL)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		if (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "ssh") || u.Host == "" {
+			return fmt.Errorf("url %q must be an absolute http, https or ssh url, or an scp-like ssh url", cfg.URL)
+		}
+
+		if _, ok := u.User.Password(); ok {
+			return fmt.Errorf("url %q must not contain a password, which must be referenced by basicAuthRef", u.Redacted())
+		}
+
+		isSSH = u.Scheme == "ssh"
+	}
+
+	if cfg.Path == "" {
+		return errors.New("path must be specified")
+	}
+
+	if strings.HasPrefix(cfg.Ref, "-") || strings.ContainsAny(cfg.Ref, " \t\n") {
+		return fmt.Errorf("ref %q is invalid", cfg.Ref)
+	}
+
+	if cfg.BasicAuthObjectRef != nil {
+		if isSSH {
+			return errors.New("basicAuthRef requires an http or https url")
+		}
+
+		if err := validateBasicAuthObjectRef(cfg.BasicAuthObjectRef); err != nil {
+			return err
+		}
+	}
+
+	if ref := cfg.SSHKeyObjectRef; ref != nil {
+		if !isSSH {
+			return errors.New("sshKeyRef requires an ssh url")
+		}
+
+		if err := validateResourceRef(ref.ResourceRef); err != nil {
+			return err
+		}
+
+		for _, s := range []string{ref.PrivateKeyJSONPath, ref.KnownHostsJSONPath} {
+			if err := validateJSONPath(s); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func validateTransformer(transformer v1alpha1.Transformer) error {
 	cfgCount := 0
 	if transformer.ButaneToIgnition {
@@ -1,8 +1,14 @@
 package types
 
 import (
+	"encoding"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"net"
+	"net/url"
+	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
@@ -33,7 +39,7 @@ const (
 	Filename     = "filename"      // Boot filename
 	NextServer   = "next-server"   // TFTP server
 	RootPath     = "root-path"     // SAN root path
-	SanFilename  = "scan-filename" // SAN filename
+	SanFilename  = "san-filename"  // SAN filename
 	InitiatorIqn = "initiator-iqn" // iSCSI initiator name
 	KeepSan      = "keep-san"      // Preserve SAN connection
 	SkipSanBoot  = "skip-san-boot" // Do not boot from SAN device
@@ -85,84 +91,155 @@ const (
 // --- PARAMS --- //
 
 type IpxeParams struct {
-	Mac        *hexa   //	MAC address
-	BusType    *string // Bus type
-	BusLoc     *uint32 // Bus location
-	BusID      *hexa   // Bus ExposedConfigID
-	Chip       *string // Chip type
-	Ssid       *string // Wireless SSID
-	ActiveScan *int8   // Actively scan for wireless orks
-	Key        *string // Wireless encryption key
+	Mac        *hexa   `ipxe:"mac"`         //	MAC address
+	BusType    *string `ipxe:"bustype"`     // Bus type
+	BusLoc     *uint32 `ipxe:"busloc"`      // Bus location
+	BusID      *hexa   `ipxe:"busid"`       // Bus ExposedConfigID
+	Chip       *string `ipxe:"chip"`        // Chip type
+	Ssid       *string `ipxe:"ssid"`        // Wireless SSID
+	ActiveScan *int8   `ipxe:"active-scan"` // Actively scan for wireless orks
+	Key        *string `ipxe:"key"`         // Wireless encryption key
 
 	// IPv4 settings
 
-	Ip      *net.IP // IP address
-	Netmask *net.IP // Subnet mask
-	Gateway *net.IP // Default gateway
-	Dns     *net.IP // DNS server
-	Domain  *string // DNS domain
+	Ip      *net.IP `ipxe:"ip"`      // IP address
+	Netmask *net.IP `ipxe:"netmask"` // Subnet mask
+	Gateway *net.IP `ipxe:"gateway"` // Default gateway
+	Dns     *net.IP `ipxe:"dns"`     // DNS server
+	Domain  *string `ipxe:"domain"`  // DNS domain
 
 	// Boot settings
 
-	Filename     *string // Boot filename
-	NextServer   *net.IP // TFTP server
-	RootPath     *string // SAN root path
-	SanFilename  *string // SAN filename
-	InitiatorIqn *string // iSCSI initiator name
-	KeepSan      *int8   // Preserve SAN connection
-	SkipSanBoot  *int8   // Do not boot from SAN device
+	Filename     *string `ipxe:"filename"`      // Boot filename
+	NextServer   *net.IP `ipxe:"next-server"`   // TFTP server
+	RootPath     *string `ipxe:"root-path"`     // SAN root path
+	SanFilename  *string `ipxe:"san-filename"`  // SAN filename
+	InitiatorIqn *string `ipxe:"initiator-iqn"` // iSCSI initiator name
+	KeepSan      *int8   `ipxe:"keep-san"`      // Preserve SAN connection
+	SkipSanBoot  *int8   `ipxe:"skip-san-boot"` // Do not boot from SAN device
 
 	// Host settings
 
-	Hostname     *string    // Host name
-	UUID         *uuid.UUID // UUID
-	UserClass    *string    // DHCP user class
-	Manufacturer *string    // Manufacturer
-	Product      *string    // Product name
-	Serial       *string    // Serial number
-	Asset        *string    // Asset tag
+	Hostname     *string    `ipxe:"hostname"`     // Host name
+	UUID         *uuid.UUID `ipxe:"uuid"`         // UUID
+	UserClass    *string    `ipxe:"user-class"`   // DHCP user class
+	Manufacturer *string    `ipxe:"manufacturer"` // Manufacturer
+	Product      *string    `ipxe:"product"`      // Product name
+	Serial       *string    `ipxe:"serial"`       // Serial number
+	Asset        *string    `ipxe:"asset"`        // Asset tag
 
 	// Authentication settings
 
-	Username        *string // User name
-	Password        *string // Password
-	ReverseUsername *string // Reverse user name
-	ReversePassword *string // Reverse password
+	Username        *string `ipxe:"username"`         // User name
+	Password        *string `ipxe:"password"`         // Password
+	ReverseUsername *string `ipxe:"reverse-username"` // Reverse user name
+	ReversePassword *string `ipxe:"reverse-password"` // Reverse password
 
 	// Cryptography settings
 
-	Crosscert *string // Cross-signed certificate source
-	Trust     *hexa   // Trusted root certificate fingerprints
-	Cert      *hexa   // Client certificate
-	Privkey   *hexa   // Client private key
+	Crosscert *string `ipxe:"crosscert"` // Cross-signed certificate source
+	Trust     *hexa   `ipxe:"trust"`     // Trusted root certificate fingerprints
+	Cert      *hexa   `ipxe:"cert"`      // Client certificate
+	Privkey   *hexa   `ipxe:"privkey"`   // Client private key
 
 	// Miscellaneous settings
 
-	Buildarch  *string // Build architecture
-	Cpumodel   *string // CPU model
-	Cpuvendor  *string // CPU vendor
-	DhcpServer *net.IP // DHCP server
-	Keymap     *string // Keyboard layout
-	Memsize    *int32  // Memory size
-	Platform   *string // Firmware platform
-	Priority   *int8   // Settings priority
-	Scriptlet  *string // Boot scriptlet
-	Syslog     *net.IP // Syslog server
-	Syslogs    *string // Encrypted syslog server
-	Sysmac     *hexa   // System MAC address
-	Unixtime   *uint32 // Seconds since the Epoch
-	UseCached  *uint8  // Use cached settings
-	Version    *string // iPXE version
-	Vram       *[]byte // Video RAM contents
+	Buildarch  *string `ipxe:"buildarch"`   // Build architecture
+	Cpumodel   *string `ipxe:"cpumodel"`    // CPU model
+	Cpuvendor  *string `ipxe:"cpuvendor"`   // CPU vendor
+	DhcpServer *net.IP `ipxe:"dhcp-server"` // DHCP server
+	Keymap     *string `ipxe:"keymap"`      // Keyboard layout
+	Memsize    *int32  `ipxe:"memsize"`     // Memory size
+	Platform   *string `ipxe:"platform"`    // Firmware platform
+	Priority   *int8   `ipxe:"priority"`    // Settings priority
+	Scriptlet  *string `ipxe:"scriptlet"`   // Boot scriptlet
+	Syslog     *net.IP `ipxe:"syslog"`      // Syslog server
+	Syslogs    *string `ipxe:"syslogs"`     // Encrypted syslog server
+	Sysmac     *hexa   `ipxe:"sysmac"`      // System MAC address
+	Unixtime   *uint32 `ipxe:"unixtime"`    // Seconds since the Epoch
+	UseCached  *uint8  `ipxe:"use-cached"`  // Use cached settings
+	Version    *string `ipxe:"version"`     // iPXE version
+	Vram       *[]byte `ipxe:"vram"`        // Video RAM contents
+}
+
+var ErrParsingIpxeParams = errors.New("parsing ipxe params")
+
+// ParseIpxeParams parses the iPXE settings forwarded as query parameters, e.g. `?mac=aa-bb-cc-dd-ee-ff&memsize=4096`.
+// Empty values are ignored, as iPXE expands unset settings to empty strings.
+func ParseIpxeParams(query url.Values) (IpxeParams, error) {
+	out := IpxeParams{}
+	v := reflect.ValueOf(&out).Elem()
+
+	for i := range v.NumField() {
+		name := v.Type().Field(i).Tag.Get(ipxeTag)
+
+		s := query.Get(name)
+		if s == "" {
+			continue
+		}
+
+		field := v.Field(i)
+		ptr := reflect.New(field.Type().Elem())
+
+		if err := parseIpxeParam(ptr, s); err != nil {
+			return IpxeParams{}, errors.Join(err, fmt.Errorf("parsing param %q", name), ErrParsingIpxeParams)
+		}
+
+		field.Set(ptr)
+	}
+
+	return out, nil
+}
+
+func parseIpxeParam(ptr reflect.Value, s string) error {
+	if u, ok := ptr.Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s)) //nolint:wrapcheck
+	}
+
+	elem := ptr.Elem()
+
+	switch elem.Kind() { //nolint:exhaustive
+	case reflect.String:
+		elem.SetString(s)
+	case reflect.Int8, reflect.Int32:
+		n, err := strconv.ParseInt(s, 0, elem.Type().Bits())
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		elem.SetInt(n)
+	case reflect.Uint8, reflect.Uint32:
+		n, err := strconv.ParseUint(s, 0, elem.Type().Bits())
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		elem.SetUint(n)
+	case reflect.Slice:
+		elem.SetBytes([]byte(s))
+	}
+
+	return nil
 }
 
 type hexa []byte
 
-// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+// String formats the bytes as colon-separated hexadecimal, e.g. `aa:bb:cc`.
+func (b hexa) String() string {
+	out := make([]string, 0, len(b))
+	for _, c := range b {
+		out = append(out, hex.EncodeToString([]byte{c}))
+	}
+
+	return strings.Join(out, ":")
+}
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface. Bytes may be separated by colons or hyphens, as
+// formatted by iPXE's `hex` and `hexhyp` types.
 func (b *hexa) UnmarshalText(text []byte) error {
 	*b = make(hexa, 0)
 
-	for _, s := range strings.Split(string(text), ":") {
+	for _, s := range strings.FieldsFunc(string(text), func(r rune) bool { return r == ':' || r == '-' }) {
 		decoded, err := hex.DecodeString(s)
 		if err != nil {
 			return err // TODO: write this err.
@@ -174,9 +251,225 @@ func (b *hexa) UnmarshalText(text []byte) error {
 	return nil
 }
 
+// --- BOOTSTRAP PARAMS --- //
+
+const (
+	ipxeTag = "ipxe"
+
+	// UriStringType URI-encodes a setting.
+	UriStringType = "uristring"
+	// HexHypType formats a setting as hyphen-separated hexadecimal.
+	HexHypType = "hexhyp"
+)
+
+var (
+	ErrParsingIPXEParam = errors.New("parsing ipxe param")
+
+	// DefaultIPXEParams are forwarded by the bootstrap script when no params are configured.
+	DefaultIPXEParams = []IPXEParam{ //nolint:gochecknoglobals
+		{Name: Uuid},
+		{Name: Buildarch, Type: UriStringType},
+		{Name: Platform, Type: UriStringType},
+		{Name: Mac, Type: HexHypType},
+		{Name: Serial, Type: UriStringType},
+		{Name: Asset, Type: UriStringType},
+		{Name: Hostname, Type: UriStringType},
+		{Name: Ip},
+	}
+
+	// requiredIPXEParams are always forwarded, as the `/ipxe` endpoint requires them.
+	requiredIPXEParams = []IPXEParam{ //nolint:gochecknoglobals
+		{Name: Uuid},
+		{Name: Buildarch, Type: UriStringType},
+	}
+
+	// ipxeSettings are the iPXE settings which can be forwarded, i.e. the settings of IpxeParams.
+	ipxeSettings = func() map[string]struct{} { //nolint:gochecknoglobals
+		out := make(map[string]struct{})
+
+		t := reflect.TypeOf(IpxeParams{}) //nolint:exhaustruct
+		for i := range t.NumField() {
+			out[t.Field(i).Tag.Get(ipxeTag)] = struct{}{}
+		}
+
+		return out
+	}()
+
+	// ipxeTypes are the types iPXE can format settings with.
+	ipxeTypes = map[string]struct{}{ //nolint:gochecknoglobals
+		"string": {}, UriStringType: {}, "ipv4": {}, "ipv6": {}, "int8": {}, "int16": {}, "int32": {}, "uint8": {},
+		"uint16": {}, "uint32": {}, "hex": {}, HexHypType: {}, "hexraw": {}, "base64": {}, "uuid": {},
+		"busdevfn": {}, "dnssl": {},
+	}
+
+	// ipxeSettingNames maps params to the iPXE settings they are read from, if their names differ.
+	ipxeSettingNames = map[string]string{ //nolint:gochecknoglobals
+		// the MAC and IP addresses of the interface iPXE boots from.
+		Mac: "netX/mac",
+		Ip:  "netX/ip",
+	}
+)
+
+// IPXEParam is an iPXE setting forwarded as a query parameter by the bootstrap script.
+type IPXEParam struct {
+	// Name of the setting, e.g. `mac`.
+	Name string
+	// Type formats the setting, e.g. `hexhyp`. Settings are formatted with their own type if empty.
+	Type string
+}
+
+// String returns the query parameter expanding the setting, e.g. `mac=${netX/mac:hexhyp}`.
+func (p IPXEParam) String() string {
+	setting := p.Name
+	if s, ok := ipxeSettingNames[p.Name]; ok {
+		setting = s
+	}
+
+	if p.Type == "" {
+		return fmt.Sprintf("%s=${%s}", p.Name, setting)
+	}
+
+	return fmt.Sprintf("%s=${%s:%s}", p.Name, setting, p.Type)
+}
+
+// ParseIPXEParams parses params formatted as `<setting>[:<type>]`, e.g. `mac:hexhyp` or `memsize`. Required params,
+// i.e. `uuid` and `buildarch`, are prepended if missing.
+func ParseIPXEParams(params []string) ([]IPXEParam, error) {
+	out := make([]IPXEParam, 0, len(params)+len(requiredIPXEParams))
+	seen := make(map[string]struct{})
+
+	for _, s := range params {
+		name, typ, _ := strings.Cut(s, ":")
+
+		if _, ok := ipxeSettings[name]; !ok {
+			return nil, errors.Join(fmt.Errorf("unknown ipxe setting %q", name), ErrParsingIPXEParam)
+		}
+
+		if _, ok := ipxeTypes[typ]; typ != "" && !ok {
+			return nil, errors.Join(fmt.Errorf("unknown type %q for ipxe setting %q", typ, name), ErrParsingIPXEParam)
+		}
+
+		if _, ok := seen[name]; ok {
+			return nil, errors.Join(fmt.Errorf("ipxe setting %q is specified more than once", name), ErrParsingIPXEParam)
+		}
+
+		seen[name] = struct{}{}
+		out = append(out, IPXEParam{Name: name, Type: typ})
+	}
+
+	for i := len(requiredIPXEParams) - 1; i >= 0; i-- {
+		if _, ok := seen[requiredIPXEParams[i].Name]; !ok {
+			out = append([]IPXEParam{requiredIPXEParams[i]}, out...)
+		}
+	}
+
+	return out, nil
+}
+
 // ------------------------------------------------ LABEL SELECTORS ------------------------------------------------- //
 
 type IPXESelectors struct {
 	Buildarch string
-	UUID      uuid.UUID
+	// Platform is optional: an empty platform selects any platform.
+	Platform string
+	UUID     uuid.UUID
+
+	// Subject identifiers below are optional, and complement the UUID of subjects reporting a zero or duplicated
+	// SMBIOS UUID.
+
+	MAC      net.HardwareAddr
+	Serial   string
+	Asset    string
+	Hostname string
+}
+
+// Subject describes the subject identifiers of the selectors, e.g. `uuid "47c6da67-..." & mac "aa:bb:cc:dd:ee:ff"`.
+func (s IPXESelectors) Subject() string {
+	out := make([]string, 0)
+
+	if s.UUID != uuid.Nil {
+		out = append(out, fmt.Sprintf("%s %q", Uuid, s.UUID.String()))
+	}
+
+	if len(s.MAC) > 0 {
+		out = append(out, fmt.Sprintf("%s %q", Mac, s.MAC.String()))
+	}
+
+	for _, id := range []struct{ kind, value string }{
+		{kind: Serial, value: s.Serial},
+		{kind: Asset, value: s.Asset},
+		{kind: Hostname, value: s.Hostname},
+	} {
+		if id.value != "" {
+			out = append(out, fmt.Sprintf("%s %q", id.kind, id.value))
+		}
+	}
+
+	return strings.Join(out, " & ")
+}
+
+// ---------------------------------------------------- MACHINE ----------------------------------------------------- //
+
+// Machine are the facts about a booting machine exposed to templates, e.g. `{{ .Machine.Hostname }}`. Facts are
+// formatted as strings, and unknown facts are empty.
+type Machine struct {
+	UUID      string
+	Buildarch string
+	Platform  string
+	MAC       string
+	Serial    string
+	Asset     string
+	Hostname  string
+	IP        string
+}
+
+// NewMachine returns the facts about the machine described by the selectors and the forwarded params. Selectors take
+// precedence over params.
+func NewMachine(selectors IPXESelectors, params IpxeParams) Machine {
+	out := Machine{
+		UUID:      selectors.UUID.String(),
+		Buildarch: selectors.Buildarch,
+		Platform:  selectors.Platform,
+		Serial:    selectors.Serial,
+		Asset:     selectors.Asset,
+		Hostname:  selectors.Hostname,
+	}
+
+	if len(selectors.MAC) > 0 {
+		out.MAC = selectors.MAC.String()
+	} else if params.Mac != nil {
+		out.MAC = params.Mac.String()
+	}
+
+	if params.Ip != nil {
+		out.IP = params.Ip.String()
+	}
+
+	for _, fact := range []struct {
+		value *string
+		param *string
+	}{
+		{value: &out.Platform, param: params.Platform},
+		{value: &out.Serial, param: params.Serial},
+		{value: &out.Asset, param: params.Asset},
+		{value: &out.Hostname, param: params.Hostname},
+	} {
+		if *fact.value == "" && fact.param != nil {
+			*fact.value = *fact.param
+		}
+	}
+
+	return out
+}
+
+// --------------------------------------------------- ATTRIBUTES --------------------------------------------------- //
+
+// Attributes are the known attributes of the machine a content is resolved and transformed for, e.g. as sent to
+// webhooks. The names of the assignment and of the profile are empty if unknown.
+type Attributes struct {
+	IPXESelectors
+
+	Machine        Machine
+	AssignmentName string
+	ProfileName    string
 }
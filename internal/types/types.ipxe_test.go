@@ -0,0 +1,107 @@
+//go:build unit
+
+package types_test
+
+import (
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIPXEParams(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		actual, err := types.ParseIPXEParams([]string{"buildarch", "serial:uristring", "memsize"})
+		require.NoError(t, err)
+
+		// uuid is required thus prepended.
+		assert.Equal(t, []types.IPXEParam{
+			{Name: types.Uuid},
+			{Name: types.Buildarch},
+			{Name: types.Serial, Type: types.UriStringType},
+			{Name: types.Memsize},
+		}, actual)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		for name, params := range map[string][]string{
+			"UnknownSetting": {"unknown"},
+			"UnknownType":    {"mac:unknown"},
+			"Duplicate":      {"mac", "mac:hexhyp"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := types.ParseIPXEParams(params)
+				assert.ErrorIs(t, err, types.ErrParsingIPXEParam)
+			})
+		}
+	})
+}
+
+func TestParseIpxeParams(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		id := uuid.New()
+
+		actual, err := types.ParseIpxeParams(url.Values{
+			types.Uuid:     {id.String()},
+			types.Mac:      {"aa-bb-cc-dd-ee-ff"},
+			types.Ip:       {"10.0.0.1"},
+			types.Memsize:  {"4096"},
+			types.Serial:   {"CZ2D2507KF"},
+			types.Hostname: {""}, // unset settings are expanded to empty strings.
+		})
+		require.NoError(t, err)
+
+		require.NotNil(t, actual.UUID)
+		assert.Equal(t, id, *actual.UUID)
+		require.NotNil(t, actual.Mac)
+		assert.Equal(t, "aa:bb:cc:dd:ee:ff", actual.Mac.String())
+		require.NotNil(t, actual.Ip)
+		assert.True(t, net.IPv4(10, 0, 0, 1).Equal(*actual.Ip))
+		require.NotNil(t, actual.Memsize)
+		assert.Equal(t, int32(4096), *actual.Memsize)
+		require.NotNil(t, actual.Serial)
+		assert.Equal(t, "CZ2D2507KF", *actual.Serial)
+		assert.Nil(t, actual.Hostname)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		_, err := types.ParseIpxeParams(url.Values{types.Memsize: {"not-a-number"}})
+		assert.ErrorIs(t, err, types.ErrParsingIpxeParams)
+	})
+}
+
+func TestNewMachine(t *testing.T) {
+	id := uuid.New()
+	ip := net.IPv4(10, 0, 0, 1)
+	hostname := "from-params"
+	serial := "CZ2D2507KF"
+	mac := net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+
+	params, err := types.ParseIpxeParams(url.Values{types.Mac: {"11-22-33-44-55-66"}})
+	require.NoError(t, err)
+
+	params.Ip = &ip
+	params.Hostname = &hostname
+	params.Serial = &serial
+
+	actual := types.NewMachine(types.IPXESelectors{
+		Buildarch: "arm64",
+		UUID:      id,
+		MAC:       mac,
+		Hostname:  "node-0",
+	}, params)
+
+	// selectors take precedence over params.
+	assert.Equal(t, types.Machine{
+		UUID:      id.String(),
+		Buildarch: "arm64",
+		MAC:       "aa:bb:cc:dd:ee:ff",
+		Serial:    serial,
+		Hostname:  "node-0",
+		IP:        "10.0.0.1",
+	}, actual)
+}
@@ -1,6 +1,13 @@
 package types
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
 	"k8s.io/client-go/util/jsonpath"
 )
@@ -8,6 +15,8 @@ import (
 // ---------------------------------------------------- PROFILE ----------------------------------------------------- //
 
 type Profile struct {
+	// Name is the name of the Profile resource.
+	Name         string
 	IPXETemplate string
 
 	AdditionalContent  map[string]Content
@@ -20,6 +29,15 @@ type Content struct {
 	Name        string
 	Exposed     bool
 	ExposedUUID uuid.UUID
+	// Template renders the resolved content as a template before its post transformers.
+	Template bool
+	// CacheTTL is the duration the content is cached for. The content is not cached if zero.
+	CacheTTL time.Duration
+	// NotFoundAsEmpty resolves the content as empty if its webhook responds 404 Not Found.
+	NotFoundAsEmpty bool
+	// Hash identifies the specification of the content. It keys the cache of the content, hence any change to the
+	// specification invalidates it.
+	Hash string
 
 	PostTransformers []TransformerConfig
 	ResolverKind     ResolverKind
@@ -27,6 +45,10 @@ type Content struct {
 	Inline        string
 	ObjectRef     *ObjectRef
 	WebhookConfig *WebhookConfig
+	HTTPConfig    *HTTPConfig
+	S3Config      *S3Config
+	OCIConfig     *OCIConfig
+	GitConfig     *GitConfig
 }
 
 type ObjectRef struct {
@@ -38,13 +60,138 @@ type ObjectRef struct {
 
 	// JSONPath is optional for types that extends this struct.
 	JSONPath *jsonpath.JSONPath
+
+	// Encoding of the values matched by the JSONPaths.
+	Encoding ObjectRefEncoding
+	// JoinSeparator joins the values when a JSONPath matches several values. Matching several values is an error if it
+	// is nil.
+	JoinSeparator *string
 }
 
+type ObjectRefEncoding string
+
+const (
+	// ObjectRefDefaultEncoding decodes the data of core/v1 Secrets from base64, and reads other values as is.
+	ObjectRefDefaultEncoding ObjectRefEncoding = ""
+	ObjectRefRawEncoding     ObjectRefEncoding = "raw"
+	ObjectRefBase64Encoding  ObjectRefEncoding = "base64"
+)
+
+const (
+	DefaultWebhookTimeout     = 10 * time.Second
+	DefaultWebhookRetries     = 2
+	DefaultWebhookMaxBodySize = 16 << 20
+
+	DefaultHTTPMaxBodySize = 128 << 20
+	DefaultS3MaxBodySize   = 128 << 20
+)
+
 type WebhookConfig struct {
+	// URL of the webhook. See ParseWebhookURL.
 	URL string
+	// Headers are static headers sent with each call.
+	Headers map[string]string
+	// QueryParams are static query parameters sent with each call, in addition to the query of the URL.
+	QueryParams map[string]string
+
+	MTLSObjectRef        *MTLSObjectRef
+	BasicAuthObjectRef   *BasicAuthObjectRef
+	BearerTokenObjectRef *BearerTokenObjectRef
+
+	// Timeout of each call to the webhook. DefaultWebhookTimeout is used if it is zero.
+	Timeout time.Duration
+	// Retries of a call failing with a connection error or a 5xx status code.
+	Retries int
+	// MaxBodySize of the response in bytes. DefaultWebhookMaxBodySize is used if it is zero.
+	MaxBodySize int64
+}
+
+var ErrParsingWebhookURL = errors.New("parsing webhook url")
+
+// ParseWebhookURL parses the URL of a webhook, e.g. `https://webhook.example.com/configs?env=prod`. The scheme must be
+// `http` or `https`, and defaults to `https` if the URL has none, e.g. `webhook.example.com/configs`.
+func ParseWebhookURL(s string) (*url.URL, error) {
+	raw := s
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, errors.Join(err, ErrParsingWebhookURL)
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.Join(fmt.Errorf("url %q must be an http or https url", s), ErrParsingWebhookURL)
+	}
+
+	return u, nil
+}
+
+// HTTPConfig configures the retrieval of a plain remote file.
+type HTTPConfig struct {
+	URL string
+	// Checksum of the file formatted as `<algorithm>:<hex digest>`, e.g. `sha256:...`. Optional.
+	Checksum string
 
 	MTLSObjectRef      *MTLSObjectRef
 	BasicAuthObjectRef *BasicAuthObjectRef
+
+	// MaxBodySize of the file in bytes. DefaultHTTPMaxBodySize is used if it is zero.
+	MaxBodySize int64
+}
+
+// S3Config configures the retrieval of an object from an S3-compatible object storage.
+type S3Config struct {
+	Bucket string
+	// Key is a template of the facts about the booting machine.
+	Key            string
+	Region         string
+	Endpoint       string
+	ForcePathStyle bool
+
+	CredentialsRef *S3CredentialsObjectRef
+
+	// MaxBodySize of the object in bytes. DefaultS3MaxBodySize is used if it is zero.
+	MaxBodySize int64
+}
+
+type S3CredentialsObjectRef struct {
+	ObjectRef
+
+	AccessKeyIDJSONPath     *jsonpath.JSONPath
+	SecretAccessKeyJSONPath *jsonpath.JSONPath
+	// SessionTokenJSONPath is optional.
+	SessionTokenJSONPath *jsonpath.JSONPath
+}
+
+// OCIConfig configures the retrieval of a layer of an OCI artifact.
+type OCIConfig struct {
+	Reference OCIReference
+	// MediaType of the layer holding the content.
+	MediaType string
+	// PlainHTTP pulls the artifact over HTTP instead of HTTPS.
+	PlainHTTP bool
+
+	CredentialsRef *BasicAuthObjectRef
+}
+
+// GitConfig configures the retrieval of a file of a Git repository.
+type GitConfig struct {
+	URL string
+	// Ref is the branch, the tag or the commit the file is read at. The default branch is used if it is empty.
+	Ref  string
+	Path string
+
+	BasicAuthObjectRef *BasicAuthObjectRef
+	SSHKeyObjectRef    *SSHKeyObjectRef
+}
+
+type SSHKeyObjectRef struct {
+	ObjectRef
+
+	PrivateKeyJSONPath *jsonpath.JSONPath
+	KnownHostsJSONPath *jsonpath.JSONPath
 }
 
 type BasicAuthObjectRef struct {
@@ -54,6 +201,12 @@ type BasicAuthObjectRef struct {
 	PasswordJSONPath *jsonpath.JSONPath
 }
 
+type BearerTokenObjectRef struct {
+	ObjectRef
+
+	TokenJSONPath *jsonpath.JSONPath
+}
+
 type MTLSObjectRef struct {
 	ObjectRef
 
@@ -64,6 +217,96 @@ type MTLSObjectRef struct {
 	TLSInsecureSkipVerify bool
 }
 
+// ------------------------------------------------- OCI REFERENCE -------------------------------------------------- //
+
+const defaultOCITag = "latest"
+
+var (
+	ErrParsingOCIReference = errors.New("parsing oci reference")
+
+	ociRepositoryRegex = regexp.MustCompile(`^[a-z0-9]+([._-][a-z0-9]+)*(/[a-z0-9]+([._-][a-z0-9]+)*)*$`)
+	ociTagRegex        = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+	ociDigestRegex     = regexp.MustCompile(`^(sha256:[a-f0-9]{64}|sha512:[a-f0-9]{128})$`)
+)
+
+// OCIReference identifies an OCI artifact, e.g. `registry.example.com/ignition/worker:v1.2.0`.
+type OCIReference struct {
+	Registry   string
+	Repository string
+	// Tag is ignored if the digest is specified.
+	Tag    string
+	Digest string
+}
+
+// ParseOCIReference parses references formatted as `<registry>/<repository>[:<tag>][@<digest>]`. The registry must be
+// specified, and the tag defaults to `latest`.
+func ParseOCIReference(s string) (OCIReference, error) {
+	out := OCIReference{}
+
+	name, digest, ok := strings.Cut(s, "@")
+	if ok {
+		if !IsOCIDigest(digest) {
+			return OCIReference{}, errors.Join(fmt.Errorf("invalid digest %q", digest), ErrParsingOCIReference)
+		}
+
+		out.Digest = digest
+	}
+
+	registry, repository, ok := strings.Cut(name, "/")
+	if !ok || registry == "" {
+		return OCIReference{}, errors.Join(fmt.Errorf("registry of %q must be specified", s), ErrParsingOCIReference)
+	}
+
+	// the tag is separated by the last colon of the last path component, as registries may specify a port.
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository, out.Tag = repository[:i], repository[i+1:]
+
+		if !ociTagRegex.MatchString(out.Tag) {
+			return OCIReference{}, errors.Join(fmt.Errorf("invalid tag %q", out.Tag), ErrParsingOCIReference)
+		}
+	}
+
+	if !ociRepositoryRegex.MatchString(repository) {
+		return OCIReference{}, errors.Join(fmt.Errorf("invalid repository %q", repository), ErrParsingOCIReference)
+	}
+
+	out.Registry = registry
+	out.Repository = repository
+
+	if out.Tag == "" && out.Digest == "" {
+		out.Tag = defaultOCITag
+	}
+
+	return out, nil
+}
+
+// IsOCIDigest reports whether the digest is a sha256 or a sha512 digest, e.g. `sha256:<hex>`.
+func IsOCIDigest(digest string) bool {
+	return ociDigestRegex.MatchString(digest)
+}
+
+// Reference returns the digest of the artifact if specified, or its tag otherwise.
+func (r OCIReference) Reference() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+
+	return r.Tag
+}
+
+func (r OCIReference) String() string {
+	out := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		out += ":" + r.Tag
+	}
+
+	if r.Digest != "" {
+		out += "@" + r.Digest
+	}
+
+	return out
+}
+
 // --------------------------------------------------- RESOLVER ----------------------------------------------------- //
 
 type ResolverKind int
@@ -72,6 +315,10 @@ const (
 	InlineResolverKind ResolverKind = iota
 	ObjectRefResolverKind
 	WebhookResolverKind
+	HTTPResolverKind
+	S3ResolverKind
+	OCIResolverKind
+	GitResolverKind
 )
 
 // -------------------------------------------------- TRANSFORMER --------------------------------------------------- //
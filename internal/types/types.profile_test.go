@@ -0,0 +1,95 @@
+//go:build unit
+
+package types_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOCIReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	t.Run("Success", func(t *testing.T) {
+		for _, tc := range []struct {
+			input    string
+			expected types.OCIReference
+		}{
+			{
+				input:    "ghcr.io/example/ignition:v1.2.0",
+				expected: types.OCIReference{Registry: "ghcr.io", Repository: "example/ignition", Tag: "v1.2.0"},
+			},
+			{
+				// the tag defaults to latest.
+				input:    "localhost:5000/ignition",
+				expected: types.OCIReference{Registry: "localhost:5000", Repository: "ignition", Tag: "latest"},
+			},
+			{
+				input:    "localhost:5000/ignition@" + digest,
+				expected: types.OCIReference{Registry: "localhost:5000", Repository: "ignition", Digest: digest},
+			},
+			{
+				input: "ghcr.io/example/ignition:v1@" + digest,
+				expected: types.OCIReference{
+					Registry:   "ghcr.io",
+					Repository: "example/ignition",
+					Tag:        "v1",
+					Digest:     digest,
+				},
+			},
+		} {
+			t.Run(tc.input, func(t *testing.T) {
+				actual, err := types.ParseOCIReference(tc.input)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, actual)
+				assert.Equal(t, tc.input, strings.TrimSuffix(actual.String(), ":latest"))
+			})
+		}
+
+		ref, err := types.ParseOCIReference("ghcr.io/example/ignition:v1@" + digest)
+		require.NoError(t, err)
+		assert.Equal(t, digest, ref.Reference())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		for name, input := range map[string]string{
+			"MissingRegistry":   "ignition",
+			"UppercaseRepo":     "ghcr.io/Example/ignition",
+			"InvalidTag":        "ghcr.io/example/ignition:-v1",
+			"InvalidDigest":     "ghcr.io/example/ignition@sha256:abc",
+			"UnsupportedDigest": "ghcr.io/example/ignition@md5:" + strings.Repeat("a", 32),
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := types.ParseOCIReference(input)
+				assert.ErrorIs(t, err, types.ErrParsingOCIReference)
+			})
+		}
+	})
+}
+
+func TestParseWebhookURL(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		for input, expected := range map[string]string{
+			// the scheme defaults to https.
+			"localhost:30443/s3-test":                   "https://localhost:30443/s3-test",
+			"http://resolver.ipxer.svc:8080/configs":    "http://resolver.ipxer.svc:8080/configs",
+			"https://webhook.example.com/c?env=prod":    "https://webhook.example.com/c?env=prod",
+			"https://webhook.example.com/a/b%2Fc?x=1#y": "https://webhook.example.com/a/b%2Fc?x=1#y",
+		} {
+			actual, err := types.ParseWebhookURL(input)
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual.String())
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		for _, input := range []string{"ftp://example.com/configs", "https:///configs", "https://exa mple.com"} {
+			_, err := types.ParseWebhookURL(input)
+			assert.ErrorIs(t, err, types.ErrParsingWebhookURL, input)
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"net"
 	"net/http"
 	"strings"
 	"testing"
@@ -41,10 +42,14 @@ func (f *Fake) Resolve( //nolint:ireturn
 	return f.expectations[counter](ctx, request)
 }
 
+// Start listens before returning, so requests sent right after Start are not refused.
 func (f *Fake) Start() *Fake {
+	listener, err := net.Listen("tcp", f.Server.Addr)
+	require.NoError(f.t, err)
+
 	go func() {
-		if err := f.Server.ListenAndServeTLS("", ""); !errors.Is(err, http.ErrServerClosed) {
-			require.NoError(f.t, err)
+		if err := f.Server.ServeTLS(listener, "", ""); !errors.Is(err, http.ErrServerClosed) {
+			assert.NoError(f.t, err)
 		}
 	}()
 
@@ -0,0 +1,141 @@
+package kubeutil
+
+import (
+	"errors"
+	"os"
+
+	ipxerv1alpha1 "github.com/alexandremahdhaoui/ipxer/pkg/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// KubeconfigFromServiceAccount can be set as a kubeconfig path to build the rest config from the pod's service account.
+const KubeconfigFromServiceAccount = ">>> Kubeconfig From Service Account"
+
+var (
+	errNewRestConfig = errors.New("creating kube rest config")
+	errNewScheme     = errors.New("creating scheme")
+	errNewClient     = errors.New("creating kube client")
+	errNewCache      = errors.New("creating informer cache")
+)
+
+func NewRestConfig(kubeconfigPath string) (*rest.Config, error) {
+	if kubeconfigPath == KubeconfigFromServiceAccount {
+		restConfig, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, errors.Join(err, errNewRestConfig)
+		}
+
+		return restConfig, nil
+	}
+
+	b, err := os.ReadFile(kubeconfigPath)
+	if err != nil {
+		return nil, errors.Join(err, errNewRestConfig)
+	}
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(b)
+	if err != nil {
+		return nil, errors.Join(err, errNewRestConfig)
+	}
+
+	return restConfig, nil
+}
+
+// NewScheme returns a scheme knowing about core/v1 and ipxer's v1alpha1 types.
+func NewScheme() (*runtime.Scheme, error) {
+	sch := runtime.NewScheme()
+
+	if err := corev1.AddToScheme(sch); err != nil {
+		return nil, errors.Join(err, errNewScheme)
+	}
+
+	if err := ipxerv1alpha1.AddToScheme(sch); err != nil {
+		return nil, errors.Join(err, errNewScheme)
+	}
+
+	return sch, nil
+}
+
+func NewClient(restConfig *rest.Config) (client.Client, error) { //nolint:ireturn
+	sch, err := NewScheme()
+	if err != nil {
+		return nil, errors.Join(err, errNewClient)
+	}
+
+	cl, err := client.New(restConfig, client.Options{Scheme: sch}) //nolint:exhaustruct
+	if err != nil {
+		return nil, errors.Join(err, errNewClient)
+	}
+
+	return cl, nil
+}
+
+// NewCache returns an informer-backed cache watching the specified namespaces. The cache must be started, and its
+// informers are created lazily unless requested before it starts, e.g. by indexing fields.
+func NewCache(restConfig *rest.Config, namespaces ...string) (cache.Cache, error) { //nolint:ireturn
+	sch, err := NewScheme()
+	if err != nil {
+		return nil, errors.Join(err, errNewCache)
+	}
+
+	defaultNamespaces := make(map[string]cache.Config)
+	for _, ns := range namespaces {
+		defaultNamespaces[ns] = cache.Config{} //nolint:exhaustruct
+	}
+
+	c, err := cache.New(restConfig, cache.Options{ //nolint:exhaustruct
+		Scheme:            sch,
+		DefaultNamespaces: defaultNamespaces,
+	})
+	if err != nil {
+		return nil, errors.Join(err, errNewCache)
+	}
+
+	return c, nil
+}
+
+// NewConfigMapCache returns an informer-backed cache watching a single ConfigMap. The informer of the ConfigMap is
+// created lazily unless requested before the cache starts.
+func NewConfigMapCache(restConfig *rest.Config, namespace, name string) (cache.Cache, error) { //nolint:ireturn
+	sch, err := NewScheme()
+	if err != nil {
+		return nil, errors.Join(err, errNewCache)
+	}
+
+	c, err := cache.New(restConfig, cache.Options{ //nolint:exhaustruct
+		Scheme:            sch,
+		DefaultNamespaces: map[string]cache.Config{namespace: {}}, //nolint:exhaustruct
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.ConfigMap{}: {Field: fields.OneTermEqualSelector("metadata.name", name)}, //nolint:exhaustruct
+		},
+	})
+	if err != nil {
+		return nil, errors.Join(err, errNewCache)
+	}
+
+	return c, nil
+}
+
+// NewCachedClient returns a client reading objects from the cache, and writing them through the API server.
+func NewCachedClient(restConfig *rest.Config, reader client.Reader) (client.Client, error) { //nolint:ireturn
+	sch, err := NewScheme()
+	if err != nil {
+		return nil, errors.Join(err, errNewClient)
+	}
+
+	cl, err := client.New(restConfig, client.Options{ //nolint:exhaustruct
+		Scheme: sch,
+		Cache:  &client.CacheOptions{Reader: reader}, //nolint:exhaustruct
+	})
+	if err != nil {
+		return nil, errors.Join(err, errNewClient)
+	}
+
+	return cl, nil
+}
@@ -79,27 +79,27 @@ func (_c *MockAssignment_FindBySelectors_Call) RunAndReturn(run func(context.Con
 	return _c
 }
 
-// FindDefaultByBuildarch provides a mock function with given fields: ctx, buildarch
-func (_m *MockAssignment) FindDefaultByBuildarch(ctx context.Context, buildarch string) (types.Assignment, error) {
-	ret := _m.Called(ctx, buildarch)
+// FindDefault provides a mock function with given fields: ctx, selectors
+func (_m *MockAssignment) FindDefault(ctx context.Context, selectors types.IPXESelectors) (types.Assignment, error) {
+	ret := _m.Called(ctx, selectors)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindDefaultByBuildarch")
+		panic("no return value specified for FindDefault")
 	}
 
 	var r0 types.Assignment
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (types.Assignment, error)); ok {
-		return rf(ctx, buildarch)
+	if rf, ok := ret.Get(0).(func(context.Context, types.IPXESelectors) (types.Assignment, error)); ok {
+		return rf(ctx, selectors)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) types.Assignment); ok {
-		r0 = rf(ctx, buildarch)
+	if rf, ok := ret.Get(0).(func(context.Context, types.IPXESelectors) types.Assignment); ok {
+		r0 = rf(ctx, selectors)
 	} else {
 		r0 = ret.Get(0).(types.Assignment)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, buildarch)
+	if rf, ok := ret.Get(1).(func(context.Context, types.IPXESelectors) error); ok {
+		r1 = rf(ctx, selectors)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -107,31 +107,149 @@ func (_m *MockAssignment) FindDefaultByBuildarch(ctx context.Context, buildarch
 	return r0, r1
 }
 
-// MockAssignment_FindDefaultByBuildarch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindDefaultByBuildarch'
-type MockAssignment_FindDefaultByBuildarch_Call struct {
+// MockAssignment_FindDefault_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindDefault'
+type MockAssignment_FindDefault_Call struct {
 	*mock.Call
 }
 
-// FindDefaultByBuildarch is a helper method to define mock.On call
+// FindDefault is a helper method to define mock.On call
 //   - ctx context.Context
-//   - buildarch string
-func (_e *MockAssignment_Expecter) FindDefaultByBuildarch(ctx interface{}, buildarch interface{}) *MockAssignment_FindDefaultByBuildarch_Call {
-	return &MockAssignment_FindDefaultByBuildarch_Call{Call: _e.mock.On("FindDefaultByBuildarch", ctx, buildarch)}
+//   - selectors types.IPXESelectors
+func (_e *MockAssignment_Expecter) FindDefault(ctx interface{}, selectors interface{}) *MockAssignment_FindDefault_Call {
+	return &MockAssignment_FindDefault_Call{Call: _e.mock.On("FindDefault", ctx, selectors)}
 }
 
-func (_c *MockAssignment_FindDefaultByBuildarch_Call) Run(run func(ctx context.Context, buildarch string)) *MockAssignment_FindDefaultByBuildarch_Call {
+func (_c *MockAssignment_FindDefault_Call) Run(run func(ctx context.Context, selectors types.IPXESelectors)) *MockAssignment_FindDefault_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(types.IPXESelectors))
+	})
+	return _c
+}
+
+func (_c *MockAssignment_FindDefault_Call) Return(_a0 types.Assignment, _a1 error) *MockAssignment_FindDefault_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAssignment_FindDefault_Call) RunAndReturn(run func(context.Context, types.IPXESelectors) (types.Assignment, error)) *MockAssignment_FindDefault_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListBySelectors provides a mock function with given fields: ctx, selectors
+func (_m *MockAssignment) ListBySelectors(ctx context.Context, selectors types.IPXESelectors) ([]types.Assignment, error) {
+	ret := _m.Called(ctx, selectors)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListBySelectors")
+	}
+
+	var r0 []types.Assignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.IPXESelectors) ([]types.Assignment, error)); ok {
+		return rf(ctx, selectors)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.IPXESelectors) []types.Assignment); ok {
+		r0 = rf(ctx, selectors)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Assignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.IPXESelectors) error); ok {
+		r1 = rf(ctx, selectors)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAssignment_ListBySelectors_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListBySelectors'
+type MockAssignment_ListBySelectors_Call struct {
+	*mock.Call
+}
+
+// ListBySelectors is a helper method to define mock.On call
+//   - ctx context.Context
+//   - selectors types.IPXESelectors
+func (_e *MockAssignment_Expecter) ListBySelectors(ctx interface{}, selectors interface{}) *MockAssignment_ListBySelectors_Call {
+	return &MockAssignment_ListBySelectors_Call{Call: _e.mock.On("ListBySelectors", ctx, selectors)}
+}
+
+func (_c *MockAssignment_ListBySelectors_Call) Run(run func(ctx context.Context, selectors types.IPXESelectors)) *MockAssignment_ListBySelectors_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(types.IPXESelectors))
+	})
+	return _c
+}
+
+func (_c *MockAssignment_ListBySelectors_Call) Return(_a0 []types.Assignment, _a1 error) *MockAssignment_ListBySelectors_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAssignment_ListBySelectors_Call) RunAndReturn(run func(context.Context, types.IPXESelectors) ([]types.Assignment, error)) *MockAssignment_ListBySelectors_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListDefault provides a mock function with given fields: ctx, selectors
+func (_m *MockAssignment) ListDefault(ctx context.Context, selectors types.IPXESelectors) ([]types.Assignment, error) {
+	ret := _m.Called(ctx, selectors)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDefault")
+	}
+
+	var r0 []types.Assignment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.IPXESelectors) ([]types.Assignment, error)); ok {
+		return rf(ctx, selectors)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.IPXESelectors) []types.Assignment); ok {
+		r0 = rf(ctx, selectors)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Assignment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.IPXESelectors) error); ok {
+		r1 = rf(ctx, selectors)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAssignment_ListDefault_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListDefault'
+type MockAssignment_ListDefault_Call struct {
+	*mock.Call
+}
+
+// ListDefault is a helper method to define mock.On call
+//   - ctx context.Context
+//   - selectors types.IPXESelectors
+func (_e *MockAssignment_Expecter) ListDefault(ctx interface{}, selectors interface{}) *MockAssignment_ListDefault_Call {
+	return &MockAssignment_ListDefault_Call{Call: _e.mock.On("ListDefault", ctx, selectors)}
+}
+
+func (_c *MockAssignment_ListDefault_Call) Run(run func(ctx context.Context, selectors types.IPXESelectors)) *MockAssignment_ListDefault_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(types.IPXESelectors))
 	})
 	return _c
 }
 
-func (_c *MockAssignment_FindDefaultByBuildarch_Call) Return(_a0 types.Assignment, _a1 error) *MockAssignment_FindDefaultByBuildarch_Call {
+func (_c *MockAssignment_ListDefault_Call) Return(_a0 []types.Assignment, _a1 error) *MockAssignment_ListDefault_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockAssignment_FindDefaultByBuildarch_Call) RunAndReturn(run func(context.Context, string) (types.Assignment, error)) *MockAssignment_FindDefaultByBuildarch_Call {
+func (_c *MockAssignment_ListDefault_Call) RunAndReturn(run func(context.Context, types.IPXESelectors) ([]types.Assignment, error)) *MockAssignment_ListDefault_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,96 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mockadapter
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockBootloader is an autogenerated mock type for the Bootloader type
+type MockBootloader struct {
+	mock.Mock
+}
+
+type MockBootloader_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockBootloader) EXPECT() *MockBootloader_Expecter {
+	return &MockBootloader_Expecter{mock: &_m.Mock}
+}
+
+// Get provides a mock function with given fields: ctx, buildarch, filename
+func (_m *MockBootloader) Get(ctx context.Context, buildarch string, filename string) ([]byte, error) {
+	ret := _m.Called(ctx, buildarch, filename)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]byte, error)); ok {
+		return rf(ctx, buildarch, filename)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []byte); ok {
+		r0 = rf(ctx, buildarch, filename)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, buildarch, filename)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockBootloader_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockBootloader_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - buildarch string
+//   - filename string
+func (_e *MockBootloader_Expecter) Get(ctx interface{}, buildarch interface{}, filename interface{}) *MockBootloader_Get_Call {
+	return &MockBootloader_Get_Call{Call: _e.mock.On("Get", ctx, buildarch, filename)}
+}
+
+func (_c *MockBootloader_Get_Call) Run(run func(ctx context.Context, buildarch string, filename string)) *MockBootloader_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockBootloader_Get_Call) Return(_a0 []byte, _a1 error) *MockBootloader_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockBootloader_Get_Call) RunAndReturn(run func(context.Context, string, string) ([]byte, error)) *MockBootloader_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockBootloader creates a new instance of MockBootloader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockBootloader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockBootloader {
+	mock := &MockBootloader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,219 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mockadapter
+
+import (
+	context "context"
+	http "net/http"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "github.com/alexandremahdhaoui/ipxer/internal/types"
+)
+
+// MockCredentialCache is an autogenerated mock type for the CredentialCache type
+type MockCredentialCache struct {
+	mock.Mock
+}
+
+type MockCredentialCache_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockCredentialCache) EXPECT() *MockCredentialCache_Expecter {
+	return &MockCredentialCache_Expecter{mock: &_m.Mock}
+}
+
+// BasicAuth provides a mock function with given fields: ctx, ref
+func (_m *MockCredentialCache) BasicAuth(ctx context.Context, ref *types.BasicAuthObjectRef) (string, string, error) {
+	ret := _m.Called(ctx, ref)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BasicAuth")
+	}
+
+	var r0 string
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *types.BasicAuthObjectRef) (string, string, error)); ok {
+		return rf(ctx, ref)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *types.BasicAuthObjectRef) string); ok {
+		r0 = rf(ctx, ref)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *types.BasicAuthObjectRef) string); ok {
+		r1 = rf(ctx, ref)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *types.BasicAuthObjectRef) error); ok {
+		r2 = rf(ctx, ref)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockCredentialCache_BasicAuth_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BasicAuth'
+type MockCredentialCache_BasicAuth_Call struct {
+	*mock.Call
+}
+
+// BasicAuth is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ref *types.BasicAuthObjectRef
+func (_e *MockCredentialCache_Expecter) BasicAuth(ctx interface{}, ref interface{}) *MockCredentialCache_BasicAuth_Call {
+	return &MockCredentialCache_BasicAuth_Call{Call: _e.mock.On("BasicAuth", ctx, ref)}
+}
+
+func (_c *MockCredentialCache_BasicAuth_Call) Run(run func(ctx context.Context, ref *types.BasicAuthObjectRef)) *MockCredentialCache_BasicAuth_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*types.BasicAuthObjectRef))
+	})
+	return _c
+}
+
+func (_c *MockCredentialCache_BasicAuth_Call) Return(_a0 string, _a1 string, _a2 error) *MockCredentialCache_BasicAuth_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockCredentialCache_BasicAuth_Call) RunAndReturn(run func(context.Context, *types.BasicAuthObjectRef) (string, string, error)) *MockCredentialCache_BasicAuth_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// BearerToken provides a mock function with given fields: ctx, ref
+func (_m *MockCredentialCache) BearerToken(ctx context.Context, ref *types.BearerTokenObjectRef) (string, error) {
+	ret := _m.Called(ctx, ref)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BearerToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *types.BearerTokenObjectRef) (string, error)); ok {
+		return rf(ctx, ref)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *types.BearerTokenObjectRef) string); ok {
+		r0 = rf(ctx, ref)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *types.BearerTokenObjectRef) error); ok {
+		r1 = rf(ctx, ref)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCredentialCache_BearerToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BearerToken'
+type MockCredentialCache_BearerToken_Call struct {
+	*mock.Call
+}
+
+// BearerToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ref *types.BearerTokenObjectRef
+func (_e *MockCredentialCache_Expecter) BearerToken(ctx interface{}, ref interface{}) *MockCredentialCache_BearerToken_Call {
+	return &MockCredentialCache_BearerToken_Call{Call: _e.mock.On("BearerToken", ctx, ref)}
+}
+
+func (_c *MockCredentialCache_BearerToken_Call) Run(run func(ctx context.Context, ref *types.BearerTokenObjectRef)) *MockCredentialCache_BearerToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*types.BearerTokenObjectRef))
+	})
+	return _c
+}
+
+func (_c *MockCredentialCache_BearerToken_Call) Return(_a0 string, _a1 error) *MockCredentialCache_BearerToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCredentialCache_BearerToken_Call) RunAndReturn(run func(context.Context, *types.BearerTokenObjectRef) (string, error)) *MockCredentialCache_BearerToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Transport provides a mock function with given fields: ctx, ref
+func (_m *MockCredentialCache) Transport(ctx context.Context, ref *types.MTLSObjectRef) (http.RoundTripper, error) {
+	ret := _m.Called(ctx, ref)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Transport")
+	}
+
+	var r0 http.RoundTripper
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *types.MTLSObjectRef) (http.RoundTripper, error)); ok {
+		return rf(ctx, ref)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *types.MTLSObjectRef) http.RoundTripper); ok {
+		r0 = rf(ctx, ref)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(http.RoundTripper)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *types.MTLSObjectRef) error); ok {
+		r1 = rf(ctx, ref)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCredentialCache_Transport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Transport'
+type MockCredentialCache_Transport_Call struct {
+	*mock.Call
+}
+
+// Transport is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ref *types.MTLSObjectRef
+func (_e *MockCredentialCache_Expecter) Transport(ctx interface{}, ref interface{}) *MockCredentialCache_Transport_Call {
+	return &MockCredentialCache_Transport_Call{Call: _e.mock.On("Transport", ctx, ref)}
+}
+
+func (_c *MockCredentialCache_Transport_Call) Run(run func(ctx context.Context, ref *types.MTLSObjectRef)) *MockCredentialCache_Transport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*types.MTLSObjectRef))
+	})
+	return _c
+}
+
+func (_c *MockCredentialCache_Transport_Call) Return(_a0 http.RoundTripper, _a1 error) *MockCredentialCache_Transport_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCredentialCache_Transport_Call) RunAndReturn(run func(context.Context, *types.MTLSObjectRef) (http.RoundTripper, error)) *MockCredentialCache_Transport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockCredentialCache creates a new instance of MockCredentialCache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCredentialCache(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCredentialCache {
+	mock := &MockCredentialCache{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,154 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mockadapter
+
+import (
+	context "context"
+
+	types "github.com/alexandremahdhaoui/ipxer/internal/types"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockGitResolver is an autogenerated mock type for the GitResolver type
+type MockGitResolver struct {
+	mock.Mock
+}
+
+type MockGitResolver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockGitResolver) EXPECT() *MockGitResolver_Expecter {
+	return &MockGitResolver_Expecter{mock: &_m.Mock}
+}
+
+// Resolve provides a mock function with given fields: ctx, content, attributes
+func (_m *MockGitResolver) Resolve(ctx context.Context, content types.Content, attributes types.Attributes) ([]byte, error) {
+	ret := _m.Called(ctx, content, attributes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resolve")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.Content, types.Attributes) ([]byte, error)); ok {
+		return rf(ctx, content, attributes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.Content, types.Attributes) []byte); ok {
+		r0 = rf(ctx, content, attributes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.Content, types.Attributes) error); ok {
+		r1 = rf(ctx, content, attributes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGitResolver_Resolve_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Resolve'
+type MockGitResolver_Resolve_Call struct {
+	*mock.Call
+}
+
+// Resolve is a helper method to define mock.On call
+//   - ctx context.Context
+//   - content types.Content
+//   - attributes types.Attributes
+func (_e *MockGitResolver_Expecter) Resolve(ctx interface{}, content interface{}, attributes interface{}) *MockGitResolver_Resolve_Call {
+	return &MockGitResolver_Resolve_Call{Call: _e.mock.On("Resolve", ctx, content, attributes)}
+}
+
+func (_c *MockGitResolver_Resolve_Call) Run(run func(ctx context.Context, content types.Content, attributes types.Attributes)) *MockGitResolver_Resolve_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(types.Content), args[2].(types.Attributes))
+	})
+	return _c
+}
+
+func (_c *MockGitResolver_Resolve_Call) Return(_a0 []byte, _a1 error) *MockGitResolver_Resolve_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGitResolver_Resolve_Call) RunAndReturn(run func(context.Context, types.Content, types.Attributes) ([]byte, error)) *MockGitResolver_Resolve_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Revision provides a mock function with given fields: ctx, content
+func (_m *MockGitResolver) Revision(ctx context.Context, content types.Content) (string, error) {
+	ret := _m.Called(ctx, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revision")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.Content) (string, error)); ok {
+		return rf(ctx, content)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.Content) string); ok {
+		r0 = rf(ctx, content)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.Content) error); ok {
+		r1 = rf(ctx, content)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockGitResolver_Revision_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Revision'
+type MockGitResolver_Revision_Call struct {
+	*mock.Call
+}
+
+// Revision is a helper method to define mock.On call
+//   - ctx context.Context
+//   - content types.Content
+func (_e *MockGitResolver_Expecter) Revision(ctx interface{}, content interface{}) *MockGitResolver_Revision_Call {
+	return &MockGitResolver_Revision_Call{Call: _e.mock.On("Revision", ctx, content)}
+}
+
+func (_c *MockGitResolver_Revision_Call) Run(run func(ctx context.Context, content types.Content)) *MockGitResolver_Revision_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(types.Content))
+	})
+	return _c
+}
+
+func (_c *MockGitResolver_Revision_Call) Return(_a0 string, _a1 error) *MockGitResolver_Revision_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockGitResolver_Revision_Call) RunAndReturn(run func(context.Context, types.Content) (string, error)) *MockGitResolver_Revision_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockGitResolver creates a new instance of MockGitResolver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockGitResolver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockGitResolver {
+	mock := &MockGitResolver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -25,7 +25,7 @@ func (_m *MockObjectRefResolver) EXPECT() *MockObjectRefResolver_Expecter {
 }
 
 // Resolve provides a mock function with given fields: ctx, content, attributes
-func (_m *MockObjectRefResolver) Resolve(ctx context.Context, content types.Content, attributes types.IPXESelectors) ([]byte, error) {
+func (_m *MockObjectRefResolver) Resolve(ctx context.Context, content types.Content, attributes types.Attributes) ([]byte, error) {
 	ret := _m.Called(ctx, content, attributes)
 
 	if len(ret) == 0 {
@@ -34,10 +34,10 @@ func (_m *MockObjectRefResolver) Resolve(ctx context.Context, content types.Cont
 
 	var r0 []byte
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, types.Content, types.IPXESelectors) ([]byte, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, types.Content, types.Attributes) ([]byte, error)); ok {
 		return rf(ctx, content, attributes)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, types.Content, types.IPXESelectors) []byte); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, types.Content, types.Attributes) []byte); ok {
 		r0 = rf(ctx, content, attributes)
 	} else {
 		if ret.Get(0) != nil {
@@ -45,7 +45,7 @@ func (_m *MockObjectRefResolver) Resolve(ctx context.Context, content types.Cont
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, types.Content, types.IPXESelectors) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, types.Content, types.Attributes) error); ok {
 		r1 = rf(ctx, content, attributes)
 	} else {
 		r1 = ret.Error(1)
@@ -62,14 +62,14 @@ type MockObjectRefResolver_Resolve_Call struct {
 // Resolve is a helper method to define mock.On call
 //   - ctx context.Context
 //   - content types.Content
-//   - attributes types.IPXESelectors
+//   - attributes types.Attributes
 func (_e *MockObjectRefResolver_Expecter) Resolve(ctx interface{}, content interface{}, attributes interface{}) *MockObjectRefResolver_Resolve_Call {
 	return &MockObjectRefResolver_Resolve_Call{Call: _e.mock.On("Resolve", ctx, content, attributes)}
 }
 
-func (_c *MockObjectRefResolver_Resolve_Call) Run(run func(ctx context.Context, content types.Content, attributes types.IPXESelectors)) *MockObjectRefResolver_Resolve_Call {
+func (_c *MockObjectRefResolver_Resolve_Call) Run(run func(ctx context.Context, content types.Content, attributes types.Attributes)) *MockObjectRefResolver_Resolve_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(types.Content), args[2].(types.IPXESelectors))
+		run(args[0].(context.Context), args[1].(types.Content), args[2].(types.Attributes))
 	})
 	return _c
 }
@@ -79,7 +79,7 @@ func (_c *MockObjectRefResolver_Resolve_Call) Return(_a0 []byte, _a1 error) *Moc
 	return _c
 }
 
-func (_c *MockObjectRefResolver_Resolve_Call) RunAndReturn(run func(context.Context, types.Content, types.IPXESelectors) ([]byte, error)) *MockObjectRefResolver_Resolve_Call {
+func (_c *MockObjectRefResolver_Resolve_Call) RunAndReturn(run func(context.Context, types.Content, types.Attributes) ([]byte, error)) *MockObjectRefResolver_Resolve_Call {
 	_c.Call.Return(run)
 	return _c
 }
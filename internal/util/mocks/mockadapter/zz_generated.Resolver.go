@@ -23,7 +23,7 @@ func (_m *MockResolver) EXPECT() *MockResolver_Expecter {
 }
 
 // Resolve provides a mock function with given fields: ctx, content, attributes
-func (_m *MockResolver) Resolve(ctx context.Context, content types.Content, attributes types.IPXESelectors) ([]byte, error) {
+func (_m *MockResolver) Resolve(ctx context.Context, content types.Content, attributes types.Attributes) ([]byte, error) {
 	ret := _m.Called(ctx, content, attributes)
 
 	if len(ret) == 0 {
@@ -32,10 +32,10 @@ func (_m *MockResolver) Resolve(ctx context.Context, content types.Content, attr
 
 	var r0 []byte
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, types.Content, types.IPXESelectors) ([]byte, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, types.Content, types.Attributes) ([]byte, error)); ok {
 		return rf(ctx, content, attributes)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, types.Content, types.IPXESelectors) []byte); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, types.Content, types.Attributes) []byte); ok {
 		r0 = rf(ctx, content, attributes)
 	} else {
 		if ret.Get(0) != nil {
@@ -43,7 +43,7 @@ func (_m *MockResolver) Resolve(ctx context.Context, content types.Content, attr
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, types.Content, types.IPXESelectors) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, types.Content, types.Attributes) error); ok {
 		r1 = rf(ctx, content, attributes)
 	} else {
 		r1 = ret.Error(1)
@@ -60,14 +60,14 @@ type MockResolver_Resolve_Call struct {
 // Resolve is a helper method to define mock.On call
 //   - ctx context.Context
 //   - content types.Content
-//   - attributes types.IPXESelectors
+//   - attributes types.Attributes
 func (_e *MockResolver_Expecter) Resolve(ctx interface{}, content interface{}, attributes interface{}) *MockResolver_Resolve_Call {
 	return &MockResolver_Resolve_Call{Call: _e.mock.On("Resolve", ctx, content, attributes)}
 }
 
-func (_c *MockResolver_Resolve_Call) Run(run func(ctx context.Context, content types.Content, attributes types.IPXESelectors)) *MockResolver_Resolve_Call {
+func (_c *MockResolver_Resolve_Call) Run(run func(ctx context.Context, content types.Content, attributes types.Attributes)) *MockResolver_Resolve_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(types.Content), args[2].(types.IPXESelectors))
+		run(args[0].(context.Context), args[1].(types.Content), args[2].(types.Attributes))
 	})
 	return _c
 }
@@ -77,7 +77,7 @@ func (_c *MockResolver_Resolve_Call) Return(_a0 []byte, _a1 error) *MockResolver
 	return _c
 }
 
-func (_c *MockResolver_Resolve_Call) RunAndReturn(run func(context.Context, types.Content, types.IPXESelectors) ([]byte, error)) *MockResolver_Resolve_Call {
+func (_c *MockResolver_Resolve_Call) RunAndReturn(run func(context.Context, types.Content, types.Attributes) ([]byte, error)) *MockResolver_Resolve_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,99 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mockadapter
+
+import (
+	context "context"
+
+	adapter "github.com/alexandremahdhaoui/ipxer/internal/adapter"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "github.com/alexandremahdhaoui/ipxer/internal/types"
+)
+
+// MockWebhookClient is an autogenerated mock type for the WebhookClient type
+type MockWebhookClient struct {
+	mock.Mock
+}
+
+type MockWebhookClient_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockWebhookClient) EXPECT() *MockWebhookClient_Expecter {
+	return &MockWebhookClient_Expecter{mock: &_m.Mock}
+}
+
+// Doer provides a mock function with given fields: ctx, cfg
+func (_m *MockWebhookClient) Doer(ctx context.Context, cfg types.WebhookConfig) (adapter.HTTPRequestDoer, error) {
+	ret := _m.Called(ctx, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Doer")
+	}
+
+	var r0 adapter.HTTPRequestDoer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.WebhookConfig) (adapter.HTTPRequestDoer, error)); ok {
+		return rf(ctx, cfg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.WebhookConfig) adapter.HTTPRequestDoer); ok {
+		r0 = rf(ctx, cfg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(adapter.HTTPRequestDoer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.WebhookConfig) error); ok {
+		r1 = rf(ctx, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockWebhookClient_Doer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Doer'
+type MockWebhookClient_Doer_Call struct {
+	*mock.Call
+}
+
+// Doer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg types.WebhookConfig
+func (_e *MockWebhookClient_Expecter) Doer(ctx interface{}, cfg interface{}) *MockWebhookClient_Doer_Call {
+	return &MockWebhookClient_Doer_Call{Call: _e.mock.On("Doer", ctx, cfg)}
+}
+
+func (_c *MockWebhookClient_Doer_Call) Run(run func(ctx context.Context, cfg types.WebhookConfig)) *MockWebhookClient_Doer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(types.WebhookConfig))
+	})
+	return _c
+}
+
+func (_c *MockWebhookClient_Doer_Call) Return(_a0 adapter.HTTPRequestDoer, _a1 error) *MockWebhookClient_Doer_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockWebhookClient_Doer_Call) RunAndReturn(run func(context.Context, types.WebhookConfig) (adapter.HTTPRequestDoer, error)) *MockWebhookClient_Doer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockWebhookClient creates a new instance of MockWebhookClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockWebhookClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockWebhookClient {
+	mock := &MockWebhookClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
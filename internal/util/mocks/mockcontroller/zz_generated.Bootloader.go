@@ -0,0 +1,95 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mockcontroller
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockBootloader is an autogenerated mock type for the Bootloader type
+type MockBootloader struct {
+	mock.Mock
+}
+
+type MockBootloader_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockBootloader) EXPECT() *MockBootloader_Expecter {
+	return &MockBootloader_Expecter{mock: &_m.Mock}
+}
+
+// Get provides a mock function with given fields: ctx, path
+func (_m *MockBootloader) Get(ctx context.Context, path string) ([]byte, error) {
+	ret := _m.Called(ctx, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
+		return rf(ctx, path)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, path)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockBootloader_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockBootloader_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - path string
+func (_e *MockBootloader_Expecter) Get(ctx interface{}, path interface{}) *MockBootloader_Get_Call {
+	return &MockBootloader_Get_Call{Call: _e.mock.On("Get", ctx, path)}
+}
+
+func (_c *MockBootloader_Get_Call) Run(run func(ctx context.Context, path string)) *MockBootloader_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockBootloader_Get_Call) Return(_a0 []byte, _a1 error) *MockBootloader_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockBootloader_Get_Call) RunAndReturn(run func(context.Context, string) ([]byte, error)) *MockBootloader_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockBootloader creates a new instance of MockBootloader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockBootloader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockBootloader {
+	mock := &MockBootloader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
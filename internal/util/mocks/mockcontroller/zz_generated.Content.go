@@ -25,9 +25,9 @@ func (_m *MockContent) EXPECT() *MockContent_Expecter {
 	return &MockContent_Expecter{mock: &_m.Mock}
 }
 
-// GetByID provides a mock function with given fields: ctx, contentID, attributes
-func (_m *MockContent) GetByID(ctx context.Context, contentID uuid.UUID, attributes types.IPXESelectors) ([]byte, error) {
-	ret := _m.Called(ctx, contentID, attributes)
+// GetByID provides a mock function with given fields: ctx, contentID, attributes, params
+func (_m *MockContent) GetByID(ctx context.Context, contentID uuid.UUID, attributes types.IPXESelectors, params types.IpxeParams) ([]byte, error) {
+	ret := _m.Called(ctx, contentID, attributes, params)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByID")
@@ -35,19 +35,19 @@ func (_m *MockContent) GetByID(ctx context.Context, contentID uuid.UUID, attribu
 
 	var r0 []byte
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, types.IPXESelectors) ([]byte, error)); ok {
-		return rf(ctx, contentID, attributes)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, types.IPXESelectors, types.IpxeParams) ([]byte, error)); ok {
+		return rf(ctx, contentID, attributes, params)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, types.IPXESelectors) []byte); ok {
-		r0 = rf(ctx, contentID, attributes)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, types.IPXESelectors, types.IpxeParams) []byte); ok {
+		r0 = rf(ctx, contentID, attributes, params)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]byte)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, types.IPXESelectors) error); ok {
-		r1 = rf(ctx, contentID, attributes)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, types.IPXESelectors, types.IpxeParams) error); ok {
+		r1 = rf(ctx, contentID, attributes, params)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -64,13 +64,14 @@ type MockContent_GetByID_Call struct {
 //   - ctx context.Context
 //   - contentID uuid.UUID
 //   - attributes types.IPXESelectors
-func (_e *MockContent_Expecter) GetByID(ctx interface{}, contentID interface{}, attributes interface{}) *MockContent_GetByID_Call {
-	return &MockContent_GetByID_Call{Call: _e.mock.On("GetByID", ctx, contentID, attributes)}
+//   - params types.IpxeParams
+func (_e *MockContent_Expecter) GetByID(ctx interface{}, contentID interface{}, attributes interface{}, params interface{}) *MockContent_GetByID_Call {
+	return &MockContent_GetByID_Call{Call: _e.mock.On("GetByID", ctx, contentID, attributes, params)}
 }
 
-func (_c *MockContent_GetByID_Call) Run(run func(ctx context.Context, contentID uuid.UUID, attributes types.IPXESelectors)) *MockContent_GetByID_Call {
+func (_c *MockContent_GetByID_Call) Run(run func(ctx context.Context, contentID uuid.UUID, attributes types.IPXESelectors, params types.IpxeParams)) *MockContent_GetByID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(types.IPXESelectors))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(types.IPXESelectors), args[3].(types.IpxeParams))
 	})
 	return _c
 }
@@ -80,7 +81,7 @@ func (_c *MockContent_GetByID_Call) Return(_a0 []byte, _a1 error) *MockContent_G
 	return _c
 }
 
-func (_c *MockContent_GetByID_Call) RunAndReturn(run func(context.Context, uuid.UUID, types.IPXESelectors) ([]byte, error)) *MockContent_GetByID_Call {
+func (_c *MockContent_GetByID_Call) RunAndReturn(run func(context.Context, uuid.UUID, types.IPXESelectors, types.IpxeParams) ([]byte, error)) *MockContent_GetByID_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,130 @@
+// Code generated by mockery v2.42.0. DO NOT EDIT.
+
+package mockcontroller
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockContentCache is an autogenerated mock type for the ContentCache type
+type MockContentCache struct {
+	mock.Mock
+}
+
+type MockContentCache_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockContentCache) EXPECT() *MockContentCache_Expecter {
+	return &MockContentCache_Expecter{mock: &_m.Mock}
+}
+
+// Get provides a mock function with given fields: operation, key
+func (_m *MockContentCache) Get(operation string, key string) ([]byte, bool) {
+	ret := _m.Called(operation, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 []byte
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(string, string) ([]byte, bool)); ok {
+		return rf(operation, key)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []byte); ok {
+		r0 = rf(operation, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) bool); ok {
+		r1 = rf(operation, key)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// MockContentCache_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockContentCache_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - operation string
+//   - key string
+func (_e *MockContentCache_Expecter) Get(operation interface{}, key interface{}) *MockContentCache_Get_Call {
+	return &MockContentCache_Get_Call{Call: _e.mock.On("Get", operation, key)}
+}
+
+func (_c *MockContentCache_Get_Call) Run(run func(operation string, key string)) *MockContentCache_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockContentCache_Get_Call) Return(_a0 []byte, _a1 bool) *MockContentCache_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockContentCache_Get_Call) RunAndReturn(run func(string, string) ([]byte, bool)) *MockContentCache_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Set provides a mock function with given fields: key, value, ttl
+func (_m *MockContentCache) Set(key string, value []byte, ttl time.Duration) {
+	_m.Called(key, value, ttl)
+}
+
+// MockContentCache_Set_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Set'
+type MockContentCache_Set_Call struct {
+	*mock.Call
+}
+
+// Set is a helper method to define mock.On call
+//   - key string
+//   - value []byte
+//   - ttl time.Duration
+func (_e *MockContentCache_Expecter) Set(key interface{}, value interface{}, ttl interface{}) *MockContentCache_Set_Call {
+	return &MockContentCache_Set_Call{Call: _e.mock.On("Set", key, value, ttl)}
+}
+
+func (_c *MockContentCache_Set_Call) Run(run func(key string, value []byte, ttl time.Duration)) *MockContentCache_Set_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].([]byte), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockContentCache_Set_Call) Return() *MockContentCache_Set_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockContentCache_Set_Call) RunAndReturn(run func(string, []byte, time.Duration)) *MockContentCache_Set_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockContentCache creates a new instance of MockContentCache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockContentCache(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockContentCache {
+	mock := &MockContentCache{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
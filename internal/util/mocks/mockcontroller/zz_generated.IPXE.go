@@ -70,9 +70,9 @@ func (_c *MockIPXE_Boostrap_Call) RunAndReturn(run func() []byte) *MockIPXE_Boos
 	return _c
 }
 
-// FindProfileAndRender provides a mock function with given fields: ctx, selectors
-func (_m *MockIPXE) FindProfileAndRender(ctx context.Context, selectors types.IPXESelectors) ([]byte, error) {
-	ret := _m.Called(ctx, selectors)
+// FindProfileAndRender provides a mock function with given fields: ctx, selectors, params
+func (_m *MockIPXE) FindProfileAndRender(ctx context.Context, selectors types.IPXESelectors, params types.IpxeParams) ([]byte, error) {
+	ret := _m.Called(ctx, selectors, params)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindProfileAndRender")
@@ -80,19 +80,19 @@ func (_m *MockIPXE) FindProfileAndRender(ctx context.Context, selectors types.IP
 
 	var r0 []byte
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, types.IPXESelectors) ([]byte, error)); ok {
-		return rf(ctx, selectors)
+	if rf, ok := ret.Get(0).(func(context.Context, types.IPXESelectors, types.IpxeParams) ([]byte, error)); ok {
+		return rf(ctx, selectors, params)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, types.IPXESelectors) []byte); ok {
-		r0 = rf(ctx, selectors)
+	if rf, ok := ret.Get(0).(func(context.Context, types.IPXESelectors, types.IpxeParams) []byte); ok {
+		r0 = rf(ctx, selectors, params)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]byte)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, types.IPXESelectors) error); ok {
-		r1 = rf(ctx, selectors)
+	if rf, ok := ret.Get(1).(func(context.Context, types.IPXESelectors, types.IpxeParams) error); ok {
+		r1 = rf(ctx, selectors, params)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -108,13 +108,14 @@ type MockIPXE_FindProfileAndRender_Call struct {
 // FindProfileAndRender is a helper method to define mock.On call
 //   - ctx context.Context
 //   - selectors types.IPXESelectors
-func (_e *MockIPXE_Expecter) FindProfileAndRender(ctx interface{}, selectors interface{}) *MockIPXE_FindProfileAndRender_Call {
-	return &MockIPXE_FindProfileAndRender_Call{Call: _e.mock.On("FindProfileAndRender", ctx, selectors)}
+//   - params types.IpxeParams
+func (_e *MockIPXE_Expecter) FindProfileAndRender(ctx interface{}, selectors interface{}, params interface{}) *MockIPXE_FindProfileAndRender_Call {
+	return &MockIPXE_FindProfileAndRender_Call{Call: _e.mock.On("FindProfileAndRender", ctx, selectors, params)}
 }
 
-func (_c *MockIPXE_FindProfileAndRender_Call) Run(run func(ctx context.Context, selectors types.IPXESelectors)) *MockIPXE_FindProfileAndRender_Call {
+func (_c *MockIPXE_FindProfileAndRender_Call) Run(run func(ctx context.Context, selectors types.IPXESelectors, params types.IpxeParams)) *MockIPXE_FindProfileAndRender_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(types.IPXESelectors))
+		run(args[0].(context.Context), args[1].(types.IPXESelectors), args[2].(types.IpxeParams))
 	})
 	return _c
 }
@@ -124,7 +125,7 @@ func (_c *MockIPXE_FindProfileAndRender_Call) Return(_a0 []byte, _a1 error) *Moc
 	return _c
 }
 
-func (_c *MockIPXE_FindProfileAndRender_Call) RunAndReturn(run func(context.Context, types.IPXESelectors) ([]byte, error)) *MockIPXE_FindProfileAndRender_Call {
+func (_c *MockIPXE_FindProfileAndRender_Call) RunAndReturn(run func(context.Context, types.IPXESelectors, types.IpxeParams) ([]byte, error)) *MockIPXE_FindProfileAndRender_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,126 @@
+package templateutil
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+var errIPXEEscape = errors.New("ipxe strings cannot contain line breaks")
+
+// New returns a template with the functions of FuncMap. Templates referencing any other function fail to parse.
+func New(name string) *template.Template {
+	return template.New(name).Funcs(FuncMap())
+}
+
+// FuncMap returns the functions available to templates, in addition to the builtin functions of text/template such as
+// `urlquery`:
+//   - `default`: returns the default if the value is empty, e.g. `{{ .Machine.Hostname | default "unknown" }}`.
+//   - `b64enc`: encodes a string to base64.
+//   - `join`: joins a list with a separator, e.g. `{{ .List | join "," }}`.
+//   - `split`: splits a string into a list, e.g. `{{ split "," .String }}`.
+//   - `toJSON`: encodes a value to JSON.
+//   - `sha256sum`: returns the hexadecimal sha256 checksum of a string.
+//   - `ipxeEscape`: escapes the characters iPXE interprets in command lines, e.g. whitespaces or `${`.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"default":    defaultValue,
+		"b64enc":     b64enc,
+		"join":       join,
+		"split":      split,
+		"toJSON":     toJSON,
+		"sha256sum":  sha256sum,
+		"ipxeEscape": ipxeEscape,
+	}
+}
+
+// ---------------------------------------------------- FUNCTIONS --------------------------------------------------- //
+
+func defaultValue(def any, given ...any) any {
+	if len(given) == 0 || isEmpty(given[0]) {
+		return def
+	}
+
+	return given[0]
+}
+
+// isEmpty reports whether the value is nil, or the zero value of its type, or an empty string, slice or map.
+func isEmpty(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil() || isEmpty(rv.Elem().Interface())
+	default:
+		return rv.IsZero()
+	}
+}
+
+func b64enc(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func join(sep string, list any) string {
+	rv := reflect.ValueOf(list)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return fmt.Sprint(list)
+	}
+
+	out := make([]string, 0, rv.Len())
+	for i := range rv.Len() {
+		out = append(out, fmt.Sprint(rv.Index(i).Interface()))
+	}
+
+	return strings.Join(out, sep)
+}
+
+func split(sep, s string) []string {
+	return strings.Split(s, sep)
+}
+
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	return string(b), nil
+}
+
+func sha256sum(s string) string {
+	sum := sha256.Sum256([]byte(s))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// ipxeEscape escapes the characters iPXE interprets in command lines with a backslash, e.g. whitespaces splitting
+// arguments, `$` expanding settings, or `&` and `|` chaining commands. Line breaks cannot be escaped.
+func ipxeEscape(s string) (string, error) {
+	if strings.ContainsAny(s, "\r\n") {
+		return "", errIPXEEscape
+	}
+
+	var b strings.Builder
+
+	for _, r := range s {
+		switch r {
+		case ' ', '\t', '$', '&', '|', '\\', '#', '"', '\'':
+			b.WriteRune('\\')
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,62 @@
+//go:build unit
+
+package templateutil_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alexandremahdhaoui/ipxer/internal/util/templateutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuncMap(t *testing.T) {
+	const valueSHA256 = "c8687a08aa5d6ed2044328fa6a697ab8e96dc34291e8c2034ae8c38e6fcc6d65"
+
+	data := map[string]any{
+		"Empty":  "",
+		"Value":  "a b",
+		"List":   []string{"a", "b"},
+		"Struct": struct{ A string }{A: "b"},
+	}
+
+	for name, tc := range map[string]struct {
+		template string
+		expected string
+	}{
+		"default":       {template: `{{ .Empty | default "x" }} {{ .Value | default "x" }}`, expected: "x a b"},
+		"defaultNil":    {template: `{{ .Missing | default "x" }}`, expected: "x"},
+		"b64enc":        {template: `{{ .Value | b64enc }}`, expected: "YSBi"},
+		"urlquery":      {template: `{{ .Value | urlquery }}`, expected: "a+b"},
+		"join":          {template: `{{ .List | join "," }}`, expected: "a,b"},
+		"split":         {template: `{{ index (split " " .Value) 1 }}`, expected: "b"},
+		"toJSON":        {template: `{{ toJSON .Struct }}`, expected: `{"A":"b"}`},
+		"sha256sum":     {template: `{{ .Value | sha256sum }}`, expected: valueSHA256},
+		"ipxeEscape":    {template: `{{ ipxeEscape "a b${c}" }}`, expected: `a\ b\${c}`},
+		"ipxeEscapeNil": {template: `{{ .Empty | ipxeEscape }}`, expected: ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tpl, err := templateutil.New("").Parse(tc.template)
+			require.NoError(t, err)
+
+			buf := bytes.NewBuffer(nil)
+			require.NoError(t, tpl.Execute(buf, data))
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Run("UnknownFunction", func(t *testing.T) {
+			_, err := templateutil.New("").Parse(`{{ unknown }}`)
+			assert.Error(t, err)
+		})
+
+		t.Run("IPXEEscapeLineBreak", func(t *testing.T) {
+			tpl, err := templateutil.New("").Parse(`{{ ipxeEscape "a\nb" }}`)
+			require.NoError(t, err)
+
+			assert.Error(t, tpl.Execute(bytes.NewBuffer(nil), nil))
+		})
+	})
+}
@@ -18,7 +18,7 @@ const (
 	objectRefName = "test-object-ref"
 	webhookName   = "test-webhook"
 
-	ipxeTemplate = "abc123"
+	ipxeTemplate = "#!ipxe\nabc123"
 
 	WebhookServerFQDN    = "localhost"
 	WebhookServerURLPath = "s3-test"
@@ -188,10 +188,15 @@ func NewTypesWebhookConfig() types.WebhookConfig {
 			UsernameJSONPath: &jsonpath.JSONPath{}, // to annoying
 			PasswordJSONPath: &jsonpath.JSONPath{}, // to annoying
 		},
+		Timeout:     types.DefaultWebhookTimeout,
+		Retries:     types.DefaultWebhookRetries,
+		MaxBodySize: types.DefaultWebhookMaxBodySize,
 	}
 }
 
 func MakeContentComparable(content types.Content) types.Content {
+	content.Hash = ""
+
 	if content.ObjectRef != nil {
 		content.ObjectRef.JSONPath = &jsonpath.JSONPath{}
 	}
@@ -4,4 +4,5 @@ type ContextKey string
 
 const (
 	ServerNameContextKey ContextKey = "serverName"
+	QueryContextKey      ContextKey = "query"
 )
@@ -31,6 +31,12 @@ const (
 	BuildarchSelectorX8664 BuildarchSelector = "x86_64"
 )
 
+// Defines values for PlatformSelector.
+const (
+	PlatformSelectorEfi    PlatformSelector = "efi"
+	PlatformSelectorPcbios PlatformSelector = "pcbios"
+)
+
 // Defines values for GetContentByIDParamsBuildarch.
 const (
 	GetContentByIDParamsBuildarchArm32 GetContentByIDParamsBuildarch = "arm32"
@@ -39,6 +45,12 @@ const (
 	GetContentByIDParamsBuildarchX8664 GetContentByIDParamsBuildarch = "x86_64"
 )
 
+// Defines values for GetContentByIDParamsPlatform.
+const (
+	GetContentByIDParamsPlatformEfi    GetContentByIDParamsPlatform = "efi"
+	GetContentByIDParamsPlatformPcbios GetContentByIDParamsPlatform = "pcbios"
+)
+
 // Defines values for GetIPXEBySelectorsParamsBuildarch.
 const (
 	Arm32 GetIPXEBySelectorsParamsBuildarch = "arm32"
@@ -47,6 +59,12 @@ const (
 	X8664 GetIPXEBySelectorsParamsBuildarch = "x86_64"
 )
 
+// Defines values for GetIPXEBySelectorsParamsPlatform.
+const (
+	Efi    GetIPXEBySelectorsParamsPlatform = "efi"
+	Pcbios GetIPXEBySelectorsParamsPlatform = "pcbios"
+)
+
 // Error defines model for Error.
 type Error struct {
 	Code    int32  `json:"code"`
@@ -62,9 +80,24 @@ type Content = string
 // IPXE An iPXE manifest.
 type IPXE = string
 
+// AssetSelector defines model for assetSelector.
+type AssetSelector = string
+
 // BuildarchSelector defines model for buildarchSelector.
 type BuildarchSelector string
 
+// HostnameSelector defines model for hostnameSelector.
+type HostnameSelector = string
+
+// MacSelector defines model for macSelector.
+type MacSelector = string
+
+// PlatformSelector defines model for platformSelector.
+type PlatformSelector string
+
+// SerialSelector defines model for serialSelector.
+type SerialSelector = string
+
 // UuidSelector defines model for uuidSelector.
 type UuidSelector = UUID
 
@@ -90,20 +123,56 @@ type N503 = Error
 type GetContentByIDParams struct {
 	Uuid      UuidSelector                  `form:"uuid" json:"uuid"`
 	Buildarch GetContentByIDParamsBuildarch `form:"buildarch" json:"buildarch"`
+
+	// Platform Firmware platform as reported by iPXE's `${platform}`. Unspecified implies any platform.
+	Platform *GetContentByIDParamsPlatform `form:"platform,omitempty" json:"platform,omitempty"`
+
+	// Mac MAC address of the interface the subject boots from, e.g. iPXE's `${mac:hexhyp}`.
+	Mac *MacSelector `form:"mac,omitempty" json:"mac,omitempty"`
+
+	// Serial Serial number reported by the SMBIOS of the subject, i.e. iPXE's `${serial}`.
+	Serial *SerialSelector `form:"serial,omitempty" json:"serial,omitempty"`
+
+	// Asset Asset tag reported by the SMBIOS of the subject, i.e. iPXE's `${asset}`.
+	Asset *AssetSelector `form:"asset,omitempty" json:"asset,omitempty"`
+
+	// Hostname Hostname the subject received by DHCP, i.e. iPXE's `${hostname}`.
+	Hostname *HostnameSelector `form:"hostname,omitempty" json:"hostname,omitempty"`
 }
 
 // GetContentByIDParamsBuildarch defines parameters for GetContentByID.
 type GetContentByIDParamsBuildarch string
 
+// GetContentByIDParamsPlatform defines parameters for GetContentByID.
+type GetContentByIDParamsPlatform string
+
 // GetIPXEBySelectorsParams defines parameters for GetIPXEBySelectors.
 type GetIPXEBySelectorsParams struct {
 	Uuid      UuidSelector                      `form:"uuid" json:"uuid"`
 	Buildarch GetIPXEBySelectorsParamsBuildarch `form:"buildarch" json:"buildarch"`
+
+	// Platform Firmware platform as reported by iPXE's `${platform}`. Unspecified implies any platform.
+	Platform *GetIPXEBySelectorsParamsPlatform `form:"platform,omitempty" json:"platform,omitempty"`
+
+	// Mac MAC address of the interface the subject boots from, e.g. iPXE's `${mac:hexhyp}`.
+	Mac *MacSelector `form:"mac,omitempty" json:"mac,omitempty"`
+
+	// Serial Serial number reported by the SMBIOS of the subject, i.e. iPXE's `${serial}`.
+	Serial *SerialSelector `form:"serial,omitempty" json:"serial,omitempty"`
+
+	// Asset Asset tag reported by the SMBIOS of the subject, i.e. iPXE's `${asset}`.
+	Asset *AssetSelector `form:"asset,omitempty" json:"asset,omitempty"`
+
+	// Hostname Hostname the subject received by DHCP, i.e. iPXE's `${hostname}`.
+	Hostname *HostnameSelector `form:"hostname,omitempty" json:"hostname,omitempty"`
 }
 
 // GetIPXEBySelectorsParamsBuildarch defines parameters for GetIPXEBySelectors.
 type GetIPXEBySelectorsParamsBuildarch string
 
+// GetIPXEBySelectorsParamsPlatform defines parameters for GetIPXEBySelectors.
+type GetIPXEBySelectorsParamsPlatform string
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 	// Retrieve an iPXE config to chainload to "/ipxe?labels=values"
@@ -189,6 +258,46 @@ func (siw *ServerInterfaceWrapper) GetContentByID(w http.ResponseWriter, r *http
 		return
 	}
 
+	// ------------- Optional query parameter "platform" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "platform", r.URL.Query(), &params.Platform)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "platform", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "mac" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "mac", r.URL.Query(), &params.Mac)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mac", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "serial" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "serial", r.URL.Query(), &params.Serial)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "serial", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "asset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "asset", r.URL.Query(), &params.Asset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "asset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "hostname" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "hostname", r.URL.Query(), &params.Hostname)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "hostname", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.GetContentByID(w, r, contentID, params)
 	}))
@@ -239,6 +348,46 @@ func (siw *ServerInterfaceWrapper) GetIPXEBySelectors(w http.ResponseWriter, r *
 		return
 	}
 
+	// ------------- Optional query parameter "platform" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "platform", r.URL.Query(), &params.Platform)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "platform", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "mac" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "mac", r.URL.Query(), &params.Mac)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mac", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "serial" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "serial", r.URL.Query(), &params.Serial)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "serial", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "asset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "asset", r.URL.Query(), &params.Asset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "asset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "hostname" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "hostname", r.URL.Query(), &params.Hostname)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "hostname", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.GetIPXEBySelectors(w, r, params)
 	}))
@@ -725,31 +874,75 @@ func (sh *strictHandler) GetIPXEBySelectors(w http.ResponseWriter, r *http.Reque
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xXXW/bOhL9K1w2jzIlf8RtBQQLp3ELA/0IkmZRoC4CWhpbbCVSJSknXsP//WJIyVYS",
-	"IzFyc4H70CfL0uHMmeHhzHBNE1WUSoK0hsZrWnLNC7Cg3b9ZJfKU6yS7hBwSqzS+FJLG9HcFekUDKnkB",
-	"NN4BaUA1/K6EhpTGVlcQUJNkUHBcCbIqaPydiv6bIQ3o7Zvh9XBAA8p10e/53+GA/gioXZVo1Vgt5IJu",
-	"NgGtKpE+RQIxj/o/0jCnMX0V7mIO/VcTXl1NzugGXWkwpZIGXAYGUYQ/iZIWpMVHXpa5SLgVSoY/jZIu",
-	"sFtelDl4ZAo0HkRRQAswhi+Q2SlPCdICYwNS5sANkCSD5BdZqUoTIcvK0s2hVMdaK+25pmASLUokU7u5",
-	"8G7Q2iDqPo97t839SvLKZkqL/0O6JV9qtRQpkCXPRUoQANLWln045gXiGdWOG7NzpYv62ZBCGCPkgijM",
-	"n+PhY+4/L+Z+O+b3Ss9EmoIMcINIqohUlmR8CaQE7TwrSawiPEnAGGIzYYgGoyqdwAsEvvXvQxo8L6RB",
-	"O6SvGTQShHTLldxw42Kbq0qmL7FlxJSQiLloOxH3fBw/71Ad3z1UE2lBS54TA3oJmgBy2irU6hXhCy4k",
-	"ybkF/QKhXUm4LSHB9Il9rn1k/edFdkd+l6CXIgFSSb7kIuezHP7BuPZ4Y2i2FUTr0cKtDcucCxfJYa6b",
-	"5fucV+4Ezas8XxENVgtYQkrqFY6HOP82fgESzszhDBBOCi7FHAzy2ObZtQWfTOyXWpWgrfDdwu/mmvpC",
-	"RWMqpHWdrW5oqJuF37Ttbq/3dLtdC/vube7wu+6oZj8hcYXe9a62rmi314fB8fB1B968nXW6vbTf4YPj",
-	"YWfQGw67g+7rQRRFNNjxrFvnPSZ3NHDvnMtVs0kBAbZghJNZZbmEUCykcEVaaZLkqko7QgrbymXQIrrk",
-	"WnBpYzJPlJnKJWgsrTHpsgGLprLkxtyk8VQSUhnQxj0R0iHY8mOSKA3+DSHGZNe7TnX9C1YN2q8wJuto",
-	"w8loNBoxxqZyX7yN1u4He18O7RBe/UeUtzCVU2nAksuvF+PRJ2IsniP/6n/ji8vJl8+k/5b1ot4g6nZ7",
-	"rI/R4cd3Xz6/n3y4uvhIMmtLE4dhbZklqsCTMxcLJhaysX86uhy30W7yMgwzoQybQ6o0L7VCaTClF2Gp",
-	"VRoaq4EXJjxae3qbell4tK7JbUI/jaGbX6Al5ORoXfvahN5sxzvp7BZ1crGEjsd3vAGCm63TkwJLVM0K",
-	"UUwrZefmutL5ycGW/RrmLTNRLEgjLjYX2tiZUnb3qsy5RUUzkZ4UYHm++1Tn0TvfpnwzlZ4t6XRQUMSR",
-	"PpidW8uLOwQxf8hqn7zwZOMQ0xQznriDVU+voxxuuUw1kE88SzOuKkEDWumcxrTZ7IWwWTVzyuANvGjQ",
-	"IcrQ1Za76v2Kw4nAIQXI6HxC5koTXiv60vUv1HMuEpAG2oRKnmRAeix6wOPm5oZx99lprF5rwo+Td+PP",
-	"l+NOj0Uss0WOZKyw7pg4f6PzCQ1ofcqxTrGIRYhSJUheChrTPotYnwa05DZzRTXEfDIMDv8twCUNq65r",
-	"qpOUxvQD2Mn5t/GpUtZYzUt6b4rv+YFjX3vY4uoGETQj/+NgBO1G7Kew3dZo+hS235r5nsIOWtPU49hj",
-	"z/f4EA4Icv2uKgquVzSmF3Vb3OrGnyecgJOMC5krnuKfKXUi/G/OZ5CbkyXPKzBTikeBL4y7+uE2/kDr",
-	"zVQQruuHydnmsQ1+51Gnq8mZU8funvp9/WBSE78rICLFS8lcgCZq7uS/HSsCf4FEje3uj1sef/cSGexH",
-	"7TiHd66zB+AfXsQ3P56j8e0k9kfmj8k8XUleiITjSMgb1ZDZighryOSMtQTdZNRr+qAqtWo20TwU8r9U",
-	"N39q4+G1sZkSUS+mtdP3S+Bm81cAAAD//z+wuVh9EwAA",
+	"H4sIAAAAAAAA/wTA744bx7E//Fvp50sCeTPLHmkpHZ8GggeyvclZwH8EMQoEeA27t6eGbGW6qlVVXC8z",
+	"mHv/fVYUaV2Y2A1pRc+aGzmpIa3IZuQnWqi4KNKKiaxo7V6FkfDBjDx4PgelLuo0hedb8AuF08/fP/56",
+	"CjIHv1Cw6/NXKj6EeqBDqB+/PPzNwp/7NZuRb38eMKAyEr5dSW8YwLkRErIZOQZYuVDLSCv81gkJ5lr5",
+	"jG0b8Hyty5S1XE60UHFRpBWVkfDtSnrDAM6NkPB8rcuUtVwwQOnbtSpNSK5XGmDlQi0jrSC+NqTfUO+/",
+	"e48Br9+9/+P9EQOytvu3GJC1vT/i9wF+64QEc618xrYNuIg550YnWqi4KNKKiaxo7V6FkfB/Ys65UfAL",
+	"Bbs+f6XiQalQfaEpPN/Cj//3w8ch1AMdQv345eFvFv7crxcx59xo+/OAAZWR8O1KesMAzo2QcBFzzo0w",
+	"wMqFWkZa4bdOSDDXymds24CWy4kWKi6KtGIiK1q7V2Ek/Pzhh5CnScksyBz8QqGyk865UPALBbs+f6Xi",
+	"4VnELcwqbQh0OB9C/fjl4W8W/tyvLZd0odfLrW9/HjCgMhK+XUlvGMC5ERJaLhhg5UItI63wWyckmGvl",
+	"M7ZtQF+yz6LtRAsVF0VaMZEVrd2rMBL+UbX9lZVCX7LPoi1kC0pd1GkKz7dQP355+JuFP/drX7LPom37",
+	"8xA+s3Uqda40hdr6UslC5lvoS/ZZtB0woDISvl1JbxjAuRES+pJ9Fm0YYOVCLSOtIL42pN/Qy3MVwwCa",
+	"K34f4LdOSDDXymds2wAjrXk50ULFRZFWTGRFa/cqjIQTac1L4Gt7Jg1KXdRpCs+34BcKp5+/f/z1FGQO",
+	"fqFg1+evVHwI9UCHUD9+efibhT/3q5HWvGx/HjCgMhK+XUlvGMC5ERKMtOYFA6xcqGWkFX7rhARzrXzG",
+	"tg24Xut0ooWKiyKtqIyEb1fSGwZwboSE67VOGKD07VqVJiTXKw2wcqGWkVbslWYk7GKR1oWJ3aKVC7Vs",
+	"8fPnxx+xbdsAJevCRoa04jiOSCuKsBM70orc+1JL9iocv5ow0gp6za0vhLSiyERIx3Ec0MgsnwkJ3+cp",
+	"KH27kvkQ+kLZKJQLlf+Em1w1VO5XxzbAyoVaRlqxV5qRsItFWhcmdotWLtSyxQdVUWzbNmAiK1q7V2Ek",
+	"fJ+n8Im+Xckc24Dj+AZpRRF2YkdakXtfasleheNXE0ZaQa+59YWQVhSZCOk4vhnQyCyfCQmfOV/9Ilr/",
+	"S9MQ+kLZKHSVlzpReMlLnUK++oXYa8lehUPlfnXDNsDKhVpGWrFXmpGwi0VaFyZ2i1Yu1LLFB1VRbNs2",
+	"YCIrWrtXYSR8uPpFtP43exUOlWfRlr0Kh2qhVbPK5yAaKr/kpU7YBhzHe6QVRdiJHWlF7n2pJXsVjl9N",
+	"GGkFvebWF0JaUWQipON4P6CRWT4TEv4h+lyniXgIN7mGSQKLh0t+odBJWzWrwsEl5FLILPilWlAyuWoh",
+	"bAOsXKhlpBV7pRkJu1ikdWFit2jlQi1bfFAVxbZtAyayorV7FUbCP0Sf6zQRYxtwHI9IK4qwEzvSitz7",
+	"Ukv2Khy/mjDSCnrNrS+EtKLIREjH8TigkVk+ExL+daGg9O1K5jQFJZOrFgp/ZQssHma58oRtgJULtYy0",
+	"Yq80I2EXi7QuTOwWrVyoZYsPqqLYtm3ARFa0dq/CSPgQrFOpc6UpKJlctVCoFlg8zHLlCduAd+OItKII",
+	"O7Ejrci9L7Vkr8LxqwkjraDX3PpCSCuKTIT0bhwHNDLLZ0LCIzsp5yUY6QtpIFXRIfSFslFwvYV8zpXD",
+	"kp0U2wArF2oZacVeaUbCLhZpXZjYLVq5UMsWH1RFsW3bgImsaO1ehZHwmem1U3GaQmUn5bwEI30hDaQq",
+	"im3Au/EeaUURdmJHWpF7X2rJXoXjVxNGWkGvufWFkFYUmQjp3Xg/oJFZPhMSTqQvtVC4cn7JdcnPCw2h",
+	"L5SNgust5HOuHJbspNgGWLlQy0gr9kozEnaxSOvCxG7RyoVatvigKopt2wZMZEVr9yqMhBPpSy0Urpxf",
+	"cl3y80IHbAOKsBM70ooi7MSOtMLp1WNfcmWkFVYu1DLSir3SjIRdLNK6MLFbtHKhli0WYSd2bNs2YCIr",
+	"WrtXYSScrqWQ2XxdlltQcq30QlMowk7sB2wD6scvD0grirATO9IKp1ePfcmVkVZYuVDLSCv2SjMSdrFI",
+	"68LEbtHKhVq2WD9+ecC2bQMmsqK1exVGwulaCpnN12W5BSXXSi80hfrxy0NometM5gds2wArF2rZkFY8",
+	"qIoiregqndQrGdKKIhMhrZhFW3YkVPb7txjgt05IqOx0JsU2oJFZPhPSCr91QoK5Vj5j2wYofbtWpQnp",
+	"NxSZCAMameUz4fcBfuuEBHn+SsWxDfj8+fFHpBX0mltfCAlv3t7T8d37/7mj7/73+e7N2+n+Lh/fvb87",
+	"vn3//s3xzf8cx3HEgFm0ZUfC9VonDPBbJySYa+UztgFF2IkdacVEVrR2r8JI+MC3UISd2IdAh/Mh5PB8",
+	"9cwU65mrV+EgGsoi1+mucvXQMteZzA8YQK+59YWQ8JK1ZvYU5iL2xC+kVoVTeHM4HsYn7tnsryk9cQhX",
+	"I7X0xCGEcBc4N0qhiNIThxBCCGaXP/LVL6L1vzT98R+6WXriEEIIIYS7YHa5U8vhw4cPHw6HwxNjgN86",
+	"IcFcK5+xDagfvzwgrZjIitbuVRgJHzjUj18eQstcZzI/YAC95tYXQsLu/6v9lZ74iY08nP716eHDz8E8",
+	"Py/0xEYe/v3w6fT46y/h/n8Pb8e3x/HNm7eH+8P4xEYefvj1l388/vPzp5/Cxb1bipFec+sLHYq0WITn",
+	"ej7UMz/xExt5+P7D6eHzp5/Cxb1bivH5WpfJDkWUxA4zTaK5q3yl4gfRc+wqUzRXys3ifj3969PDh5+3",
+	"+Hyty2Rxv/774dPp8ddftvj63fs/3h+f+In/Q8q0hP36/YfTw+dPP21xpkk03xVRErvbr/9++HR6/PWX",
+	"7W6pL3T3H1Km5e71u/d/vD+GytV1+nvLlUMRJbHDUl/ooCI+2x9XXf6+X7//cHr4/OmnLc40iea7Ikpi",
+	"d/v13w+fTo+//rLdLfWF7lTEZzu8fvf+j/fHQ23nUM9cvQof5qrmzyIe6pmrV+FDX7LPou1Qp7838ryE",
+	"eubqVfhQhOd6Plx1+ft+/eHXX/7x+M/Pn37anrhydZ3C3R3nRqHlymG/fv/h9PD5009bnGkSzXdFlMTu",
+	"9uu/Hz6dHn/9Zbtb6gvdVa6uuc12eP3u/R/vj4fazk/8xM8i/sQY4LdOSDDXymds2zag8ixIK4qw5+JI",
+	"Kzg3QsKHhV4zT0rh53yZLlmuFQOuuiDh4t4txXiufrk+H4q0mBd6zTwptXyZLlmuNdb+SoptwERWtHav",
+	"wkj416VaqBb8QuHDx8cwi4bMoX788hBOpC+kBwxYaiE2QlrBuRESPvRcLhTeHkYMuOqChIt7txTjX3/9",
+	"dcg9lwsdRM9xqYXYyOJPjz88/HJ6uHt7GA8Xbwu2AV59ISTUj18ewoePjxjwQmpVGAlvDuNhxDZAOnHu",
+	"FQn3h/FwjwE9+8WQVsRnET/U/kpIK87kSCukk2avwo8TEv5J/vjxy8P3Im6uuWOAknVhI0Na8XYckVbs",
+	"lWYk7GKR1oWJ3aKSdWEji/XjlwdsA47jiLRirzQjYReLtC5M7BaVrAsbWTyOI7YBx/EN0oq90oyEXSzS",
+	"ujCxW1SyLmxk8Ti+wTbgON4jrdgrzUjYxSKtCxO7RSXrwkYWj+M9tgHH8Yi0Yq80I2EXi7QuTOwWlawL",
+	"G1k8jkdsA96NI9KKvdKMhF0s0rowsVtUsi5sZPHdOGIb8G68R1qxV5qRsItFWhcmdotK1oWNLL4b77Ft",
+	"A+zaWtYbEj6Ra6UXCplD/fjlIRThuZ6DSyiXXHmRPAWX8IRY+yv9/0t+psX+/pKXK9kTMMDz2ZB+Q+2v",
+	"hN+3bUAswk7scS3CTuyPP25IK87kSCukk2avwo8TEv5J/oOwE/v3t8cfMaBnzY2c1JB+WzGRFa3dqzAS",
+	"PnP9dqVQJ2KvcyUNMge/UCjCTuwHDKiMhJ79ggGcGyGhCDuxP/6IAUrfrlVpQnK90gArF2oZacVeaUbC",
+	"LhZpXZjYLVq5UMsWP39+/BHbNqzYK81I2MUirQsTu8WeNTdyUovXa51OtFBxUWzDir3SjIRdLNK6MLFb",
+	"7FlzIye1+Hyty5S1XE60UHFRbMOKvdKMhF0s0rowsVvsWXMjJ7XYl+yzaDvRQsVFsQ0r9kozEnaxSOvC",
+	"xG6xZ82NnNRiy+VECxUXxTas2CvNSNjFIq0LE7vFnjU3clKLRlrzcqKFiotiG1bslWYk7GKR1oWJ3WLP",
+	"mhs5qcVsRn6ihYqLYhtW7JVmJOxikdaFid1iz5obOanFi5hzbnSihYqLYvt9gJJ1YSNDWvF2HJFW7JVm",
+	"JOxikdaFid2iknVhI4tF2Ikd24DjOCKt2CvNSNjFIq0LE7tFJevCRhaP44htwHF8g7RirzQjYReLtC5M",
+	"7BaVrAsbWTyOb7ANOI73SCv2SjMSdrFI68LEblHJurCRxeN4j23AcTwirdgrzUjYxSKtCxO7RSXrwkYW",
+	"j+MR24B344i0Yq80I2EXi7QuTOwWlawLG1l8N47YBrwb75FW7JVmJOxikdaFid2iknVhI4vvxnts2wC7",
+	"tpb1hoRP5FrphcJ049xqyctyCzkUYSf28HwL1S08/njAAM9nQ/oNRdiJHb9v24BY+yshrTiTI62QTpq9",
+	"Cj9OSPgn+ePHLw/f3060UHFRw4CeNTdyUkP6bcVeaUbCLhZpXZjYLfasuZGTWrxe63SihYqLYhtW7JVm",
+	"JOxikdaFid1iz5obOanF52tdpqzlcqKFiotiG1bslWYk7GKR1oWJ3WLPmhs5qcW+ZJ9F24kWKi6KbVix",
+	"V5qRsItFWhcmdos9a27kpBZbLidaqLgotmHFXmlGwi4WaV2Y2C32rLmRk1o00pqXEy1UXBTbsGKvNCNh",
+	"F4u0LkzsFnvW3MhJLWYz8hMtVFwU27BirzQjYReLtC5M7BZ71tzISS1exJxzoxMtVFwU2+8DlKwLGxnS",
+	"irfjiLRirzQjYReLtC5M7BaVrAsbWawfvzxgG3AcR6QVe6UZCbtYpHVhYreoZF3YyOJxHLENOI5vkFbs",
+	"lWYk7GKR1oWJ3aKSdWEji8fxDbYBx/EeacVeaUbCLhZpXZjYLSpZFzayeBzvsQ04jkekFXulGQm7WKR1",
+	"YWK3qGRd2MjicTxiG/BuHJFW7JVmJOxikdaFid2iknVhI4vvxhHbgHfjPdKKvdKMhF0s0rowsVtUsi5s",
+	"ZPHdeI9tG2DX1rLekPCJXCu9UMgc6scvD6FlrjOZh+dbMFqouKhhgOezIf2G2l8Jv2/btv2/AQDwRS6U",
+	"1BgAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -16,21 +16,19 @@ import (
 	"path"
 	"strings"
 
+	externalRef0 "github.com/alexandremahdhaoui/ipxer/pkg/generated/webhookattributes"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/oapi-codegen/runtime"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
-// Defines values for Buildarch.
-const (
-	Arm32 Buildarch = "arm32"
-	Arm64 Buildarch = "arm64"
-	I386  Buildarch = "i386"
-	X8664 Buildarch = "x86_64"
-)
+// Attributes Attributes of the booting machine, shared by the resolver and the transformer webhooks. Attributes are versioned
+// by `version`: new optional attributes may be added to a version, whereas breaking changes bump it. Unknown
+// attributes are omitted.
+type Attributes = externalRef0.Attributes
 
 // Buildarch defines model for Buildarch.
-type Buildarch string
+type Buildarch = externalRef0.Buildarch
 
 // Error defines model for Error.
 type Error struct {
@@ -77,6 +75,10 @@ type ResolveResp struct {
 type ResolveParams struct {
 	Uuid      UuidSelector      `form:"uuid" json:"uuid"`
 	Buildarch BuildarchSelector `form:"buildarch" json:"buildarch"`
+
+	// Attributes Attributes of the booting machine, e.g. `attributes[version]=v1&attributes[mac]=aa:bb:cc:dd:ee:ff`. The `uuid`
+	// and `buildarch` parameters are kept for compatibility.
+	Attributes *Attributes `json:"attributes,omitempty"`
 }
 
 // RequestEditorFn  is the function signature for the RequestEditor callback function
@@ -221,6 +223,22 @@ func NewResolveRequest(server string, anyRoutes AnyRoutes, params *ResolveParams
 			}
 		}
 
+		if params.Attributes != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("deepObject", true, "attributes", runtime.ParamLocationQuery, *params.Attributes); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
 		queryURL.RawQuery = queryValues.Encode()
 	}
 
@@ -387,24 +405,32 @@ func ParseResolveResponse(rsp *http.Response) (*ResolveResponse, error) {
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/7RWUW/bNhD+K8StT4MsybbspnpLtwwwsA1F0mDDAm+gpbPEViIZknLiBvrvA0nZUmKj",
-	"MYz0SZb18e777o539wSZqKXgyI2G9AkkVbRGg8q9Ub69Fo1B95KjzhSThgkOKdxydt8gYTlyw9YMFRFr",
-	"YkokUok1qzCEAJgFSmpKCIDTGiEdWAxA4X3DFOaQGtVgADorsaaehTGo7Ol/7y5H/9DRt2X3jEcflj+/",
-	"gwDMVlp72ijGC2jbAFYNq3KqsvIGK8yMUNaSo3DfoNr2HPbA73J4p3ANKfwU9QGK/FcdfdxbsI6bhuWv",
-	"+bSYs93d3i5+hda6Uqil4NonJIlj+8gEN8iNy5eUFcuoTVH0Rds8PQE+0lpW6JE5QprEcQA1ak0Ly+wj",
-	"zYmlhdoERFZINZKsxOwr2YpGEcZlY6A9leqVUkJ5rs/rxbq59m6stSQen8d9POR+y2ljSqHYN8z35KUS",
-	"G5Yj2dCK5cQCbIl6y16OfgM9l53jndm1UHX3W5Oaac14QYSNn+PhNU/P0zwdav5NqBXLc+SBTRDJBeHC",
-	"kJJukEhUzrPgxAhCswy1JqZkmijUolEZvoHwvX8vKTlPUjKU9LnEXQlivudKHqh22tai4flbpIxoiZlt",
-	"VgMn7IWP2XmXavb8Ui247V+0IhrVBhVBy2lfoUZtCS0o46SiBtUbSLvl+Cgxs+Fjx1x7ZdPzlD0rvxtU",
-	"G5YhaTjdUFbRVYU/UNcRb6E1e41aVBu8Ri1P0DQYLEpIVIZ1E40a9+/hLOn+EasvmJljxOyk24d815XJ",
-	"WonaDUHl+RHkuRSMG2e0U2s99gPEhpw3NaR3wKYXcwjg8WL+3zyBAKiqpxP/nCewPJh5Afi4HejyiXsC",
-	"35MgBcaNs9QZsCVS+PzsE3ssCv20uvM2e/zyIEYBuDE1LCEYT6aYzObvR3jxYTUaT/LpiCaz+SiZzOfj",
-	"ZPw+ieMYgp5nNyVfMmntKrEWu0zTzGW6m6yXFT5Sniskf9AyL6loGATQqApSKI2ROo2igpmyWYWZqCO6",
-	"g9c7dMTkow/G8xR/to2TaZfPy0+LXfPoBsna9nYrs7bThReEkr9wVQrxlXTFqewKVLEMucYhX0mzEskk",
-	"jA9oPjw8hNR9DoUqou6sjn5f/HL1583VaBLGYWnqynI1zLgQLz79fXV94BoC2KDSXsg4jMPYnhESOZUM",
-	"UpiGcTiFwC1nrmSip/1m1tr3Al2MbVU5vYsc0t2tc+f6NfHu+N3uIVG/9LXBq+Bn29QJ+MO1r12+WJQm",
-	"vqcfM7THRcOW4ibbCWcsqF9mXsOOB0vAa9jpYLq+hk0Gc+v72JnnOzuFgwW5rtXUNVXbPv2EkkzwNSvs",
-	"TaWFrQDo+h0s27Zt/w8AAP//Box+0FQMAAA=",
+	"H4sIAAAAAAAC/7RYbZObyBH+K10Tf0ohhF5WZ1N1H+SLr2qrchfX+jZJxVZODbTE3MIMnhmklbf031MN",
+	"CFhLsWRl80kCmqeffpl+4UnEOi+0IuWsCJ9EgQZzcmSqK1S7O106qi4SsrGRhZNaiVDcK/m5JJAJKSdX",
+	"kgzoFbiUoDB6JTPyhSckCxboUuEJhTmJsIfoCUOfS2koEaEzJXnCxinlWLNwjgy//e+P88G/cPBl0fwG",
+	"gzeLP78SnnC7gvGsM1KtxX7vCXTOyOg023n77EAz0tpJtYYc41Qq8oD8tQ/LDuTjhoyVWi1+3Iw+lUEw",
+	"nvWe5RgvfkQMoyiM4zBJQqJwtVr68FtKsCxLmSw/KVQJLKNSZgmaOF1C51tAQ/BAhYOVNsARQCcjmUm3",
+	"Y7/RY5HphA5+qdz4uSSz6/mxM7bvuFeGViIUfxp2UR3WT+2w84HYe8K6XcZACVHxt+gPih3fbdl+oIxi",
+	"pw2DntLfCn4zjt+i87ZF4OCxy87pZJmr1d3f3/5F7FmVIVtoZes0mQYB/8RaOVKO/2JRZDJGTpzhH5az",
+	"50nQI+ZFRrUkB2YaBJ7IyVpcM7O3mADTIus8KDJCSxCnFD/ATpcGpCrKyr2XUX1njDY11+dZzGruajWM",
+	"Ng1G13Ef9bnfKyxdqo38QklLvjB6IxOCDWYyARbgY14j1+bYF7Bn3ig+wK60yZv/FnJpLR9Qzf6reNQ2",
+	"T66zedK3+WdtIpkkpDwOECQalHaQ4oagIFNp1gqcBoxjshZcKi0Ysro0Mb2A4a3+2qTpdSZN+yZx4WlS",
+	"kJKWK2zRVratdKmSlwgZ2IJiLvg9JfIrHTfXHaqb54fqVnEPwAwsmQ0ZIObUZqgzO8A1SgUZOjIvYNq9",
+	"oseCYnafPKW6tmxynWXP0u8DmY2MCUqFG5QZRhn9H+06oc1n2DuyOtvQHdniApt6zdnogoyTTZ9FV909",
+	"7sfNHd00l2Ni3IZblx+qMqyMzqsObWp+QCoptFSuAm2sZY3z/63f2xQNJRDt+soMcNPmG86gslyQyMCW",
+	"olTrB+tDD5dbeDMiUPJJRTtYNpfLEBRtQVdkMIOuV0OOO4gIMEkoqerLAcKDbUqG0EJkCB+YaZyiWpOF",
+	"qMwLkM6He/Wg9FZ9Uvichc6lc5Tw5PA8NmgtuRPO4dvgcH3wTeMT/3is8hhDrlVO6gTQr5jTAaOTA1v1",
+	"cTahNw9eoqsbKs7kuiweyQyasAw6d/zeGyo8kWrr6tHh6ViXLI7tuX3PkTFc8b/KmqogrDA+zTvH+Bjs",
+	"l/lPZ9HaqfN4kjylp8jQcUoeK3vfPDloWkmTb9G0Cmgll9xJl0UcSW3/C3wdqW/H+TvCaclIzI7hPlT3",
+	"QZV5ROYSoGrw69dVMRpPaHoz+2FAr99Eg9E4mQxwejMbTMez2Wg6+mEaBIHwnu0RweANDlbzwc+Lp9f7",
+	"Qf9y+j2Xo/H+1SmOzTm+OnO7yvL3Bmm/78+6H1sN3mEQ7o7L4qjaeuJt/zCRKnPGkJPXM+GJx9ez32dT",
+	"4Qk0+WRc/86mYnHCrrqtHJX9uq89iXpkE6GQylVIDQCn+LpuX23fO9Uk+gZWmJ38KaOqKf6aTGh5Nr47",
+	"UQ8uDkzPn5uRWHwP2IsEhf3Gw/Kha2Nc1eZmS5pn9IgqMQS/YJqkqEvJKWMyEYrUucKGw+FaurSM/Fjn",
+	"QzyI5wfpOkfFUbv+jYdgaavTOn9/exgEm6WAF1nJMeEWwEUO4R+1/dAMGobPdiZjUpb6fAuMU4KxHxzR",
+	"3G63PlaPfW3Ww+ZdO/zr7U/vfv3wbjD2Az91ecZcnXRVPty+/+e7uyPVondAxcgf+QG/owtSWEgRiokf",
+	"+JO6YKRVfg+f2i8Ve75e142Uj0Bl720iwsMEVb3XfTb5eLoCdCLDFlrsvbPCzzbjC+SPV/gLXsLet4HF",
+	"VyvyuJ7mTyG0csP+MFntNBe8w0LdGntOdtRb/87JTnp71TnZaW9j+bbsTRD0doBzspN6Xi3zHM2uSxZA",
+	"iLVaybXwhMM154tohk+x2O/3+/8MAIfKAr+SEwAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -444,6 +470,14 @@ func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
 		res[pathToFile] = rawSpec
 	}
 
+	pathPrefix := path.Dir(pathToFile)
+
+	for rawPath, rawFunc := range externalRef0.PathToRawSpec(path.Join(pathPrefix, "./ipxer-webhook-attributes.v1.yaml")) {
+		if _, ok := res[rawPath]; ok {
+			// it is not possible to compare functions in golang, so always overwrite the old value
+		}
+		res[rawPath] = rawFunc
+	}
 	return res
 }
 
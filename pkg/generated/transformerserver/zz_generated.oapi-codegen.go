@@ -17,22 +17,20 @@ import (
 	"path"
 	"strings"
 
+	externalRef0 "github.com/alexandremahdhaoui/ipxer/pkg/generated/webhookattributes"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/oapi-codegen/runtime"
 	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
-// Defines values for Buildarch.
-const (
-	Arm32 Buildarch = "arm32"
-	Arm64 Buildarch = "arm64"
-	I386  Buildarch = "i386"
-	X8664 Buildarch = "x86_64"
-)
+// Attributes Attributes of the booting machine, shared by the resolver and the transformer webhooks. Attributes are versioned
+// by `version`: new optional attributes may be added to a version, whereas breaking changes bump it. Unknown
+// attributes are omitted.
+type Attributes = externalRef0.Attributes
 
 // Buildarch defines model for Buildarch.
-type Buildarch string
+type Buildarch = externalRef0.Buildarch
 
 // Error defines model for Error.
 type Error struct {
@@ -42,11 +40,11 @@ type Error struct {
 
 // TransformRequest defines model for TransformRequest.
 type TransformRequest struct {
-	Attributes *struct {
-		Buildarch Buildarch `json:"buildarch"`
-		Uuid      UUID      `json:"uuid"`
-	} `json:"attributes,omitempty"`
-	Content *string `json:"content,omitempty"`
+	// Attributes Attributes of the booting machine, shared by the resolver and the transformer webhooks. Attributes are versioned
+	// by `version`: new optional attributes may be added to a version, whereas breaking changes bump it. Unknown
+	// attributes are omitted.
+	Attributes *Attributes `json:"attributes,omitempty"`
+	Content    *string     `json:"content,omitempty"`
 }
 
 // UUID defines model for UUID.
@@ -402,24 +400,30 @@ func (sh *strictHandler) Transform(w http.ResponseWriter, r *http.Request, anyRo
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/7RW227jNhD9FWK6T4UsybbszeotaVPAQFsE2QQtGrjFWBpb3JVILkk58Qb+94KkL3Js",
-	"JGmQPikOhzPnDM9cHqGQjZKChDWQP4JCjQ1Z0v4XitW1bC35HyWZQnNluRSQw63g31pivCRh+ZyTZnLO",
-	"bEVMaTnnNcUQAXeGCm0FEQhsCPKOxwg0fWu5phJyq1uKwBQVNRhQWEva3f777rz3F/a+TzfftPdp+uMH",
-	"iMCulPNnrOZiAev12vkzSgoT0GZp6j6FFJaE9WSUqnmBDn/yxTgSj0AP2KiagmVJkGdpGkFDxuDCub/A",
-	"kjmYZGzEVE1oiBUVFV/ZSraacaFaC+su9A+a5pDDD8k+r0k4Ncml1lIHrIfJdGGuQxjnLUv7b8Pe72K/",
-	"FdjaSmr+ncodeKXlkpfElljzkjkD937Bc6Bj3oHP+Sbw1u1c6mbzt2ENN4aLBZMufx5H4Dx8G+dhl/Mv",
-	"Us94WZKI3AOxUjIhLatwSUyR9pGlYFYyLAoyhtmKG6bJyFYX9A7Ed/EDpextlLIupZuKthKkcoeV3aPx",
-	"3OayFeV7PBkzigpXyZ0g/EmM0duKanRYVBPhihtrZkgvSTNymHYKtXrFcIFcsBot6XegdivoQVHh0sdP",
-	"hQ7Mhm9jdiC/z6SXvCDWClwir3FW0//I60S02Lm90SiMK7lrMuoVrDp9V0tF2vJNw0fr//u01e6ar5x9",
-	"ocKeguYGwS7pdouHbTu0d7Lh5yJctLwuUReVT7JoG8jvgA/PxhDBw9n4n3EGEaBuhoPwHWcwPRoBEYRM",
-	"HfEIT/UIoQtBDlxY72njwIliEV5k95SnWO/n1V3wubefHuXk4BlCZz8ChtZqPtvO18OzWTclz8ljn7t1",
-	"BG3Ly5cu3N5Ofj7i4y9GnainGHV09KImIvBxukUD/cGQstH4Y4/OPs16/UE57GE2GveywXjcz/ofszRN",
-	"Idq/0wbUiVHvRspW2Vh4RJsF47ymBxSlJvYbVmWFsuUQQatryKGyVpk8SRbcVu0sLmST4Na82VonXD0E",
-	"MRxK+saNCm78jnN+Ndm2y83onLtp5mg2bp6KBUP2B80qKb+ynQ5Iu6Wo5gW5EuhAVlhUxAZxeoT0/v4+",
-	"Rn8cS71INndN8uvkp8vfP1/2BnEaV7apHVzLrc/y5OrPy+tT0SGCJWkT6PTjNE7dNalIoOKQwzBO4yFE",
-	"fmPzGkwed+va2itUBhU7nXrak9IlZhvBX92vj3enZbg3SfbL4Hoa5EjGXshy9Z+a1nNaP6rBE73KDdhu",
-	"i/KGbOZgPF0qB2H+nYq4s0sO26/fA15xyxntV7+XbPudlekl22FnF3nJNutM+edtRwHv6DUYnJHv+G3T",
-	"oF5BDlekfbZxn3n/vK7aceHEA7sDmK7X6/W/AQAA//+bYHC5qQwAAA==",
+	"H4sIAAAAAAAC/+xYXVPbyBL9K11z83RLluUPHKI3yCVVVN2kKAK7W5uwoSW1rQnSjDIzsnEo/fet0Zdl",
+	"rGBg2TeebEmt0316evqM+o6FMs2kIGE08+9YhgpTMqTKKxTrc5kbKi8i0qHimeFSMJ9dCv4jJ+ARCcPn",
+	"nBTIOZiYIFNyzhNymcO4NczQxMxhAlNifgfRYYp+5FxRxHyjcnKYDmNKsYrCGFL27b++HA3+xMHPq/rX",
+	"G7y7+u8b5jCzziyeNoqLBSuKwuLpTApdRTv1PPsTSmFImJJMliU8RBv/8Lu2JO4Y3WKaJVRZRsT8qec5",
+	"LCWtcWHhjzECGyZp40CWEGqCMKbwBtYyV8BFlhtWdEN/o2jOfPaf4Savw+qpHp4oJVUV63YyrZvzyo1F",
+	"m3qj58U+6sZ+KTA3sVT8J0Vt8JmSSx4RLDHhEVgDu34VckVHvwCfo9pxAzuXKq3/a0i51lwsQNr8lXFU",
+	"nCfP4zzpcv4gVcCjiIRjFwgiCUIaiHFJkJEqPUsBRgKGIWkNJuYaFGmZq5BegHjrv6I0fR6laZfSRUxN",
+	"CVLUxgor1CW3ucxF9BJLBjqj0O7kjhN+z8fB8zbVwfamOhV2c2MCmtSSFJCNqa1Qo9aAC+QCEjSkXoDa",
+	"paDbjEKbPt7numI2eR6zrfL7TGrJQ4Jc4BJ5gkFC/yKvHm+uhb1QKLTdcueks0ew6vRdJTNShtcNH015",
+	"936rbZuvDL5TaPpCs0LQJt008UDToUuQmp/1cGSM4kG/0GyeNQoTSGlsA0kxjLkgB3SMiiII1uVjW72J",
+	"XVwUUXmj9U8KVhTEUt5oFzq4qAiWpGxvoOirCNZwXV9e+yBoBbIMBhPAzUspriEgwCiyFCVgA+HAKiZF",
+	"qCFQhDc20jBGsSANQZ5mwI0Ll+JGyJX4KnA7CplyYyiy4rm9Fqg1mZ7k2NtgcNHkps6Ju6uQjsXgC5GS",
+	"6AH6hCk1GBs70JRQWCa7I+2P8RXkPIlQhfG+6ubZLalBvSyDTTq+HbcIhcNiqU11grjb9cWzXT6nZ3Zl",
+	"lO3x96qmbAFzDPvjTjHcBft49H4vmgPkLly4RvSDwA9DP4p8In8+v+71kyVobEnuOjurnzSe5lylK1St",
+	"A5rza6ud11kYcKl/AV+t1MPr/ITl1KQ4Jrtwn8v7IPI0IPUYoDzn0VYnZaPxhKYHs7cDOnwXDEbjaDLA",
+	"6cFsMB3PZqPp6O3U8zzmbB0JvcE7HMyPBh+u7g6LQfdy+pTL0bh40xdjvY+fXbmbzvJbjVQU3dPul9ZD",
+	"nY/udrna6a4OO+5uJhJ5ajH45HDGHHZ7OPs2mzKHoUon4+p3NmVXPbwqIdlp85WS3bHqkMZ8xoUpkWoA",
+	"W+KLSrBapesThS7BEnNj30eqo1LVwXcnMNxShYeWYpNxi9yRu73S5bDLy9P/Pasi23zVa9jTl/YWyKvc",
+	"vcrdq9y9yt2r3HU68hM8dfRwOeoVvUdsuH8gqpapHW80X1kYls2m2rvsKKFbFJEi+IhxFKPMuc2BSpjP",
+	"YmMy7Q+HC27iPHBDmQ6xMU8b6yrpbOfz6sKOLbguy+/o7LT5dK/HOHOpgNtysz3N7lqE3yv+cLHRBluv",
+	"CQ9JaOqGnGEYE4xdbyfS1WrlYvnYlWoxrN/Vw/+fvj/59PlkMHY9NzZpYsM13JTVfnr2x8l5n3fWqTs2",
+	"ckeuZ1+TGQnMOPPZxPXcSbUP4lIRhnft6LCw15msjgxWNUrap5FNTOOBOVujzC/9tb0xGbborLiqqpe0",
+	"OZbR+kkf0A9toJ0DT89380VXv5uxDwQ2jPsDzrHn/cpjazfcHgWUM6lHvGWNNmPIfbajzvhun+2kMxfb",
+	"ZzvtTJwetj3wvM4MZ5/tpJo+5GmKam0ViFSZbdxkvlxe5jCDC1s8rH3AroqiKP4eAKvDWlE1FwAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -459,6 +463,14 @@ func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
 		res[pathToFile] = rawSpec
 	}
 
+	pathPrefix := path.Dir(pathToFile)
+
+	for rawPath, rawFunc := range externalRef0.PathToRawSpec(path.Join(pathPrefix, "./ipxer-webhook-attributes.v1.yaml")) {
+		if _, ok := res[rawPath]; ok {
+			// it is not possible to compare functions in golang, so always overwrite the old value
+		}
+		res[rawPath] = rawFunc
+	}
 	return res
 }
 
@@ -4,7 +4,10 @@
 package v1alpha1
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net"
 	"strings"
 
 	"github.com/google/uuid"
@@ -15,11 +18,16 @@ import (
 )
 
 const (
-	Group   = "ipxer.cloud.alexandre.mahdhaoui.com"
+	Group   = "ipxe.cloud.alexandre.mahdhaoui.com"
 	Version = "v1alpha1"
 
 	UUIDPrefix      = "uuid"
+	MACPrefix       = "mac"
+	SerialPrefix    = "serial"
+	AssetPrefix     = "asset"
+	HostnamePrefix  = "hostname"
 	BuildarchPrefix = "buildarch"
+	PlatformPrefix  = "platform"
 )
 
 var (
@@ -54,6 +62,8 @@ func IsInternalLabel(key string) bool {
 	return strings.Contains(key, Group)
 }
 
+// UUIDLabelSelectors returns the UUIDs found in the keys of UUID label selectors, mapped to the values of the labels,
+// e.g. content names. The reverse map maps the values of the labels to their UUIDs.
 func UUIDLabelSelectors(labels map[string]string) (idNameMap map[uuid.UUID]string, reverse map[string]uuid.UUID, err error) {
 	idNameMap = make(map[uuid.UUID]string)
 	reverse = make(map[string]uuid.UUID)
@@ -68,8 +78,63 @@ func UUIDLabelSelectors(labels map[string]string) (idNameMap map[uuid.UUID]strin
 		}
 
 		idNameMap[id] = v
-		reverse[k] = id
+		reverse[v] = id
 	}
 
 	return idNameMap, reverse, nil
 }
+
+// ------------------------------------------------ SUBJECT SELECTORS ----------------------------------------------- //
+
+// NewMACLabelSelector returns the label selecting the subject booting with the specified MAC address, e.g.
+// `mac.ipxe.cloud.alexandre.mahdhaoui.com/aa-bb-cc-dd-ee-ff`.
+func NewMACLabelSelector(mac net.HardwareAddr) string {
+	return LabelSelector(strings.ReplaceAll(mac.String(), ":", "-"), MACPrefix)
+}
+
+// NewSerialLabelSelector returns the label selecting the subject reporting the specified SMBIOS serial number.
+func NewSerialLabelSelector(serial string) string {
+	return LabelSelector(hashLabelName(serial), SerialPrefix)
+}
+
+// NewAssetLabelSelector returns the label selecting the subject reporting the specified SMBIOS asset tag.
+func NewAssetLabelSelector(asset string) string {
+	return LabelSelector(hashLabelName(asset), AssetPrefix)
+}
+
+// NewHostnameLabelSelector returns the label selecting the subject with the specified hostname. Hostnames are not
+// case-sensitive.
+func NewHostnameLabelSelector(hostname string) string {
+	return LabelSelector(hashLabelName(strings.ToLower(hostname)), HostnamePrefix)
+}
+
+// LabelSelectorNames returns the names of the label selectors with the specified prefix, e.g. the normalized MAC
+// addresses of MAC label selectors.
+func LabelSelectorNames(labels map[string]string, prefix string) []string {
+	labelPrefix := LabelSelector("", prefix)
+
+	out := make([]string, 0)
+	for k := range labels {
+		if name, ok := strings.CutPrefix(k, labelPrefix); ok {
+			out = append(out, name)
+		}
+	}
+
+	return out
+}
+
+// LabelSelectorName returns the name of a label selector, i.e. the key of the label without its prefix.
+func LabelSelectorName(label string) string {
+	_, name, _ := strings.Cut(label, "/")
+
+	return name
+}
+
+// hashLabelName returns a valid label name identifying free-form values, such as serial numbers, which may be longer
+// than 63 characters or contain forbidden characters. Surrounding whitespaces are ignored, as SMBIOS strings are often
+// padded.
+func hashLabelName(value string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(value)))
+
+	return hex.EncodeToString(sum[:16])
+}
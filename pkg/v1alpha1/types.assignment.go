@@ -19,6 +19,11 @@ var (
 	Arm32BuildarchLabelSelector = LabelSelector(Arm32.String(), BuildarchPrefix)
 	Arm64BuildarchLabelSelector = LabelSelector(Arm64.String(), BuildarchPrefix)
 
+	// PlatformList Label Selector
+
+	PCBIOSPlatformLabelSelector = LabelSelector(PCBIOS.String(), PlatformPrefix)
+	EFIPlatformLabelSelector    = LabelSelector(EFI.String(), PlatformPrefix)
+
 	// datastructures
 
 	AllowedBuildarchList = []Buildarch{Arm32, Arm64, I386, X8664}
@@ -33,11 +38,27 @@ var (
 	}()
 
 	buildarchToLabel = map[Buildarch]string{
-		Arm32: Arm64BuildarchLabelSelector,
+		Arm32: Arm32BuildarchLabelSelector,
 		Arm64: Arm64BuildarchLabelSelector,
 		I386:  I386BuildarchLabelSelector,
 		X8664: X8664BuildarchLabelSelector,
 	}
+
+	AllowedPlatformList = []Platform{EFI, PCBIOS}
+	AllowedPlatform     = func() map[Platform]any {
+		out := make(map[Platform]any)
+
+		for _, p := range AllowedPlatformList {
+			out[p] = nil
+		}
+
+		return out
+	}()
+
+	platformToLabel = map[Platform]string{
+		EFI:    EFIPlatformLabelSelector,
+		PCBIOS: PCBIOSPlatformLabelSelector,
+	}
 )
 
 type Buildarch string
@@ -59,33 +80,71 @@ const (
 	Arm64 Buildarch = "arm64"
 )
 
-// apiVersion: ipxer.cloud.alexandre.mahdhaoui.com/v1alpha1
+// Platform is the firmware platform, as reported by iPXE's `${platform}`.
+type Platform string
+
+func (p Platform) String() string {
+	return string(p)
+}
+
+const (
+	// PlatformList
+
+	// PCBIOS - pcbios	Legacy BIOS
+	PCBIOS Platform = "pcbios"
+	// EFI - efi	UEFI
+	EFI Platform = "efi"
+)
+
+// apiVersion: ipxe.cloud.alexandre.mahdhaoui.com/v1alpha1
 // kind: Assignment
 // metadata:
 //   name: your-assignment
 //   labels:
-//     ipxer.cloud.alexandre.mahdhaoui.com/buildarch: arm64
-//     uuid.ipxer.cloud.alexandre.mahdhaoui.com/c4a94672-05a1-4eda-a186-b4aa4544b146: ""
-//     uuid.ipxer.cloud.alexandre.mahdhaoui.com/3f5f3c39-584e-4c7c-b6ff-137e1aaa7175: ""
+//     buildarch.ipxe.cloud.alexandre.mahdhaoui.com/arm64: ""
+//     platform.ipxe.cloud.alexandre.mahdhaoui.com/efi: ""
+//     uuid.ipxe.cloud.alexandre.mahdhaoui.com/c4a94672-05a1-4eda-a186-b4aa4544b146: ""
+//     uuid.ipxe.cloud.alexandre.mahdhaoui.com/3f5f3c39-584e-4c7c-b6ff-137e1aaa7175: ""
+//     mac.ipxe.cloud.alexandre.mahdhaoui.com/aa-bb-cc-dd-ee-ff: ""
+//     # serial numbers, asset tags and hostnames are hashed.
+//     serial.ipxe.cloud.alexandre.mahdhaoui.com/3c2f7a16d2a0c5b6f8e1a4d9b7c0e2f1: ""
 // spec:
 //   # subjectSelectors map[string][]string
 //   # the specified labels selects subjects that can iPXE boot the selected profile below.
 //   subjectSelectors:
 //     buildarch: # please note only 1 buildarch mat be specified at a time.
 //       - arm64
-//     serialNumber:
-//       - c4a94672-05a1-4eda-a186-b4aa4544b146
-//     uuid:
+//     platform: # unspecified implies any platform.
+//       - efi
+//     uuidList:
 //       - 47c6da67-7477-4970-aa03-84e48ff4f6ad
 //       - 3f5f3c39-584e-4c7c-b6ff-137e1aaa7175
+//     macList:
+//       - aa:bb:cc:dd:ee:ff
+//     serialList:
+//       - CZ2D2507KF
+//     assetList:
+//       - ASSET-0042
+//     hostnameList:
+//       - node-0.example.com
 //   # profileName string
 //   profileName: 819f1859-a669-410b-adfc-d0bc128e2d7a
 // status:
-//   conditions: []
+//   conditions:
+//     - type: Ready
+//       status: "False"
+//       reason: ProfileNotFound
+//       message: profile "819f1859-a669-410b-adfc-d0bc128e2d7a" does not exist
 
 type (
 	//+kubebuilder:object:root=true
-	//+kubebuilder:subresources:status
+	//+kubebuilder:subresource:status
+	//+kubebuilder:printcolumn:name="Profile",type=string,JSONPath=`.spec.profileName`
+	//+kubebuilder:printcolumn:name="Default",type=boolean,JSONPath=`.spec.isDefault`
+	//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+	//+kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+	//+kubebuilder:printcolumn:name="Message",type=string,priority=1,JSONPath=`.status.conditions[?(@.type=="Ready")].message`
+	//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 	Assignment struct {
 		metav1.TypeMeta   `json:",inline"`
@@ -110,11 +169,27 @@ type (
 		IsDefault        bool             `json:"isDefault"`
 	}
 
-	AssignmentStatus struct{}
+	AssignmentStatus struct {
+		// Conditions report whether the assignment can be used to boot its subjects, i.e. whether its profile exists
+		// and its subject selectors do not conflict with other assignments.
+		// +listType=map
+		// +listMapKey=type
+		Conditions []metav1.Condition `json:"conditions,omitempty"`
+	}
 
 	SubjectSelectors struct {
 		BuildarchList []Buildarch `json:"buildarch"`
+		PlatformList  []Platform  `json:"platform,omitempty"`
 		UUIDList      []string    `json:"uuidList"`
+
+		// MACList selects subjects by the MAC address of the interface they boot from, e.g. `aa:bb:cc:dd:ee:ff`.
+		MACList []string `json:"macList,omitempty"`
+		// SerialList selects subjects by the serial number reported by their SMBIOS.
+		SerialList []string `json:"serialList,omitempty"`
+		// AssetList selects subjects by the asset tag reported by their SMBIOS.
+		AssetList []string `json:"assetList,omitempty"`
+		// HostnameList selects subjects by the hostname they received by DHCP.
+		HostnameList []string `json:"hostnameList,omitempty"`
 	}
 )
 
@@ -143,3 +218,21 @@ func (a *Assignment) GetBuildarchList() []Buildarch {
 func (a *Assignment) SetBuildarch(buildarch Buildarch) {
 	a.Labels[buildarchToLabel[buildarch]] = ""
 }
+
+func (a *Assignment) GetPlatformList() []Platform {
+	out := make([]Platform, 0)
+
+	if _, ok := a.Labels[EFIPlatformLabelSelector]; ok {
+		out = append(out, EFI)
+	}
+
+	if _, ok := a.Labels[PCBIOSPlatformLabelSelector]; ok {
+		out = append(out, PCBIOS)
+	}
+
+	return out
+}
+
+func (a *Assignment) SetPlatform(platform Platform) {
+	a.Labels[platformToLabel[platform]] = ""
+}
@@ -0,0 +1,65 @@
+package v1alpha1
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ClientArch is the client system architecture sent by PXE clients before iPXE is loaded, either through DHCP option 93
+// (RFC 4578) or the PXE vendor class identifier. Values are registered by IANA under "Processor Architecture Types".
+type ClientArch uint16
+
+const (
+	ClientArchX86BIOS  ClientArch = 0
+	ClientArchI386EFI  ClientArch = 6
+	ClientArchX8664EFI ClientArch = 7
+	ClientArchEFIBC    ClientArch = 9 // EFI byte code; in practice sent by x86_64 UEFI firmwares.
+	ClientArchArm32EFI ClientArch = 10
+	ClientArchArm64EFI ClientArch = 11
+
+	// UEFI HTTP boot clients are not supported: they expect an http(s) URI to the bootloader and the HTTPClient vendor
+	// class in the offer, while bootloaders are only served over TFTP.
+	ClientArchI386EFIHTTP  ClientArch = 15
+	ClientArchX8664EFIHTTP ClientArch = 16
+	ClientArchArm32EFIHTTP ClientArch = 18
+	ClientArchArm64EFIHTTP ClientArch = 19
+)
+
+type buildarchAndPlatform struct {
+	buildarch Buildarch
+	platform  Platform
+}
+
+var clientArchToBuildarchAndPlatform = map[ClientArch]buildarchAndPlatform{ //nolint:gochecknoglobals
+	ClientArchX86BIOS:  {buildarch: I386, platform: PCBIOS},
+	ClientArchI386EFI:  {buildarch: I386, platform: EFI},
+	ClientArchX8664EFI: {buildarch: X8664, platform: EFI},
+	ClientArchEFIBC:    {buildarch: X8664, platform: EFI},
+	ClientArchArm32EFI: {buildarch: Arm32, platform: EFI},
+	ClientArchArm64EFI: {buildarch: Arm64, platform: EFI},
+}
+
+// BuildarchAndPlatform returns the buildarch and firmware platform of the iPXE bootloader to serve to the client, i.e.
+// the values iPXE reports as `${buildarch}` and `${platform}` once loaded. It returns false if the client architecture
+// is not supported.
+func (c ClientArch) BuildarchAndPlatform() (Buildarch, Platform, bool) {
+	v, ok := clientArchToBuildarchAndPlatform[c]
+
+	return v.buildarch, v.platform, ok
+}
+
+// ClientArchFromVendorClass parses the client architecture advertised in a PXE vendor class identifier (DHCP option
+// 60), e.g. "PXEClient:Arch:00007:UNDI:003016".
+func ClientArchFromVendorClass(vendorClass string) (ClientArch, bool) {
+	fields := strings.Split(vendorClass, ":")
+	if len(fields) < 3 || fields[1] != "Arch" {
+		return 0, false
+	}
+
+	arch, err := strconv.ParseUint(fields[2], 10, 16)
+	if err != nil {
+		return 0, false
+	}
+
+	return ClientArch(arch), true
+}
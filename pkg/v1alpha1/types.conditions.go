@@ -0,0 +1,27 @@
+package v1alpha1
+
+// Condition types reported in the status of Assignments and Profiles.
+const (
+	// ConditionReady is true when the resource can be used to iPXE boot its subjects.
+	ConditionReady = "Ready"
+	// ConditionDegraded is true when the resource cannot be used; its reason explains why.
+	ConditionDegraded = "Degraded"
+)
+
+// Condition reasons reported in the status of Assignments and Profiles.
+const (
+	ReasonReconciled = "Reconciled"
+	// ReasonInvalidSpec is reported by Assignments and Profiles.
+	ReasonInvalidSpec = "InvalidSpec"
+
+	// Assignment
+
+	ReasonProfileNotFound  = "ProfileNotFound"
+	ReasonSelectorConflict = "SelectorConflict"
+	ReasonDefaultConflict  = "DefaultConflict"
+
+	// Profile
+
+	ReasonTemplateInvalid     = "TemplateInvalid"
+	ReasonContentUnresolvable = "ContentUnresolvable"
+)
@@ -48,7 +48,11 @@ func init() {
 //     ignitionFile: 445a4753-3d59-4429-8cea-7db9febdeca
 
 //+kubebuilder:object:root=true
-//+kubebuilder:subresources:status
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+//+kubebuilder:printcolumn:name="Message",type=string,priority=1,JSONPath=`.status.conditions[?(@.type=="Ready")].message`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 type Profile struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -65,7 +69,16 @@ type ProfileSpec struct {
 	AdditionalContent []AdditionalContent `json:"additionalContent,omitempty"`
 }
 
-type ProfileStatus struct{}
+type ProfileStatus struct {
+	// Conditions report whether the profile can be rendered, i.e. whether its template parses and every additional
+	// content can be resolved.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ResolvedRevisions are the commits the git additional contents resolve to, keyed by the name of the content.
+	ResolvedRevisions map[string]string `json:"resolvedRevisions,omitempty"`
+}
 
 //+kubebuilder:object:root=true
 
@@ -87,6 +100,15 @@ type (
 		// field will be templated as 'https://your.ipxer.com/config/YOUR_CONFIG_UUID'.
 		Exposed bool `json:"exposed,omitempty"`
 
+		// Template when set to true renders the resolved content as a template before its post transformations. The
+		// template can reference the facts about the booting machine, e.g. `\{\{ .Machine.Hostname }}`, and the other
+		// contents, e.g. `\{\{ .cloudInit }}`. Exposed contents are templated as their URL.
+		Template bool `json:"template,omitempty"`
+
+		// CacheTTL is the duration the resolved and transformed content is cached for, e.g. `5m`. The content is not
+		// cached by default. Changes to the content invalidate its cache.
+		CacheTTL *metav1.Duration `json:"cacheTTL,omitempty"`
+
 		// PostTransformations is a list of Transformers
 		PostTransformations []Transformer `json:"postTransformations"`
 
@@ -100,6 +122,23 @@ type (
 		// Webhook is a source type used to allow fetching configurations from any kind of sources, e.g. from an S3
 		// bucket.
 		Webhook *WebhookConfig `json:"webhook,omitempty"`
+
+		// NotFoundAsEmpty when set to true resolves the content as empty if the webhook responds 404 Not Found, instead
+		// of failing. It requires a webhook.
+		NotFoundAsEmpty bool `json:"notFoundAsEmpty,omitempty"`
+
+		// HTTP fetches the content from a plain HTTP(S) URL, e.g. from an artifact server.
+		HTTP *HTTPConfig `json:"http,omitempty"`
+
+		// S3 reads the content from an S3-compatible object storage, e.g. AWS S3 or MinIO.
+		S3 *S3Config `json:"s3,omitempty"`
+
+		// OCI pulls the content from a layer of an OCI artifact, e.g. pushed to a container registry with oras.
+		OCI *OCIConfig `json:"oci,omitempty"`
+
+		// Git reads the content from a file of a Git repository, so changes to the content go through code review. The
+		// commit the content resolves to is reported in the status of the profile.
+		Git *GitConfig `json:"git,omitempty"`
 	}
 
 	Transformer struct {
@@ -116,13 +155,148 @@ type (
 		// JSONPath to the desired content in the resource using jsonpath notation. E.g. `.data.private\.key`
 		// TODO: Validate this jsonpath in the webhook.
 		JSONPath string `json:"jsonpath"`
+
+		// Encoding of the values, either `raw` or `base64`. By default, the data of core/v1 Secrets are decoded from
+		// base64 and other values are read as is.
+		// +kubebuilder:validation:Enum=raw;base64
+		Encoding string `json:"encoding,omitempty"`
+
+		// JoinSeparator joins the values with this separator when the JSONPath matches several values, e.g. `\n`.
+		// Matching several values is an error if it is not set.
+		JoinSeparator *string `json:"joinSeparator,omitempty"`
 	}
 
 	WebhookConfig struct {
+		// URL of the webhook, e.g. `https://webhook.example.com/configs?env=prod`. The scheme must be `http` or `https`,
+		// and defaults to `https` if omitted. The query of the URL is sent along the selectors of the machine.
 		URL string `json:"url"`
 
+		// Headers are static headers sent with each call to the webhook, e.g. a tenant identifier.
+		Headers map[string]string `json:"headers,omitempty"`
+
+		// QueryParams are static query parameters sent with each call to the webhook. They do not override the query
+		// parameters set by ipxer, e.g. `uuid` or `buildarch`.
+		QueryParams map[string]string `json:"queryParams,omitempty"`
+
 		MTLSObjectRef      *MTLSObjectRef      `json:"mTLSRef,omitempty"`
 		BasicAuthObjectRef *BasicAuthObjectRef `json:"basicAuthRef,omitempty"`
+
+		// BearerTokenObjectRef references the token sent as a bearer token. It is exclusive with basicAuthRef.
+		BearerTokenObjectRef *BearerTokenObjectRef `json:"bearerTokenRef,omitempty"`
+
+		// TimeoutSeconds of each call to the webhook. Defaults to 10.
+		// +kubebuilder:validation:Minimum=1
+		TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+		// Retries of a call failing with a connection error or a 5xx status code, with an exponential backoff. Defaults
+		// to 2.
+		// +kubebuilder:validation:Minimum=0
+		Retries *int32 `json:"retries,omitempty"`
+
+		// MaxBodySizeBytes is the maximum size of the response of the webhook. Defaults to 16MiB.
+		// +kubebuilder:validation:Minimum=1
+		MaxBodySizeBytes *int64 `json:"maxBodySizeBytes,omitempty"`
+	}
+
+	HTTPConfig struct {
+		// URL of the content, e.g. `https://artifacts.example.com/fcos/config.bu`.
+		URL string `json:"url"`
+
+		// Checksum of the content formatted as `<algorithm>:<hex digest>`, where the algorithm is `sha256` or `sha512`.
+		// The content is rejected if its checksum does not match.
+		Checksum string `json:"checksum,omitempty"`
+
+		MTLSObjectRef      *MTLSObjectRef      `json:"mTLSRef,omitempty"`
+		BasicAuthObjectRef *BasicAuthObjectRef `json:"basicAuthRef,omitempty"`
+
+		// MaxBodySizeBytes is the maximum size of the content. Defaults to 128MiB.
+		// +kubebuilder:validation:Minimum=1
+		MaxBodySizeBytes *int64 `json:"maxBodySizeBytes,omitempty"`
+	}
+
+	S3Config struct {
+		// Bucket holding the content.
+		Bucket string `json:"bucket"`
+
+		// Key of the content in the bucket. The key is a template which can reference the facts about the booting
+		// machine, e.g. `ignition/\{\{ .Machine.UUID }}.ign`.
+		Key string `json:"key"`
+
+		// Region of the bucket. Defaults to `us-east-1`.
+		Region string `json:"region,omitempty"`
+
+		// Endpoint of an S3-compatible object storage, e.g. `https://minio.example.com:9000`. Defaults to AWS S3.
+		Endpoint string `json:"endpoint,omitempty"`
+
+		// ForcePathStyle addresses objects as `<endpoint>/<bucket>/<key>` instead of using virtual hosted buckets, as
+		// required by most S3-compatible object storages, e.g. MinIO.
+		ForcePathStyle bool `json:"forcePathStyle,omitempty"`
+
+		// CredentialsRef references the access key used to sign requests. Requests are anonymous if it is not set.
+		CredentialsRef *S3CredentialsObjectRef `json:"credentialsRef,omitempty"`
+
+		// MaxBodySizeBytes is the maximum size of the object. Defaults to 128MiB.
+		// +kubebuilder:validation:Minimum=1
+		MaxBodySizeBytes *int64 `json:"maxBodySizeBytes,omitempty"`
+	}
+
+	S3CredentialsObjectRef struct {
+		ResourceRef `json:",inline"`
+
+		// AccessKeyIDJSONPath to the access key ID in the resource using jsonpath notation. E.g. `.data.accessKeyID`
+		AccessKeyIDJSONPath string `json:"accessKeyIDJSONPath"`
+
+		// SecretAccessKeyJSONPath to the secret access key in the resource using jsonpath notation. E.g.
+		// `.data.secretAccessKey`
+		SecretAccessKeyJSONPath string `json:"secretAccessKeyJSONPath"`
+
+		// SessionTokenJSONPath to the optional session token in the resource using jsonpath notation.
+		SessionTokenJSONPath string `json:"sessionTokenJSONPath,omitempty"`
+	}
+
+	OCIConfig struct {
+		// Reference of the artifact formatted as `<registry>/<repository>[:<tag>][@<digest>]`, e.g.
+		// `ghcr.io/example/ignition:v1.2.0`. The tag defaults to `latest`, and is ignored if the digest is specified.
+		Reference string `json:"reference"`
+
+		// MediaType of the layer holding the content, e.g. `application/vnd.coreos.ignition+json`. The artifact must
+		// have exactly one layer of this media type.
+		MediaType string `json:"mediaType"`
+
+		// PlainHTTP pulls the artifact over HTTP instead of HTTPS, e.g. from a local registry.
+		PlainHTTP bool `json:"plainHTTP,omitempty"`
+
+		// CredentialsRef references the credentials used to authenticate to the registry. The artifact is pulled
+		// anonymously if it is not set.
+		CredentialsRef *BasicAuthObjectRef `json:"credentialsRef,omitempty"`
+	}
+
+	GitConfig struct {
+		// URL of the repository, e.g. `https://github.com/example/configs.git` or `git@github.com:example/configs.git`.
+		URL string `json:"url"`
+
+		// Ref is the branch, the tag or the commit the file is read at. Defaults to `HEAD`, i.e. the default branch.
+		Ref string `json:"ref,omitempty"`
+
+		// Path of the file in the repository, e.g. `fcos/worker.bu`.
+		Path string `json:"path"`
+
+		// BasicAuthObjectRef references the credentials of HTTPS repositories, e.g. a username and an access token.
+		BasicAuthObjectRef *BasicAuthObjectRef `json:"basicAuthRef,omitempty"`
+
+		// SSHKeyObjectRef references the private key of SSH repositories.
+		SSHKeyObjectRef *SSHKeyObjectRef `json:"sshKeyRef,omitempty"`
+	}
+
+	SSHKeyObjectRef struct {
+		ResourceRef `json:",inline"`
+
+		// PrivateKeyJSONPath to the private key in the resource using jsonpath notation. E.g. `.data.ssh-privatekey`
+		PrivateKeyJSONPath string `json:"privateKeyJSONPath"`
+
+		// KnownHostsJSONPath to the known hosts the host key of the server is verified against, using jsonpath
+		// notation. E.g. `.data.known_hosts`
+		KnownHostsJSONPath string `json:"knownHostsJSONPath"`
 	}
 
 	BasicAuthObjectRef struct {
@@ -135,6 +309,13 @@ type (
 		PasswordJSONPath string `json:"passwordJSONPath"`
 	}
 
+	BearerTokenObjectRef struct {
+		ResourceRef `json:",inline"`
+
+		// TokenJSONPath to the desired content in the resource using jsonpath notation. E.g. `.data.token`
+		TokenJSONPath string `json:"tokenJSONPath"`
+	}
+
 	MTLSObjectRef struct {
 		ResourceRef `json:",inline"`
 
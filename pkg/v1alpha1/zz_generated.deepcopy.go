@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -34,10 +35,15 @@ func (in *AdditionalContent) DeepCopyInto(out *AdditionalContent) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CacheTTL != nil {
+		in, out := &in.CacheTTL, &out.CacheTTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.ObjectRef != nil {
 		in, out := &in.ObjectRef, &out.ObjectRef
 		*out = new(ObjectRef)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Inline != nil {
 		in, out := &in.Inline, &out.Inline
@@ -49,6 +55,26 @@ func (in *AdditionalContent) DeepCopyInto(out *AdditionalContent) {
 		*out = new(WebhookConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3Config)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(OCIConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalContent.
@@ -67,7 +93,7 @@ func (in *Assignment) DeepCopyInto(out *Assignment) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Assignment.
@@ -139,6 +165,13 @@ func (in *AssignmentSpec) DeepCopy() *AssignmentSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AssignmentStatus) DeepCopyInto(out *AssignmentStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssignmentStatus.
@@ -167,6 +200,77 @@ func (in *BasicAuthObjectRef) DeepCopy() *BasicAuthObjectRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BearerTokenObjectRef) DeepCopyInto(out *BearerTokenObjectRef) {
+	*out = *in
+	out.ResourceRef = in.ResourceRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BearerTokenObjectRef.
+func (in *BearerTokenObjectRef) DeepCopy() *BearerTokenObjectRef {
+	if in == nil {
+		return nil
+	}
+	out := new(BearerTokenObjectRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitConfig) DeepCopyInto(out *GitConfig) {
+	*out = *in
+	if in.BasicAuthObjectRef != nil {
+		in, out := &in.BasicAuthObjectRef, &out.BasicAuthObjectRef
+		*out = new(BasicAuthObjectRef)
+		**out = **in
+	}
+	if in.SSHKeyObjectRef != nil {
+		in, out := &in.SSHKeyObjectRef, &out.SSHKeyObjectRef
+		*out = new(SSHKeyObjectRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitConfig.
+func (in *GitConfig) DeepCopy() *GitConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GitConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPConfig) DeepCopyInto(out *HTTPConfig) {
+	*out = *in
+	if in.MTLSObjectRef != nil {
+		in, out := &in.MTLSObjectRef, &out.MTLSObjectRef
+		*out = new(MTLSObjectRef)
+		**out = **in
+	}
+	if in.BasicAuthObjectRef != nil {
+		in, out := &in.BasicAuthObjectRef, &out.BasicAuthObjectRef
+		*out = new(BasicAuthObjectRef)
+		**out = **in
+	}
+	if in.MaxBodySizeBytes != nil {
+		in, out := &in.MaxBodySizeBytes, &out.MaxBodySizeBytes
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPConfig.
+func (in *HTTPConfig) DeepCopy() *HTTPConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MTLSObjectRef) DeepCopyInto(out *MTLSObjectRef) {
 	*out = *in
@@ -183,10 +287,35 @@ func (in *MTLSObjectRef) DeepCopy() *MTLSObjectRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCIConfig) DeepCopyInto(out *OCIConfig) {
+	*out = *in
+	if in.CredentialsRef != nil {
+		in, out := &in.CredentialsRef, &out.CredentialsRef
+		*out = new(BasicAuthObjectRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIConfig.
+func (in *OCIConfig) DeepCopy() *OCIConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OCIConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectRef) DeepCopyInto(out *ObjectRef) {
 	*out = *in
 	out.ResourceRef = in.ResourceRef
+	if in.JoinSeparator != nil {
+		in, out := &in.JoinSeparator, &out.JoinSeparator
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectRef.
@@ -205,7 +334,7 @@ func (in *Profile) DeepCopyInto(out *Profile) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Profile.
@@ -283,6 +412,20 @@ func (in *ProfileSpec) DeepCopy() *ProfileSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProfileStatus) DeepCopyInto(out *ProfileStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResolvedRevisions != nil {
+		in, out := &in.ResolvedRevisions, &out.ResolvedRevisions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfileStatus.
@@ -310,6 +453,63 @@ func (in *ResourceRef) DeepCopy() *ResourceRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3Config) DeepCopyInto(out *S3Config) {
+	*out = *in
+	if in.CredentialsRef != nil {
+		in, out := &in.CredentialsRef, &out.CredentialsRef
+		*out = new(S3CredentialsObjectRef)
+		**out = **in
+	}
+	if in.MaxBodySizeBytes != nil {
+		in, out := &in.MaxBodySizeBytes, &out.MaxBodySizeBytes
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Config.
+func (in *S3Config) DeepCopy() *S3Config {
+	if in == nil {
+		return nil
+	}
+	out := new(S3Config)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3CredentialsObjectRef) DeepCopyInto(out *S3CredentialsObjectRef) {
+	*out = *in
+	out.ResourceRef = in.ResourceRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3CredentialsObjectRef.
+func (in *S3CredentialsObjectRef) DeepCopy() *S3CredentialsObjectRef {
+	if in == nil {
+		return nil
+	}
+	out := new(S3CredentialsObjectRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHKeyObjectRef) DeepCopyInto(out *SSHKeyObjectRef) {
+	*out = *in
+	out.ResourceRef = in.ResourceRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHKeyObjectRef.
+func (in *SSHKeyObjectRef) DeepCopy() *SSHKeyObjectRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHKeyObjectRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubjectSelectors) DeepCopyInto(out *SubjectSelectors) {
 	*out = *in
@@ -318,11 +518,36 @@ func (in *SubjectSelectors) DeepCopyInto(out *SubjectSelectors) {
 		*out = make([]Buildarch, len(*in))
 		copy(*out, *in)
 	}
+	if in.PlatformList != nil {
+		in, out := &in.PlatformList, &out.PlatformList
+		*out = make([]Platform, len(*in))
+		copy(*out, *in)
+	}
 	if in.UUIDList != nil {
 		in, out := &in.UUIDList, &out.UUIDList
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.MACList != nil {
+		in, out := &in.MACList, &out.MACList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SerialList != nil {
+		in, out := &in.SerialList, &out.SerialList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AssetList != nil {
+		in, out := &in.AssetList, &out.AssetList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostnameList != nil {
+		in, out := &in.HostnameList, &out.HostnameList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubjectSelectors.
@@ -358,6 +583,20 @@ func (in *Transformer) DeepCopy() *Transformer {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WebhookConfig) DeepCopyInto(out *WebhookConfig) {
 	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.QueryParams != nil {
+		in, out := &in.QueryParams, &out.QueryParams
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.MTLSObjectRef != nil {
 		in, out := &in.MTLSObjectRef, &out.MTLSObjectRef
 		*out = new(MTLSObjectRef)
@@ -368,6 +607,26 @@ func (in *WebhookConfig) DeepCopyInto(out *WebhookConfig) {
 		*out = new(BasicAuthObjectRef)
 		**out = **in
 	}
+	if in.BearerTokenObjectRef != nil {
+		in, out := &in.BearerTokenObjectRef, &out.BearerTokenObjectRef
+		*out = new(BearerTokenObjectRef)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxBodySizeBytes != nil {
+		in, out := &in.MaxBodySizeBytes, &out.MaxBodySizeBytes
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookConfig.